@@ -0,0 +1,400 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// DownloadClient is the subset of the BackupRecoveryV1 operations used by Cache.
+type DownloadClient interface {
+	DownloadAgentWithContext(ctx context.Context, downloadAgentOptions *backuprecoveryv1.DownloadAgentOptions) (result io.ReadCloser, response *core.DetailedResponse, err error)
+}
+
+var _ DownloadClient = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// VersionClient is the subset of the management SRE API used by ClusterVersion.
+type VersionClient interface {
+	GetClustersInfoWithContext(ctx context.Context, getClustersInfoOptions *backuprecoveryv1.GetClustersInfoOptions) (result *backuprecoveryv1.ClusterDetails, response *core.DetailedResponse, err error)
+}
+
+var _ VersionClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// ClusterVersion returns a Cache.ServerVersion that reads the software version of the clusters from the management
+// SRE API. It fails when the clusters do not all run the same version, since the agent they distribute would then
+// depend on the cluster serving the download.
+func ClusterVersion(client VersionClient) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		details, _, err := client.GetClustersInfoWithContext(ctx, &backuprecoveryv1.GetClustersInfoOptions{})
+		if err != nil {
+			return "", fmt.Errorf("getting clusters: %w", err)
+		}
+		versions := map[string]bool{}
+		if details != nil {
+			for _, cluster := range details.CohesityClusters {
+				if version := helpers.Deref(cluster.CurrentVersion); version != "" {
+					versions[version] = true
+				}
+			}
+		}
+		if len(versions) != 1 {
+			names := make([]string, 0, len(versions))
+			for version := range versions {
+				names = append(names, version)
+			}
+			sort.Strings(names)
+			return "", fmt.Errorf("expected one cluster software version, found %d: %s", len(names), strings.Join(names, ", "))
+		}
+		for version := range versions {
+			return version, nil
+		}
+		return "", nil
+	}
+}
+
+// PackageKey identifies an agent package.
+type PackageKey struct {
+	// Platform is one of the DownloadAgentOptions_Platform_* constants.
+	Platform string `json:"platform"`
+
+	// PackageType is one of the LinuxAgentParams_PackageType_* constants. It is empty for Windows.
+	PackageType string `json:"packageType,omitempty"`
+
+	// Version is the agent version the service distributes, which follows the cluster software version.
+	Version string `json:"version"`
+}
+
+// path returns the relative directory of the package, which is also its URL path in the Handler.
+func (key PackageKey) path() string {
+	packageType := key.PackageType
+	if packageType == "" {
+		packageType = "default"
+	}
+	return path.Join(key.Version, key.Platform, packageType)
+}
+
+func (key PackageKey) validate() error {
+	for _, part := range []string{key.Platform, key.PackageType, key.Version} {
+		if strings.ContainsAny(part, `/\`) || part == "." || part == ".." {
+			return fmt.Errorf("invalid package key %+v", key)
+		}
+	}
+	if key.Platform == "" || key.Version == "" {
+		return fmt.Errorf("invalid package key %+v: platform and version are required", key)
+	}
+	return nil
+}
+
+// Package describes a cached package.
+type Package struct {
+	Key       PackageKey `json:"key"`
+	FileName  string     `json:"fileName"`
+	Size      int64      `json:"size"`
+	SHA256    string     `json:"sha256"`
+	FetchedAt time.Time  `json:"fetchedAt"`
+}
+
+// ErrChecksumMismatch is returned when a cached package does not match its recorded checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrVersionMismatch is returned when a package is fetched for a version the service does not distribute.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+const (
+	packageFile  = "package"
+	metadataFile = "package.json"
+)
+
+// Cache stores agent packages on disk. A package is complete once its metadata file exists; both the package and
+// the metadata are written to temporary files and renamed into place, so readers never see a partial package.
+type Cache struct {
+	client   DownloadClient
+	tenantID string
+	dir      string
+
+	// FetchOnMiss makes the Handler download packages that are not cached yet. Defaults to false, so a mirror only
+	// serves what was fetched explicitly.
+	FetchOnMiss bool
+
+	// ServerVersion returns the agent version the service currently distributes, e.g. ClusterVersion. The download
+	// API takes no version, so a package is only fetched for a key of this version. Packages cannot be fetched
+	// while it is nil.
+	ServerVersion func(ctx context.Context) (string, error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mutex    sync.Mutex
+	locks    map[PackageKey]*sync.Mutex
+	verified map[PackageKey]string
+}
+
+// NewCache : Instantiate Cache. The client may be nil for a read-only mirror of an existing directory.
+func NewCache(client DownloadClient, tenantID string, dir string) *Cache {
+	return &Cache{
+		client:   client,
+		tenantID: tenantID,
+		dir:      dir,
+		Now:      time.Now,
+		locks:    map[PackageKey]*sync.Mutex{},
+		verified: map[PackageKey]string{},
+	}
+}
+
+func (cache *Cache) lock(key PackageKey) func() {
+	cache.mutex.Lock()
+	lock, ok := cache.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		cache.locks[key] = lock
+	}
+	cache.mutex.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// Lookup returns the cached package, or nil when it is not cached. The checksum of the package file is verified the
+// first time a package is looked up by this cache.
+func (cache *Cache) Lookup(key PackageKey) (*Package, error) {
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	defer cache.lock(key)()
+	return cache.lookup(key)
+}
+
+func (cache *Cache) lookup(key PackageKey) (*Package, error) {
+	dir := filepath.Join(cache.dir, filepath.FromSlash(key.path()))
+	pkg := &Package{}
+	if found, err := helpers.LoadJSON(filepath.Join(dir, metadataFile), pkg); !found || err != nil {
+		return nil, err
+	}
+
+	cache.mutex.Lock()
+	verified := cache.verified[key] == pkg.SHA256
+	cache.mutex.Unlock()
+	if !verified {
+		sum, err := checksum(filepath.Join(dir, packageFile))
+		if err != nil {
+			return nil, err
+		}
+		if sum != pkg.SHA256 {
+			return nil, fmt.Errorf("package '%s': %w", key.path(), ErrChecksumMismatch)
+		}
+		cache.mutex.Lock()
+		cache.verified[key] = pkg.SHA256
+		cache.mutex.Unlock()
+	}
+	return pkg, nil
+}
+
+// Get returns the cached package, downloading it first when it is not cached or fails verification.
+func (cache *Cache) Get(ctx context.Context, key PackageKey) (*Package, error) {
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	defer cache.lock(key)()
+	pkg, err := cache.lookup(key)
+	if err != nil && !errors.Is(err, ErrChecksumMismatch) {
+		return nil, err
+	}
+	if pkg != nil {
+		return pkg, nil
+	}
+	return cache.fetch(ctx, key)
+}
+
+// Refresh downloads the package again, replacing the cached one.
+func (cache *Cache) Refresh(ctx context.Context, key PackageKey) (*Package, error) {
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	defer cache.lock(key)()
+	return cache.fetch(ctx, key)
+}
+
+// Open returns the package file of a cached package for reading.
+func (cache *Cache) Open(pkg *Package) (*os.File, error) {
+	return os.Open(filepath.Join(cache.dir, filepath.FromSlash(pkg.Key.path()), packageFile))
+}
+
+func (cache *Cache) fetch(ctx context.Context, key PackageKey) (*Package, error) {
+	if cache.client == nil {
+		return nil, fmt.Errorf("package '%s' is not cached", key.path())
+	}
+	if cache.ServerVersion == nil {
+		return nil, fmt.Errorf("package '%s' is not cached and no server version is configured", key.path())
+	}
+	version, err := cache.ServerVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting server agent version: %w", err)
+	}
+	if version != key.Version {
+		return nil, fmt.Errorf("package '%s': the service distributes version '%s': %w", key.path(), version, ErrVersionMismatch)
+	}
+	options := &backuprecoveryv1.DownloadAgentOptions{
+		XIBMTenantID: core.StringPtr(cache.tenantID),
+		Platform:     core.StringPtr(key.Platform),
+	}
+	if key.PackageType != "" {
+		options.LinuxParams = &backuprecoveryv1.LinuxAgentParams{PackageType: core.StringPtr(key.PackageType)}
+	}
+	body, response, err := cache.client.DownloadAgentWithContext(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("downloading package '%s': %w", key.path(), err)
+	}
+	defer body.Close()
+
+	dir := filepath.Join(cache.dir, filepath.FromSlash(key.path()))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	var size int64
+	err = helpers.WriteFile(filepath.Join(dir, packageFile), func(w io.Writer) error {
+		size, err = io.Copy(io.MultiWriter(w, hash), body)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storing package '%s': %w", key.path(), err)
+	}
+
+	pkg := &Package{
+		Key:       key,
+		FileName:  fileName(response, key),
+		Size:      size,
+		SHA256:    hex.EncodeToString(hash.Sum(nil)),
+		FetchedAt: cache.Now().UTC(),
+	}
+	if err := helpers.SaveJSON(filepath.Join(dir, metadataFile), pkg); err != nil {
+		return nil, fmt.Errorf("storing package metadata '%s': %w", key.path(), err)
+	}
+	cache.mutex.Lock()
+	cache.verified[key] = pkg.SHA256
+	cache.mutex.Unlock()
+	return pkg, nil
+}
+
+// fileName returns the file name from the Content-Disposition header of the download, or one derived from the key.
+func fileName(response *core.DetailedResponse, key PackageKey) string {
+	if response != nil && response.Headers != nil {
+		if _, params, err := mime.ParseMediaType(response.Headers.Get("Content-Disposition")); err == nil {
+			if name := filepath.Base(params["filename"]); name != "" && name != "." && name != "/" {
+				return name
+			}
+		}
+	}
+	name := "agent-" + key.Version + "-" + strings.TrimPrefix(key.Platform, "k")
+	if key.PackageType != "" {
+		name += "-" + strings.TrimPrefix(key.PackageType, "k")
+	}
+	return name
+}
+
+func checksum(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Handler serves cached packages at /<version>/<platform>/<package type>, with "default" as the package type of
+// Windows packages, and their checksums in sha256sum format at the same path with a ".sha256" suffix. The checksum
+// is also sent in the X-Checksum-SHA256 header of every package response.
+func (cache *Cache) Handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet && request.Method != http.MethodHead {
+			http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		requested := strings.Trim(request.URL.Path, "/")
+		sum := strings.HasSuffix(requested, ".sha256")
+		parts := strings.Split(strings.TrimSuffix(requested, ".sha256"), "/")
+		if len(parts) != 3 {
+			http.NotFound(writer, request)
+			return
+		}
+		key := PackageKey{Version: parts[0], Platform: parts[1], PackageType: parts[2]}
+		if key.PackageType == "default" {
+			key.PackageType = ""
+		}
+		if key.validate() != nil {
+			http.NotFound(writer, request)
+			return
+		}
+
+		var pkg *Package
+		var err error
+		if cache.FetchOnMiss {
+			pkg, err = cache.Get(request.Context(), key)
+		} else {
+			pkg, err = cache.Lookup(key)
+		}
+		switch {
+		case errors.Is(err, ErrVersionMismatch):
+			http.Error(writer, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(writer, err.Error(), http.StatusBadGateway)
+			return
+		case pkg == nil:
+			http.NotFound(writer, request)
+			return
+		}
+
+		if sum {
+			writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprintf(writer, "%s  %s\n", pkg.SHA256, pkg.FileName)
+			return
+		}
+		file, err := cache.Open(pkg)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+		writer.Header().Set("Content-Type", "application/octet-stream")
+		writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": pkg.FileName}))
+		writer.Header().Set("X-Checksum-SHA256", pkg.SHA256)
+		writer.Header().Set("ETag", `"`+pkg.SHA256+`"`)
+		http.ServeContent(writer, request, pkg.FileName, pkg.FetchedAt, file)
+	})
+}
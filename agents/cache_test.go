@@ -0,0 +1,169 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDownloadClient struct {
+	downloads []*backuprecoveryv1.DownloadAgentOptions
+}
+
+func (fake *fakeDownloadClient) DownloadAgentWithContext(ctx context.Context, options *backuprecoveryv1.DownloadAgentOptions) (io.ReadCloser, *core.DetailedResponse, error) {
+	fake.downloads = append(fake.downloads, options)
+	headers := http.Header{}
+	headers.Set("Content-Disposition", `attachment; filename="cohesity-agent-7.2.deb"`)
+	return io.NopCloser(strings.NewReader("package content")), &core.DetailedResponse{Headers: headers}, nil
+}
+
+func serverVersion(ctx context.Context) (string, error) {
+	return "7.2", nil
+}
+
+var debKey = PackageKey{Platform: backuprecoveryv1.DownloadAgentOptions_Platform_Klinux, PackageType: backuprecoveryv1.LinuxAgentParams_PackageType_Kdeb, Version: "7.2"}
+
+func TestCacheDownloadsOnce(t *testing.T) {
+	client := &fakeDownloadClient{}
+	dir := t.TempDir()
+	cache := NewCache(client, "tenant", dir)
+	cache.ServerVersion = serverVersion
+
+	pkg, err := cache.Get(context.Background(), debKey)
+	require.Nil(t, err)
+	sum := sha256.Sum256([]byte("package content"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), pkg.SHA256)
+	assert.Equal(t, "cohesity-agent-7.2.deb", pkg.FileName)
+	assert.Equal(t, int64(15), pkg.Size)
+	assert.Equal(t, "kDEB", *client.downloads[0].LinuxParams.PackageType)
+
+	_, err = cache.Get(context.Background(), debKey)
+	require.Nil(t, err)
+	assert.Len(t, client.downloads, 1)
+
+	// A corrupted package is detected by a new cache and downloaded again.
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "7.2", "kLinux", "kDEB", "package"), []byte("tampered"), 0o644))
+	reopened := NewCache(client, "tenant", dir)
+	reopened.ServerVersion = serverVersion
+	_, err = reopened.Lookup(debKey)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	pkg, err = reopened.Get(context.Background(), debKey)
+	require.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), pkg.SHA256)
+	assert.Len(t, client.downloads, 2)
+
+	_, err = cache.Get(context.Background(), PackageKey{Platform: "kLinux", Version: "../etc"})
+	assert.NotNil(t, err)
+}
+
+func TestCacheHandler(t *testing.T) {
+	client := &fakeDownloadClient{}
+	cache := NewCache(client, "tenant", t.TempDir())
+	cache.ServerVersion = serverVersion
+	server := httptest.NewServer(cache.Handler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/7.2/kLinux/kDEB")
+	require.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Empty(t, client.downloads)
+
+	pkg, err := cache.Get(context.Background(), debKey)
+	require.Nil(t, err)
+	response, err = http.Get(server.URL + "/7.2/kLinux/kDEB")
+	require.Nil(t, err)
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "package content", string(body))
+	assert.Equal(t, pkg.SHA256, response.Header.Get("X-Checksum-SHA256"))
+	assert.Equal(t, `attachment; filename=cohesity-agent-7.2.deb`, response.Header.Get("Content-Disposition"))
+
+	response, err = http.Get(server.URL + "/7.2/kLinux/kDEB.sha256")
+	require.Nil(t, err)
+	body, _ = io.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, pkg.SHA256+"  cohesity-agent-7.2.deb\n", string(body))
+
+	cache.FetchOnMiss = true
+	response, err = http.Get(server.URL + "/7.2/kWindows/default")
+	require.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, client.downloads[1].LinuxParams)
+}
+
+func TestCacheRejectsOtherVersions(t *testing.T) {
+	client := &fakeDownloadClient{}
+	dir := t.TempDir()
+	cache := NewCache(client, "tenant", dir)
+
+	_, err := cache.Get(context.Background(), debKey)
+	assert.ErrorContains(t, err, "no server version")
+
+	cache.ServerVersion = serverVersion
+	_, err = cache.Get(context.Background(), PackageKey{Platform: "kLinux", PackageType: "kRPM", Version: "anything"})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	cache.FetchOnMiss = true
+	server := httptest.NewServer(cache.Handler())
+	defer server.Close()
+	response, err := http.Get(server.URL + "/anything/kLinux/kRPM")
+	require.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Empty(t, client.downloads)
+	_, err = os.Stat(filepath.Join(dir, "anything"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+type fakeVersionClient struct {
+	versions []string
+}
+
+func (fake fakeVersionClient) GetClustersInfoWithContext(ctx context.Context, options *backuprecoveryv1.GetClustersInfoOptions) (*backuprecoveryv1.ClusterDetails, *core.DetailedResponse, error) {
+	details := &backuprecoveryv1.ClusterDetails{}
+	for _, version := range fake.versions {
+		details.CohesityClusters = append(details.CohesityClusters, backuprecoveryv1.ClusterInfo{CurrentVersion: core.StringPtr(version)})
+	}
+	return details, nil, nil
+}
+
+func TestClusterVersion(t *testing.T) {
+	version, err := ClusterVersion(fakeVersionClient{versions: []string{"7.2", "7.2"}})(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "7.2", version)
+
+	_, err = ClusterVersion(fakeVersionClient{versions: []string{"7.2", "7.1"}})(context.Background())
+	assert.ErrorContains(t, err, "found 2: 7.1, 7.2")
+}
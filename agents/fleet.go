@@ -0,0 +1,507 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package agents keeps the backup agents of a fleet up to date: it finds outdated agents, upgrades them in batches
+// within maintenance windows and mirrors the agent packages for hosts without access to the service.
+package agents
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	ListProtectionSourcesWithContext(ctx context.Context, listProtectionSourcesOptions *backuprecoveryv1.ListProtectionSourcesOptions) (result []backuprecoveryv1.ProtectionSourceNodes, response *core.DetailedResponse, err error)
+	GetSourceRegistrationsWithContext(ctx context.Context, getSourceRegistrationsOptions *backuprecoveryv1.GetSourceRegistrationsOptions) (result *backuprecoveryv1.SourceRegistrations, response *core.DetailedResponse, err error)
+	CreateUpgradeTaskWithContext(ctx context.Context, createUpgradeTaskOptions *backuprecoveryv1.CreateUpgradeTaskOptions) (result *backuprecoveryv1.AgentUpgradeTaskState, response *core.DetailedResponse, err error)
+	GetUpgradeTasksWithContext(ctx context.Context, getUpgradeTasksOptions *backuprecoveryv1.GetUpgradeTasksOptions) (result *backuprecoveryv1.AgentUpgradeTaskStates, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Agent is a backup agent installed on a protected host.
+type Agent struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name,omitempty"`
+	HostName       string `json:"hostName,omitempty"`
+	HostType       string `json:"hostType,omitempty"`
+	SourceID       int64  `json:"sourceId"`
+	RegistrationID int64  `json:"registrationId,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Status         string `json:"status,omitempty"`
+
+	// Upgradability is one of the AgentInformation_Upgradability_* constants.
+	Upgradability string `json:"upgradability,omitempty"`
+
+	// UpgradeStatus is one of the AgentInformation_UpgradeStatus_* constants.
+	UpgradeStatus string `json:"upgradeStatus,omitempty"`
+}
+
+// Outdated reports whether the service offers an upgrade for the agent and none is in progress.
+func (agent Agent) Outdated() bool {
+	if agent.Upgradability != backuprecoveryv1.AgentInformation_Upgradability_Kupgradable {
+		return false
+	}
+	switch agent.UpgradeStatus {
+	case backuprecoveryv1.AgentInformation_UpgradeStatus_Kaccepted, backuprecoveryv1.AgentInformation_UpgradeStatus_Kscheduled,
+		backuprecoveryv1.AgentInformation_UpgradeStatus_Kstarted:
+		return false
+	}
+	return true
+}
+
+// Window is a maintenance window in which upgrades may run.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Outcome is the result of the upgrade of one agent.
+type Outcome string
+
+const (
+	OutcomePending     Outcome = "pending"
+	OutcomeSucceeded   Outcome = "succeeded"
+	OutcomeFailed      Outcome = "failed"
+	OutcomeSkipped     Outcome = "skipped"
+	OutcomeUnscheduled Outcome = "unscheduled"
+)
+
+// AgentOutcome is the report line of one agent.
+type AgentOutcome struct {
+	Agent    Agent     `json:"agent"`
+	Outcome  Outcome   `json:"outcome"`
+	Attempts int       `json:"attempts"`
+	TaskIDs  []int64   `json:"taskIds,omitempty"`
+	Window   *Window   `json:"window,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Report lists the outcome of every agent of an upgrade.
+type Report struct {
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+	Agents     []*AgentOutcome `json:"agents"`
+}
+
+// Count returns the number of agents with the outcome.
+func (report *Report) Count(outcome Outcome) int {
+	count := 0
+	for _, agent := range report.Agents {
+		if agent.Outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteCSV writes one line per agent.
+func (report *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"agent_id", "name", "host", "source_id", "version", "outcome", "attempts", "task_ids", "finished", "error"})
+	for _, outcome := range report.Agents {
+		tasks := make([]string, len(outcome.TaskIDs))
+		for i, id := range outcome.TaskIDs {
+			tasks[i] = strconv.FormatInt(id, 10)
+		}
+		finished := ""
+		if !outcome.Finished.IsZero() {
+			finished = outcome.Finished.Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			strconv.FormatInt(outcome.Agent.ID, 10),
+			outcome.Agent.Name,
+			outcome.Agent.HostName,
+			strconv.FormatInt(outcome.Agent.SourceID, 10),
+			outcome.Agent.Version,
+			string(outcome.Outcome),
+			strconv.Itoa(outcome.Attempts),
+			strings.Join(tasks, " "),
+			finished,
+			outcome.Error,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Manager discovers and upgrades agents.
+type Manager struct {
+	client   Client
+	tenantID string
+
+	// BatchSize is the maximum number of agents per upgrade task. Defaults to 25.
+	BatchSize int
+
+	// PerWindow is the maximum number of agents scheduled in one window. Zero means no limit.
+	PerWindow int
+
+	// MaxRetries is the number of times failed agents are retried with RetryTaskID. Defaults to 2.
+	MaxRetries int
+
+	// PollInterval is the delay between checks of the upgrade tasks. Defaults to one minute.
+	PollInterval time.Duration
+
+	// TaskTimeout fails the agents of a task that has not finished this long after its window ended, or after it
+	// was created when it has no window. Defaults to one hour.
+	TaskTimeout time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewManager : Instantiate Manager
+func NewManager(client Client, tenantID string) *Manager {
+	return &Manager{
+		client:       client,
+		tenantID:     tenantID,
+		BatchSize:    25,
+		MaxRetries:   2,
+		PollInterval: time.Minute,
+		TaskTimeout:  time.Hour,
+		Now:          time.Now,
+	}
+}
+
+// Discover lists the agents of the registered physical sources.
+func (manager *Manager) Discover(ctx context.Context) ([]Agent, error) {
+	registrations, _, err := manager.client.GetSourceRegistrationsWithContext(ctx, &backuprecoveryv1.GetSourceRegistrationsOptions{
+		XIBMTenantID: core.StringPtr(manager.tenantID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing source registrations: %w", err)
+	}
+	registered := map[int64]int64{}
+	if registrations != nil {
+		for _, registration := range registrations.Registrations {
+			registered[helpers.Deref(registration.SourceID)] = helpers.Deref(registration.ID)
+		}
+	}
+
+	nodes, _, err := manager.client.ListProtectionSourcesWithContext(ctx, &backuprecoveryv1.ListProtectionSourcesOptions{
+		XIBMTenantID: core.StringPtr(manager.tenantID),
+		Environments: []string{backuprecoveryv1.ListProtectionSourcesOptions_Environments_Kphysical},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing protection sources: %w", err)
+	}
+	seen := map[int64]bool{}
+	var agents []Agent
+	var walk func(nodes []backuprecoveryv1.ProtectionSourceNodes)
+	walk = func(nodes []backuprecoveryv1.ProtectionSourceNodes) {
+		for _, node := range nodes {
+			if source := node.ProtectionSource; source != nil && source.PhysicalProtectionSource != nil {
+				sourceID := helpers.Deref(source.ID)
+				registrationID, ok := registered[sourceID]
+				physical := source.PhysicalProtectionSource
+				for _, info := range physical.Agents {
+					id := helpers.Deref(info.ID)
+					if !ok || seen[id] {
+						continue
+					}
+					seen[id] = true
+					agents = append(agents, Agent{
+						ID:             id,
+						Name:           helpers.Deref(info.Name),
+						HostName:       helpers.Deref(physical.HostName),
+						HostType:       helpers.Deref(info.HostType),
+						SourceID:       sourceID,
+						RegistrationID: registrationID,
+						Version:        helpers.Deref(info.Version),
+						Status:         helpers.Deref(info.Status),
+						Upgradability:  helpers.Deref(info.Upgradability),
+						UpgradeStatus:  helpers.Deref(info.UpgradeStatus),
+					})
+				}
+			}
+			walk(node.Nodes)
+			walk(node.ApplicationNodes)
+		}
+	}
+	walk(nodes)
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+	return agents, nil
+}
+
+// Outdated returns the discovered agents that can be upgraded.
+func (manager *Manager) Outdated(ctx context.Context) ([]Agent, error) {
+	agents, err := manager.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var outdated []Agent
+	for _, agent := range agents {
+		if agent.Outdated() {
+			outdated = append(outdated, agent)
+		}
+	}
+	return outdated, nil
+}
+
+// task is an upgrade task created by the manager.
+type task struct {
+	id       int64
+	agents   []*AgentOutcome
+	window   *Window
+	deadline time.Time
+}
+
+// Upgrade schedules the agents in batches within the windows, waits for every task to finish and retries failed
+// agents. Windows that already ended are ignored; when there are no windows the upgrades start immediately. Agents
+// that do not fit into the windows are reported as unscheduled. The report is returned even when an error ends
+// the upgrade early: the tasks created before the error are still tracked, agents that were not scheduled are
+// reported as unscheduled and agents whose task could no longer be tracked as failed, both with the error.
+func (manager *Manager) Upgrade(ctx context.Context, agents []Agent, windows []Window) (*Report, error) {
+	report := &Report{StartedAt: manager.Now().UTC()}
+	var pending []*AgentOutcome
+	for _, agent := range agents {
+		outcome := &AgentOutcome{Agent: agent, Outcome: OutcomePending}
+		report.Agents = append(report.Agents, outcome)
+		if agent.Outdated() {
+			pending = append(pending, outcome)
+		} else {
+			outcome.Outcome, outcome.Error = OutcomeSkipped, "not upgradable: "+agent.Upgradability
+		}
+	}
+
+	tasks, scheduleErr := manager.schedule(ctx, pending, windows)
+	trackErr := manager.track(ctx, tasks)
+	report.FinishedAt = manager.Now().UTC()
+	return report, errors.Join(scheduleErr, trackErr)
+}
+
+// schedule creates the upgrade tasks of the agents. When a task cannot be created, the tasks created so far are
+// returned with the error and the agents left are reported as unscheduled.
+func (manager *Manager) schedule(ctx context.Context, pending []*AgentOutcome, windows []Window) ([]*task, error) {
+	now := manager.Now()
+	var open []Window
+	for _, window := range windows {
+		if window.End.After(now) {
+			open = append(open, window)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].Start.Before(open[j].Start) })
+	if len(windows) == 0 {
+		open = []Window{{}}
+	}
+
+	var tasks []*task
+	for i := range open {
+		window := &open[i]
+		capacity := len(pending)
+		if manager.PerWindow > 0 {
+			capacity = min(capacity, manager.PerWindow)
+		}
+		members := pending[:capacity]
+		for batchIndex := 0; len(members) > 0; batchIndex++ {
+			batch := members[:min(max(1, manager.BatchSize), len(members))]
+			created, err := manager.create(ctx, batch, window, fmt.Sprintf("w%d-b%d", i+1, batchIndex+1), 0)
+			if err != nil {
+				for _, outcome := range pending[len(batch):] {
+					outcome.Outcome, outcome.Error = OutcomeUnscheduled, "not scheduled: "+err.Error()
+				}
+				return tasks, err
+			}
+			tasks = append(tasks, created)
+			members = members[len(batch):]
+			pending = pending[len(batch):]
+		}
+	}
+	for _, outcome := range pending {
+		outcome.Outcome, outcome.Error = OutcomeUnscheduled, "no maintenance window left"
+	}
+	return tasks, nil
+}
+
+// create creates the upgrade task of a batch. The label tells apart the tasks created in the same second.
+func (manager *Manager) create(ctx context.Context, batch []*AgentOutcome, window *Window, label string, retryTaskID int64) (*task, error) {
+	options := &backuprecoveryv1.CreateUpgradeTaskOptions{
+		XIBMTenantID: core.StringPtr(manager.tenantID),
+		Name:         core.StringPtr(fmt.Sprintf("fleet-upgrade-%d-%s", manager.Now().Unix(), label)),
+	}
+	for _, outcome := range batch {
+		options.AgentIDs = append(options.AgentIDs, outcome.Agent.ID)
+	}
+	now := manager.Now()
+	deadline := now.Add(manager.TaskTimeout)
+	if !window.End.IsZero() {
+		if window.Start.After(now) {
+			options.ScheduleTimeUsecs = core.Int64Ptr(window.Start.UnixMicro())
+		}
+		options.ScheduleEndTimeUsecs = core.Int64Ptr(window.End.UnixMicro())
+		deadline = window.End.Add(manager.TaskTimeout)
+	}
+	if retryTaskID != 0 {
+		options.RetryTaskID = core.Int64Ptr(retryTaskID)
+		options.Description = core.StringPtr(fmt.Sprintf("retry of task %d", retryTaskID))
+	}
+	result, _, err := manager.client.CreateUpgradeTaskWithContext(ctx, options)
+	if err != nil {
+		for _, outcome := range batch {
+			outcome.Outcome, outcome.Error = OutcomeFailed, err.Error()
+		}
+		return nil, fmt.Errorf("creating upgrade task: %w", err)
+	}
+	created := &task{id: helpers.Deref(result.ID), agents: batch, deadline: deadline}
+	if !window.End.IsZero() {
+		created.window = window
+	}
+	for _, outcome := range batch {
+		outcome.Attempts++
+		outcome.TaskIDs = append(outcome.TaskIDs, created.id)
+		outcome.Window = created.window
+	}
+	return created, nil
+}
+
+// track polls the tasks until every one is finished, retrying failed agents as long as their window is open. When
+// the tasks can no longer be polled, the agents of the unfinished tasks are reported as failed with the error.
+func (manager *Manager) track(ctx context.Context, tasks []*task) error {
+	var errs []error
+	abandon := func(err error) error {
+		for _, task := range tasks {
+			for _, outcome := range task.agents {
+				outcome.Outcome, outcome.Error = OutcomeFailed, fmt.Sprintf("task %d not tracked: %s", task.id, err)
+			}
+		}
+		return errors.Join(append(errs, err)...)
+	}
+	for len(tasks) > 0 {
+		ids := make([]int64, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.id
+		}
+		result, _, err := manager.client.GetUpgradeTasksWithContext(ctx, &backuprecoveryv1.GetUpgradeTasksOptions{
+			XIBMTenantID: core.StringPtr(manager.tenantID),
+			Ids:          ids,
+		})
+		if err != nil {
+			return abandon(fmt.Errorf("getting upgrade tasks: %w", err))
+		}
+		states := map[int64]backuprecoveryv1.AgentUpgradeTaskState{}
+		if result != nil {
+			for _, state := range result.Tasks {
+				states[helpers.Deref(state.ID)] = state
+			}
+		}
+
+		now := manager.Now()
+		var active []*task
+		for _, task := range tasks {
+			state, ok := states[task.id]
+			if !ok || !finished(helpers.Deref(state.Status)) {
+				if now.After(task.deadline) {
+					for _, outcome := range task.agents {
+						outcome.Outcome, outcome.Error, outcome.Finished = OutcomeFailed, "timed out", now.UTC()
+					}
+					continue
+				}
+				active = append(active, task)
+				continue
+			}
+			failed := manager.settle(task, state, now)
+			if len(failed) == 0 {
+				continue
+			}
+			if failed[0].Attempts > manager.MaxRetries || (state.IsRetryable != nil && !*state.IsRetryable) {
+				continue
+			}
+			window := task.window
+			if window == nil {
+				window = &Window{}
+			} else if !window.End.After(now) {
+				continue
+			}
+			retry, err := manager.create(ctx, failed, window, fmt.Sprintf("retry-%d", task.id), task.id)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			active = append(active, retry)
+		}
+		tasks = active
+		if len(tasks) == 0 {
+			break
+		}
+		timer := time.NewTimer(manager.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return abandon(ctx.Err())
+		case <-timer.C:
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// settle records the outcome of the agents of a finished task and returns the failed ones.
+func (manager *Manager) settle(task *task, state backuprecoveryv1.AgentUpgradeTaskState, now time.Time) []*AgentOutcome {
+	infos := map[int64]*backuprecoveryv1.AgentInfoObject{}
+	for _, agent := range state.Agents {
+		infos[helpers.Deref(agent.ID)] = agent.Info
+	}
+	taskError := ""
+	if state.Error != nil {
+		taskError = helpers.Deref(state.Error.Message)
+	}
+	var failed []*AgentOutcome
+	for _, outcome := range task.agents {
+		outcome.Finished = now.UTC()
+		info := infos[outcome.Agent.ID]
+		status := helpers.Deref(state.Status)
+		if info != nil && info.Status != nil {
+			status = *info.Status
+		}
+		switch status {
+		case backuprecoveryv1.AgentInfoObject_Status_Succeeded:
+			outcome.Outcome, outcome.Error = OutcomeSucceeded, ""
+		case backuprecoveryv1.AgentInfoObject_Status_Skipped:
+			outcome.Outcome = OutcomeSkipped
+		default:
+			outcome.Outcome, outcome.Error = OutcomeFailed, taskError
+			if info != nil && info.Error != nil && info.Error.Message != nil {
+				outcome.Error = *info.Error.Message
+			}
+			if outcome.Error == "" {
+				outcome.Error = "upgrade " + strings.ToLower(status)
+			}
+			failed = append(failed, outcome)
+		}
+	}
+	return failed
+}
+
+func finished(status string) bool {
+	switch status {
+	case backuprecoveryv1.AgentUpgradeTaskState_Status_Succeeded, backuprecoveryv1.AgentUpgradeTaskState_Status_Failed,
+		backuprecoveryv1.AgentUpgradeTaskState_Status_Partiallyfailed, backuprecoveryv1.AgentUpgradeTaskState_Status_Canceled:
+		return true
+	}
+	return false
+}
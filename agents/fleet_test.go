@@ -0,0 +1,236 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	// failures is the number of attempts that fail per agent id.
+	failures map[int64]int
+	created  []*backuprecoveryv1.CreateUpgradeTaskOptions
+	tasks    map[int64][]int64
+	// failCreate fails the creation of the task with this 1-based index.
+	failCreate int
+	// failPoll fails every poll of the tasks.
+	failPoll bool
+}
+
+func physicalNode(sourceID int64, host string, agents ...backuprecoveryv1.AgentInformation) backuprecoveryv1.ProtectionSourceNodes {
+	return backuprecoveryv1.ProtectionSourceNodes{ProtectionSource: &backuprecoveryv1.ProtectionSourceNode{
+		ID:                       core.Int64Ptr(sourceID),
+		PhysicalProtectionSource: &backuprecoveryv1.PhysicalProtectionSource{HostName: core.StringPtr(host), Agents: agents},
+	}}
+}
+
+func agentInfo(id int64, upgradability string) backuprecoveryv1.AgentInformation {
+	return backuprecoveryv1.AgentInformation{
+		ID:            core.Int64Ptr(id),
+		Name:          core.StringPtr("agent"),
+		Version:       core.StringPtr("7.1"),
+		Upgradability: core.StringPtr(upgradability),
+		UpgradeStatus: core.StringPtr(backuprecoveryv1.AgentInformation_UpgradeStatus_Kidle),
+	}
+}
+
+func (fake *fakeClient) ListProtectionSourcesWithContext(ctx context.Context, options *backuprecoveryv1.ListProtectionSourcesOptions) ([]backuprecoveryv1.ProtectionSourceNodes, *core.DetailedResponse, error) {
+	root := physicalNode(10, "host-a", agentInfo(1, backuprecoveryv1.AgentInformation_Upgradability_Kupgradable))
+	root.Nodes = []backuprecoveryv1.ProtectionSourceNodes{
+		physicalNode(11, "host-b", agentInfo(2, backuprecoveryv1.AgentInformation_Upgradability_Kcurrent)),
+		physicalNode(12, "host-c", agentInfo(3, backuprecoveryv1.AgentInformation_Upgradability_Kupgradable)),
+		// Not registered.
+		physicalNode(13, "host-d", agentInfo(4, backuprecoveryv1.AgentInformation_Upgradability_Kupgradable)),
+	}
+	return []backuprecoveryv1.ProtectionSourceNodes{root}, nil, nil
+}
+
+func (fake *fakeClient) GetSourceRegistrationsWithContext(ctx context.Context, options *backuprecoveryv1.GetSourceRegistrationsOptions) (*backuprecoveryv1.SourceRegistrations, *core.DetailedResponse, error) {
+	result := &backuprecoveryv1.SourceRegistrations{}
+	for _, id := range []int64{10, 11, 12} {
+		result.Registrations = append(result.Registrations, backuprecoveryv1.SourceRegistrationResponseParams{ID: core.Int64Ptr(id + 100), SourceID: core.Int64Ptr(id)})
+	}
+	return result, nil, nil
+}
+
+func (fake *fakeClient) CreateUpgradeTaskWithContext(ctx context.Context, options *backuprecoveryv1.CreateUpgradeTaskOptions) (*backuprecoveryv1.AgentUpgradeTaskState, *core.DetailedResponse, error) {
+	fake.created = append(fake.created, options)
+	id := int64(len(fake.created))
+	if int(id) == fake.failCreate {
+		return nil, nil, errors.New("too many tasks")
+	}
+	if fake.tasks == nil {
+		fake.tasks = map[int64][]int64{}
+	}
+	fake.tasks[id] = options.AgentIDs
+	return &backuprecoveryv1.AgentUpgradeTaskState{ID: core.Int64Ptr(id)}, nil, nil
+}
+
+func (fake *fakeClient) GetUpgradeTasksWithContext(ctx context.Context, options *backuprecoveryv1.GetUpgradeTasksOptions) (*backuprecoveryv1.AgentUpgradeTaskStates, *core.DetailedResponse, error) {
+	if fake.failPoll {
+		return nil, nil, errors.New("unavailable")
+	}
+	result := &backuprecoveryv1.AgentUpgradeTaskStates{}
+	for _, id := range options.Ids {
+		state := backuprecoveryv1.AgentUpgradeTaskState{ID: core.Int64Ptr(id), Status: core.StringPtr(backuprecoveryv1.AgentUpgradeTaskState_Status_Succeeded)}
+		for _, agentID := range fake.tasks[id] {
+			info := &backuprecoveryv1.AgentInfoObject{Status: core.StringPtr(backuprecoveryv1.AgentInfoObject_Status_Succeeded)}
+			if fake.failures[agentID] > 0 {
+				fake.failures[agentID]--
+				info.Status = core.StringPtr(backuprecoveryv1.AgentInfoObject_Status_Failed)
+				info.Error = &backuprecoveryv1.Error{Message: core.StringPtr("disk full")}
+				state.Status = core.StringPtr(backuprecoveryv1.AgentUpgradeTaskState_Status_Partiallyfailed)
+			}
+			state.Agents = append(state.Agents, backuprecoveryv1.AgentUpgradeInfoObject{ID: core.Int64Ptr(agentID), Info: info})
+		}
+		result.Tasks = append(result.Tasks, state)
+	}
+	return result, nil, nil
+}
+
+func newManager(client *fakeClient) *Manager {
+	manager := NewManager(client, "tenant")
+	manager.PollInterval = time.Millisecond
+	manager.Now = func() time.Time { return base }
+	return manager
+}
+
+func TestDiscoverOutdatedAgents(t *testing.T) {
+	manager := newManager(&fakeClient{})
+	agents, err := manager.Discover(context.Background())
+	require.Nil(t, err)
+	require.Len(t, agents, 3)
+	assert.Equal(t, "host-c", agents[2].HostName)
+	assert.Equal(t, int64(112), agents[2].RegistrationID)
+
+	outdated, err := manager.Outdated(context.Background())
+	require.Nil(t, err)
+	require.Len(t, outdated, 2)
+	assert.Equal(t, int64(1), outdated[0].ID)
+	assert.Equal(t, int64(3), outdated[1].ID)
+}
+
+func TestUpgradeInWindowsWithRetries(t *testing.T) {
+	client := &fakeClient{failures: map[int64]int{3: 1, 5: 5}}
+	manager := newManager(client)
+	manager.BatchSize = 2
+	manager.PerWindow = 3
+
+	var agents []Agent
+	for id := int64(1); id <= 5; id++ {
+		agents = append(agents, Agent{ID: id, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kupgradable})
+	}
+	agents = append(agents, Agent{ID: 6, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kcurrent})
+	windows := []Window{
+		{Start: base.Add(-48 * time.Hour), End: base.Add(-47 * time.Hour)},
+		{Start: base.Add(time.Hour), End: base.Add(3 * time.Hour)},
+		{Start: base.Add(25 * time.Hour), End: base.Add(27 * time.Hour)},
+	}
+
+	report, err := manager.Upgrade(context.Background(), agents, windows)
+	require.Nil(t, err)
+
+	// Two windows of at most three agents, in batches of two, then a retry of agent 3 and two of agent 5.
+	require.Len(t, client.created, 6)
+	assert.Equal(t, []int64{1, 2}, client.created[0].AgentIDs)
+	assert.Equal(t, base.Add(time.Hour).UnixMicro(), *client.created[0].ScheduleTimeUsecs)
+	assert.Equal(t, []int64{3}, client.created[1].AgentIDs)
+	assert.Equal(t, []int64{4, 5}, client.created[2].AgentIDs)
+	assert.Equal(t, base.Add(27*time.Hour).UnixMicro(), *client.created[2].ScheduleEndTimeUsecs)
+	assert.Equal(t, int64(2), *client.created[3].RetryTaskID)
+	names := map[string]bool{}
+	for _, options := range client.created {
+		names[*options.Name] = true
+	}
+	assert.Len(t, names, 6)
+	assert.Equal(t, "fleet-upgrade-1780315200-w1-b2", *client.created[1].Name)
+	assert.Equal(t, "fleet-upgrade-1780315200-retry-2", *client.created[3].Name)
+
+	outcomes := map[int64]*AgentOutcome{}
+	for _, outcome := range report.Agents {
+		outcomes[outcome.Agent.ID] = outcome
+	}
+	assert.Equal(t, OutcomeSucceeded, outcomes[3].Outcome)
+	assert.Equal(t, 2, outcomes[3].Attempts)
+	assert.Equal(t, OutcomeFailed, outcomes[5].Outcome)
+	assert.Equal(t, 3, outcomes[5].Attempts)
+	assert.Equal(t, "disk full", outcomes[5].Error)
+	assert.Equal(t, OutcomeSkipped, outcomes[6].Outcome)
+	assert.Equal(t, 4, report.Count(OutcomeSucceeded))
+
+	var csv strings.Builder
+	require.Nil(t, report.WriteCSV(&csv))
+	assert.Contains(t, csv.String(), "5,,,0,,failed,3,3 5 6,2026-06-01T12:00:00Z,disk full\n")
+}
+
+func TestUpgradeReportsUnscheduledAgents(t *testing.T) {
+	client := &fakeClient{}
+	manager := newManager(client)
+	manager.PerWindow = 1
+	agents := []Agent{
+		{ID: 1, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kupgradable},
+		{ID: 2, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kupgradable},
+	}
+
+	report, err := manager.Upgrade(context.Background(), agents, []Window{{Start: base, End: base.Add(time.Hour)}})
+	require.Nil(t, err)
+	assert.Equal(t, OutcomeSucceeded, report.Agents[0].Outcome)
+	assert.Equal(t, OutcomeUnscheduled, report.Agents[1].Outcome)
+	assert.Nil(t, client.created[0].ScheduleTimeUsecs)
+}
+
+func TestUpgradeTracksTasksCreatedBeforeAnError(t *testing.T) {
+	client := &fakeClient{failCreate: 2}
+	manager := newManager(client)
+	manager.BatchSize = 1
+	var agents []Agent
+	for id := int64(1); id <= 3; id++ {
+		agents = append(agents, Agent{ID: id, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kupgradable})
+	}
+
+	report, err := manager.Upgrade(context.Background(), agents, nil)
+	assert.ErrorContains(t, err, "too many tasks")
+	require.Len(t, client.created, 2)
+	assert.Equal(t, OutcomeSucceeded, report.Agents[0].Outcome)
+	assert.Equal(t, OutcomeFailed, report.Agents[1].Outcome)
+	assert.Equal(t, OutcomeUnscheduled, report.Agents[2].Outcome)
+	assert.Equal(t, "not scheduled: creating upgrade task: too many tasks", report.Agents[2].Error)
+	assert.Zero(t, report.Count(OutcomePending))
+}
+
+func TestUpgradeReportsUntrackedTasks(t *testing.T) {
+	client := &fakeClient{failPoll: true}
+	manager := newManager(client)
+	agents := []Agent{{ID: 1, Upgradability: backuprecoveryv1.AgentInformation_Upgradability_Kupgradable}}
+
+	report, err := manager.Upgrade(context.Background(), agents, nil)
+	assert.ErrorContains(t, err, "unavailable")
+	assert.Equal(t, OutcomeFailed, report.Agents[0].Outcome)
+	assert.Equal(t, "task 1 not tracked: getting upgrade tasks: unavailable", report.Agents[0].Error)
+	assert.Equal(t, []int64{1}, report.Agents[0].TaskIDs)
+}
@@ -0,0 +1,131 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package alerts turns the point-in-time alert lists of the management SRE API into a stream of state changes.
+package alerts
+
+import (
+	"time"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Alert is the common form of the alerts returned by GetManagementAlerts and GetAlerts.
+type Alert struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name,omitempty"`
+	Code         string            `json:"code,omitempty"`
+	Category     string            `json:"category,omitempty"`
+	Type         int64             `json:"type,omitempty"`
+	TypeBucket   string            `json:"typeBucket,omitempty"`
+	State        string            `json:"state"`
+	Severity     string            `json:"severity"`
+	ClusterID    int64             `json:"clusterId,omitempty"`
+	ClusterName  string            `json:"clusterName,omitempty"`
+	RegionID     string            `json:"regionId,omitempty"`
+	Summary      string            `json:"summary,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Cause        string            `json:"cause,omitempty"`
+	HelpText     string            `json:"helpText,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+	DedupCount   int64             `json:"dedupCount,omitempty"`
+	FirstSeen    time.Time         `json:"firstSeen"`
+	LastSeen     time.Time         `json:"lastSeen"`
+	ResolvedAt   time.Time         `json:"resolvedAt,omitempty"`
+	ResolutionID string            `json:"resolutionId,omitempty"`
+}
+
+// Resolved reports whether the alert is resolved.
+func (alert Alert) Resolved() bool {
+	return alert.State == backuprecoveryv1.Alert_AlertState_Kresolved
+}
+
+// FromManagementAlert converts an alert returned by GetManagementAlerts.
+func FromManagementAlert(source *backuprecoveryv1.Alert) Alert {
+	alert := Alert{
+		ID:           helpers.Deref(source.ID),
+		Code:         helpers.Deref(source.AlertCode),
+		Category:     helpers.Deref(source.AlertCategory),
+		Type:         helpers.Deref(source.AlertType),
+		TypeBucket:   helpers.Deref(source.AlertTypeBucket),
+		State:        helpers.Deref(source.AlertState),
+		Severity:     helpers.Deref(source.Severity),
+		ClusterID:    helpers.Deref(source.ClusterID),
+		ClusterName:  helpers.Deref(source.ClusterName),
+		RegionID:     helpers.Deref(source.RegionID),
+		Properties:   properties(source.PropertyList),
+		DedupCount:   helpers.Deref(source.DedupCount),
+		FirstSeen:    usecs(source.FirstTimestampUsecs),
+		LastSeen:     usecs(source.LatestTimestampUsecs),
+		ResolutionID: helpers.Deref(source.ResolutionIdString),
+	}
+	alert.setDocument(source.AlertDocument)
+	return alert
+}
+
+// FromAlertInfo converts an alert returned by GetAlerts.
+func FromAlertInfo(source *backuprecoveryv1.AlertInfo) Alert {
+	alert := Alert{
+		ID:           helpers.Deref(source.ID),
+		Code:         helpers.Deref(source.AlertCode),
+		Category:     helpers.Deref(source.AlertCategory),
+		Type:         helpers.Deref(source.AlertType),
+		TypeBucket:   helpers.Deref(source.AlertTypeBucket),
+		State:        helpers.Deref(source.AlertState),
+		Severity:     helpers.Deref(source.Severity),
+		ClusterID:    helpers.Deref(source.ClusterID),
+		ClusterName:  helpers.Deref(source.ClusterName),
+		RegionID:     helpers.Deref(source.RegionID),
+		Properties:   properties(source.PropertyList),
+		DedupCount:   helpers.Deref(source.DedupCount),
+		FirstSeen:    usecs(source.FirstTimestampUsecs),
+		LastSeen:     usecs(source.LatestTimestampUsecs),
+		ResolvedAt:   usecs(source.ResolvedTimestampUsecs),
+		ResolutionID: helpers.Deref(source.ResolutionIdString),
+	}
+	alert.setDocument(source.AlertDocument)
+	return alert
+}
+
+func (alert *Alert) setDocument(document *backuprecoveryv1.AlertDocument) {
+	if document == nil {
+		return
+	}
+	alert.Name = helpers.Deref(document.AlertName)
+	alert.Summary = helpers.Deref(document.AlertSummary)
+	alert.Description = helpers.Deref(document.AlertDescription)
+	alert.Cause = helpers.Deref(document.AlertCause)
+	alert.HelpText = helpers.Deref(document.AlertHelpText)
+}
+
+func properties(labels []backuprecoveryv1.Label) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(labels))
+	for _, label := range labels {
+		values[helpers.Deref(label.Key)] = helpers.Deref(label.Value)
+	}
+	return values
+}
+
+func usecs(value *int64) time.Time {
+	if value == nil || *value == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(*value).UTC()
+}
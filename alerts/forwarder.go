@@ -0,0 +1,470 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// ResolutionClient is the subset of the management SRE API used to describe resolved alerts.
+type ResolutionClient interface {
+	GetManagementAlertResolutionWithContext(ctx context.Context, getManagementAlertResolutionOptions *backuprecoveryv1.GetManagementAlertResolutionOptions) (result *backuprecoveryv1.AlertResolutionsList, response *core.DetailedResponse, err error)
+}
+
+var _ ResolutionClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+const (
+	// CloudEventsContentType is the content type of a single event in structured mode.
+	CloudEventsContentType = "application/cloudevents+json"
+	// CloudEventsBatchContentType is the content type of a batch of events.
+	CloudEventsBatchContentType = "application/cloudevents-batch+json"
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body, prefixed with "sha256=".
+	SignatureHeader = "X-Signature-256"
+)
+
+// eventTypePrefix namespaces the CloudEvents type attribute, e.g. com.ibm.backuprecovery.alert.opened.
+const eventTypePrefix = "com.ibm.backuprecovery.alert."
+
+// CloudEvent is a CloudEvents 1.0 event in structured JSON form.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+// EventData is the payload of a forwarded alert event.
+type EventData struct {
+	Alert            Alert       `json:"alert"`
+	PreviousSeverity string      `json:"previousSeverity,omitempty"`
+	Resolution       *Resolution `json:"resolution,omitempty"`
+}
+
+// Resolution describes how a resolved alert was resolved.
+type Resolution struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SilenceRule suppresses matching events. Every non-empty criterion must match; a rule without criteria matches
+// every event.
+type SilenceRule struct {
+	Name         string            `json:"name"`
+	EventTypes   []EventType       `json:"eventTypes,omitempty"`
+	Severities   []string          `json:"severities,omitempty"`
+	Categories   []string          `json:"categories,omitempty"`
+	ClusterIDs   []int64           `json:"clusterIds,omitempty"`
+	ClusterNames []string          `json:"clusterNames,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+
+	// Until ends the silence. A zero value silences indefinitely.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// Matches reports whether the rule silences the event at the given time.
+func (rule SilenceRule) Matches(event Event, now time.Time) bool {
+	if !rule.Until.IsZero() && !now.Before(rule.Until) {
+		return false
+	}
+	alert := event.Alert
+	if len(rule.EventTypes) > 0 && !slices.Contains(rule.EventTypes, event.Type) {
+		return false
+	}
+	if len(rule.Severities) > 0 && !slices.Contains(rule.Severities, alert.Severity) {
+		return false
+	}
+	if len(rule.Categories) > 0 && !slices.Contains(rule.Categories, alert.Category) {
+		return false
+	}
+	if len(rule.ClusterIDs) > 0 && !slices.Contains(rule.ClusterIDs, alert.ClusterID) {
+		return false
+	}
+	if len(rule.ClusterNames) > 0 && !slices.Contains(rule.ClusterNames, alert.ClusterName) {
+		return false
+	}
+	for key, value := range rule.Properties {
+		if actual, ok := alert.Properties[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Endpoint is an HTTP receiver of forwarded events.
+type Endpoint struct {
+	URL string
+
+	// Secret, when set, signs every request body with HMAC-SHA256 in the SignatureHeader.
+	Secret []byte
+
+	// Headers are added to every request, e.g. an authorization token.
+	Headers map[string]string
+}
+
+// DeliveryError is returned when a batch could not be delivered to an endpoint.
+type DeliveryError struct {
+	URL        string
+	StatusCode int
+	Attempts   int
+	Err        error
+}
+
+func (err *DeliveryError) Error() string {
+	if err.Err != nil {
+		return fmt.Sprintf("delivering to '%s' failed after %d attempts: %s", err.URL, err.Attempts, err.Err)
+	}
+	return fmt.Sprintf("delivering to '%s' failed after %d attempts: status %d", err.URL, err.Attempts, err.StatusCode)
+}
+
+func (err *DeliveryError) Unwrap() error {
+	return err.Err
+}
+
+// UndeliveredError is passed to the error callback of Run with the events that were not delivered to every endpoint,
+// so that they can be kept and forwarded again. Err holds the delivery errors.
+type UndeliveredError struct {
+	Events []Event
+	Err    error
+}
+
+func (err *UndeliveredError) Error() string {
+	return fmt.Sprintf("%d events not delivered: %s", len(err.Events), err.Err)
+}
+
+func (err *UndeliveredError) Unwrap() error {
+	return err.Err
+}
+
+// Forwarder converts alert events to CloudEvents and posts them to HTTP endpoints.
+type Forwarder struct {
+	endpoints []Endpoint
+
+	// HTTPClient sends the requests. Defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+
+	// Source is the CloudEvents source attribute. Defaults to "/ibm-backup-recovery/alerts".
+	Source string
+
+	// Silences suppresses matching events.
+	Silences []SilenceRule
+
+	// Resolutions, when set, is used to describe the resolution of resolved alerts.
+	Resolutions ResolutionClient
+
+	// BatchSize is the maximum number of events per request. Defaults to 50.
+	BatchSize int
+
+	// FlushInterval is the longest time Run holds events before sending an incomplete batch. Defaults to 5 seconds.
+	FlushInterval time.Duration
+
+	// MaxAttempts is the number of delivery attempts per batch and endpoint. Defaults to 4.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry; it doubles with every further retry. Defaults to one second.
+	Backoff time.Duration
+
+	// ShutdownTimeout bounds the delivery of the events Run still holds when its context is cancelled. Defaults to
+	// 10 seconds.
+	ShutdownTimeout time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	resolutionMutex sync.Mutex
+	resolutions     map[string]*Resolution
+}
+
+// NewForwarder : Instantiate Forwarder
+func NewForwarder(endpoints ...Endpoint) *Forwarder {
+	return &Forwarder{
+		endpoints:       endpoints,
+		HTTPClient:      &http.Client{Timeout: 30 * time.Second},
+		Source:          "/ibm-backup-recovery/alerts",
+		BatchSize:       50,
+		FlushInterval:   5 * time.Second,
+		MaxAttempts:     4,
+		Backoff:         time.Second,
+		ShutdownTimeout: 10 * time.Second,
+		Now:             time.Now,
+		resolutions:     map[string]*Resolution{},
+	}
+}
+
+// CloudEvent converts an event. The event id is stable, so receivers can de-duplicate redelivered events.
+func (forwarder *Forwarder) CloudEvent(ctx context.Context, event Event) CloudEvent {
+	data := EventData{Alert: event.Alert, PreviousSeverity: event.PreviousSeverity}
+	if event.Type == EventResolved {
+		data.Resolution = forwarder.resolution(ctx, event.Alert.ResolutionID)
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", event.Alert.ID, event.Type, event.Alert.Severity, event.DetectedAt.UnixMicro())))
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              hex.EncodeToString(hash[:16]),
+		Source:          forwarder.Source,
+		Type:            eventTypePrefix + string(event.Type),
+		Subject:         event.Alert.ID,
+		Time:            event.DetectedAt.UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// resolution returns the cached description of a resolution. Lookup failures leave the resolution undescribed
+// rather than holding back the event. The lookup runs without the cache lock, so a slow lookup does not hold up
+// events of other resolutions; concurrent lookups of the same resolution may both reach the service.
+func (forwarder *Forwarder) resolution(ctx context.Context, id string) *Resolution {
+	if id == "" {
+		return nil
+	}
+	forwarder.resolutionMutex.Lock()
+	resolution, ok := forwarder.resolutions[id]
+	forwarder.resolutionMutex.Unlock()
+	if ok {
+		return resolution
+	}
+	resolution = &Resolution{ID: id}
+	if forwarder.Resolutions != nil {
+		result, _, err := forwarder.Resolutions.GetManagementAlertResolutionWithContext(ctx, &backuprecoveryv1.GetManagementAlertResolutionOptions{
+			MaxResolutions: core.Int64Ptr(1),
+			ResolutionID:   core.StringPtr(id),
+		})
+		if err != nil {
+			return resolution
+		}
+		if result != nil && len(result.AlertResolutionsList) > 0 {
+			resolution.Name = helpers.Deref(result.AlertResolutionsList[0].ResolutionName)
+			resolution.Description = helpers.Deref(result.AlertResolutionsList[0].Description)
+		}
+	}
+	forwarder.resolutionMutex.Lock()
+	forwarder.resolutions[id] = resolution
+	forwarder.resolutionMutex.Unlock()
+	return resolution
+}
+
+// Silenced returns the name of the first silence rule matching the event, or "".
+func (forwarder *Forwarder) Silenced(event Event) string {
+	now := forwarder.Now()
+	for _, rule := range forwarder.Silences {
+		if rule.Matches(event, now) {
+			return rule.Name
+		}
+	}
+	return ""
+}
+
+// Forward delivers the events that are not silenced to every endpoint, in batches of at most BatchSize. Failed
+// deliveries are returned as *DeliveryError values joined into one error.
+func (forwarder *Forwarder) Forward(ctx context.Context, events []Event) error {
+	_, err := forwarder.forward(ctx, events)
+	return err
+}
+
+// forward is Forward that also returns the events of the batches that failed for at least one endpoint.
+func (forwarder *Forwarder) forward(ctx context.Context, events []Event) ([]Event, error) {
+	var sent []Event
+	var cloudEvents []CloudEvent
+	for _, event := range events {
+		if forwarder.Silenced(event) == "" {
+			sent = append(sent, event)
+			cloudEvents = append(cloudEvents, forwarder.CloudEvent(ctx, event))
+		}
+	}
+	var undelivered []Event
+	var errs []error
+	size := max(1, forwarder.BatchSize)
+	for start := 0; start < len(cloudEvents); start += size {
+		end := min(start+size, len(cloudEvents))
+		failed := false
+		for _, endpoint := range forwarder.endpoints {
+			if err := forwarder.post(ctx, endpoint, cloudEvents[start:end]); err != nil {
+				errs = append(errs, err)
+				failed = true
+			}
+		}
+		if failed {
+			undelivered = append(undelivered, sent[start:end]...)
+		}
+	}
+	return undelivered, errors.Join(errs...)
+}
+
+// Run forwards the events received on the channel until it is closed or the context is cancelled. Events are sent
+// when a batch is full or FlushInterval after the first event of a batch arrived. When the context is cancelled, the
+// events held or already buffered in the channel are sent within ShutdownTimeout. Events that could not be delivered
+// are passed to onError, which may be nil, as an *UndeliveredError.
+func (forwarder *Forwarder) Run(ctx context.Context, events <-chan Event, onError func(error)) error {
+	var pending []Event
+	var flush <-chan time.Time
+	var timer *time.Timer
+	send := func(ctx context.Context) {
+		if timer != nil {
+			timer.Stop()
+			timer, flush = nil, nil
+		}
+		if len(pending) == 0 {
+			return
+		}
+		undelivered, err := forwarder.forward(ctx, pending)
+		if err != nil && onError != nil {
+			onError(&UndeliveredError{Events: undelivered, Err: err})
+		}
+		pending = nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			for drained := false; !drained; {
+				select {
+				case event, ok := <-events:
+					if ok {
+						pending = append(pending, event)
+					} else {
+						drained = true
+					}
+				default:
+					drained = true
+				}
+			}
+			shutdown, cancel := context.WithTimeout(context.WithoutCancel(ctx), forwarder.ShutdownTimeout)
+			send(shutdown)
+			cancel()
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				send(ctx)
+				return nil
+			}
+			pending = append(pending, event)
+			if len(pending) >= forwarder.BatchSize {
+				send(ctx)
+			} else if timer == nil {
+				timer = time.NewTimer(forwarder.FlushInterval)
+				flush = timer.C
+			}
+		case <-flush:
+			timer, flush = nil, nil
+			send(ctx)
+		}
+	}
+}
+
+// post delivers one batch to one endpoint, retrying network errors, 429 and 5xx responses with exponential backoff.
+// A Retry-After header in seconds overrides the backoff.
+func (forwarder *Forwarder) post(ctx context.Context, endpoint Endpoint, batch []CloudEvent) error {
+	var body []byte
+	var err error
+	contentType := CloudEventsBatchContentType
+	if len(batch) == 1 {
+		contentType = CloudEventsContentType
+		body, err = json.Marshal(batch[0])
+	} else {
+		body, err = json.Marshal(batch)
+	}
+	if err != nil {
+		return err
+	}
+
+	delay := forwarder.Backoff
+	failure := &DeliveryError{URL: endpoint.URL}
+	attempts := max(1, forwarder.MaxAttempts)
+	for attempt := 1; attempt <= attempts; attempt++ {
+		failure.Attempts = attempt
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+		if err != nil {
+			failure.Err = err
+			return failure
+		}
+		request.Header.Set("Content-Type", contentType)
+		for key, value := range endpoint.Headers {
+			request.Header.Set(key, value)
+		}
+		if len(endpoint.Secret) > 0 {
+			request.Header.Set(SignatureHeader, Sign(endpoint.Secret, body))
+		}
+
+		retryAfter := time.Duration(0)
+		response, err := forwarder.HTTPClient.Do(request)
+		if err == nil {
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+			if response.StatusCode < 300 {
+				return nil
+			}
+			failure.StatusCode, failure.Err = response.StatusCode, nil
+			if response.StatusCode != http.StatusTooManyRequests && response.StatusCode < 500 {
+				return failure
+			}
+			if seconds, parseErr := strconv.Atoi(response.Header.Get("Retry-After")); parseErr == nil {
+				retryAfter = time.Duration(seconds) * time.Second
+			}
+		} else {
+			failure.StatusCode, failure.Err = 0, err
+		}
+
+		if attempt == attempts {
+			break
+		}
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			failure.Err = ctx.Err()
+			return failure
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+	return failure
+}
+
+// Sign returns the value of the SignatureHeader for the body.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature header value matches the body. Receivers can use it to
+// authenticate forwarded events.
+func VerifySignature(secret []byte, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,246 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkRequest struct {
+	contentType string
+	signature   string
+	body        []byte
+}
+
+// sink records the requests it receives and fails the first failures of them with 503.
+type sink struct {
+	mutex    sync.Mutex
+	requests []sinkRequest
+	failures int
+}
+
+func (sink *sink) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, _ := io.ReadAll(request.Body)
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.failures > 0 {
+		sink.failures--
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	sink.requests = append(sink.requests, sinkRequest{
+		contentType: request.Header.Get("Content-Type"),
+		signature:   request.Header.Get(SignatureHeader),
+		body:        body,
+	})
+}
+
+func (sink *sink) received() []sinkRequest {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return append([]sinkRequest(nil), sink.requests...)
+}
+
+type fakeResolutionClient struct {
+	calls int
+}
+
+func (fake *fakeResolutionClient) GetManagementAlertResolutionWithContext(ctx context.Context, options *backuprecoveryv1.GetManagementAlertResolutionOptions) (*backuprecoveryv1.AlertResolutionsList, *core.DetailedResponse, error) {
+	fake.calls++
+	return &backuprecoveryv1.AlertResolutionsList{AlertResolutionsList: []backuprecoveryv1.AlertResolution{{
+		ResolutionID:   options.ResolutionID,
+		ResolutionName: core.StringPtr("Disk replaced"),
+	}}}, nil, nil
+}
+
+func testEvent(id string, eventType EventType, severity string) Event {
+	alert := FromManagementAlert(&backuprecoveryv1.Alert{
+		ID:            core.StringPtr(id),
+		AlertCategory: core.StringPtr("kBackupRestore"),
+		Severity:      core.StringPtr(severity),
+		ClusterID:     core.Int64Ptr(7),
+		PropertyList:  []backuprecoveryv1.Label{{Key: core.StringPtr("jobName"), Value: core.StringPtr("files")}},
+	})
+	if eventType == EventResolved {
+		alert.State = backuprecoveryv1.Alert_AlertState_Kresolved
+		alert.ResolutionID = "r1"
+	}
+	return Event{Type: eventType, Alert: alert, DetectedAt: base}
+}
+
+func TestForwardSignsAndBatches(t *testing.T) {
+	receiver := &sink{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	secret := []byte("secret")
+	resolutions := &fakeResolutionClient{}
+	forwarder := NewForwarder(Endpoint{URL: server.URL, Secret: secret})
+	forwarder.Resolutions = resolutions
+	forwarder.BatchSize = 2
+
+	err := forwarder.Forward(context.Background(), []Event{
+		testEvent("a1", EventOpened, "kCritical"),
+		testEvent("a2", EventResolved, "kWarning"),
+		testEvent("a3", EventResolved, "kWarning"),
+	})
+	require.Nil(t, err)
+
+	requests := receiver.received()
+	require.Len(t, requests, 2)
+	assert.Equal(t, CloudEventsBatchContentType, requests[0].contentType)
+	assert.Equal(t, CloudEventsContentType, requests[1].contentType)
+	for _, request := range requests {
+		assert.True(t, VerifySignature(secret, request.body, request.signature))
+	}
+
+	var batch []CloudEvent
+	require.Nil(t, json.Unmarshal(requests[0].body, &batch))
+	require.Len(t, batch, 2)
+	assert.Equal(t, "1.0", batch[0].SpecVersion)
+	assert.Equal(t, "com.ibm.backuprecovery.alert.opened", batch[0].Type)
+	assert.Equal(t, "a1", batch[0].Subject)
+	assert.Equal(t, base, batch[0].Time)
+	assert.Equal(t, &Resolution{ID: "r1", Name: "Disk replaced"}, batch[1].Data.Resolution)
+	assert.Equal(t, 1, resolutions.calls)
+
+	// Redelivering the same event yields the same id.
+	assert.Equal(t, batch[0].ID, forwarder.CloudEvent(context.Background(), testEvent("a1", EventOpened, "kCritical")).ID)
+}
+
+func TestForwardRetries(t *testing.T) {
+	receiver := &sink{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	forwarder := NewForwarder(Endpoint{URL: server.URL})
+	forwarder.Backoff = time.Millisecond
+	require.Nil(t, forwarder.Forward(context.Background(), []Event{testEvent("a1", EventOpened, "kCritical")}))
+	assert.Len(t, receiver.received(), 1)
+
+	receiver.failures = 10
+	err := forwarder.Forward(context.Background(), []Event{testEvent("a1", EventOpened, "kCritical")})
+	var failure *DeliveryError
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, http.StatusServiceUnavailable, failure.StatusCode)
+	assert.Equal(t, 4, failure.Attempts)
+}
+
+func TestSilenceRules(t *testing.T) {
+	receiver := &sink{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	forwarder := NewForwarder(Endpoint{URL: server.URL})
+	forwarder.Now = func() time.Time { return base }
+	forwarder.Silences = []SilenceRule{
+		{Name: "info", Severities: []string{"kInfo"}},
+		{Name: "files", ClusterIDs: []int64{7}, Properties: map[string]string{"jobName": "files"}, EventTypes: []EventType{EventSeverityChanged}},
+		{Name: "expired", Categories: []string{"kBackupRestore"}, Until: base},
+	}
+
+	assert.Equal(t, "info", forwarder.Silenced(testEvent("a1", EventOpened, "kInfo")))
+	assert.Equal(t, "files", forwarder.Silenced(testEvent("a2", EventSeverityChanged, "kCritical")))
+	assert.Equal(t, "", forwarder.Silenced(testEvent("a3", EventOpened, "kCritical")))
+
+	require.Nil(t, forwarder.Forward(context.Background(), []Event{
+		testEvent("a1", EventOpened, "kInfo"),
+		testEvent("a3", EventOpened, "kCritical"),
+	}))
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	var event CloudEvent
+	require.Nil(t, json.Unmarshal(requests[0].body, &event))
+	assert.Equal(t, "a3", event.Subject)
+}
+
+func TestRunFlushesOnClose(t *testing.T) {
+	receiver := &sink{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	forwarder := NewForwarder(Endpoint{URL: server.URL})
+	forwarder.FlushInterval = time.Hour
+	events := make(chan Event, 3)
+	events <- testEvent("a1", EventOpened, "kCritical")
+	events <- testEvent("a2", EventOpened, "kCritical")
+	close(events)
+
+	require.Nil(t, forwarder.Run(context.Background(), events, func(err error) { t.Error(err) }))
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, CloudEventsBatchContentType, requests[0].contentType)
+}
+
+func TestRunFlushesOnCancel(t *testing.T) {
+	receiver := &sink{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	forwarder := NewForwarder(Endpoint{URL: server.URL})
+	forwarder.FlushInterval = time.Hour
+	events := make(chan Event, 3)
+	events <- testEvent("a1", EventOpened, "kCritical")
+	events <- testEvent("a2", EventOpened, "kCritical")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, forwarder.Run(ctx, events, func(err error) { t.Error(err) }))
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	var batch []CloudEvent
+	require.Nil(t, json.Unmarshal(requests[0].body, &batch))
+	assert.Len(t, batch, 2)
+}
+
+func TestRunReportsUndeliveredEvents(t *testing.T) {
+	receiver := &sink{failures: 1}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	forwarder := NewForwarder(Endpoint{URL: server.URL})
+	forwarder.BatchSize = 1
+	forwarder.MaxAttempts = 1
+	events := make(chan Event, 2)
+	events <- testEvent("a1", EventOpened, "kCritical")
+	events <- testEvent("a2", EventOpened, "kCritical")
+	close(events)
+
+	var errs []error
+	require.Nil(t, forwarder.Run(context.Background(), events, func(err error) { errs = append(errs, err) }))
+	require.Len(t, errs, 1)
+	var undelivered *UndeliveredError
+	require.True(t, errors.As(errs[0], &undelivered))
+	require.Len(t, undelivered.Events, 1)
+	assert.Equal(t, "a1", undelivered.Events[0].Alert.ID)
+	var failure *DeliveryError
+	assert.True(t, errors.As(errs[0], &failure))
+	assert.Len(t, receiver.received(), 1)
+}
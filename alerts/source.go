@@ -0,0 +1,191 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+)
+
+// Source lists alerts from one of the alert APIs.
+type Source interface {
+	// Alerts returns the alerts raised within [from, to).
+	Alerts(ctx context.Context, from time.Time, to time.Time) ([]Alert, error)
+
+	// Lookup returns the current state of the given alerts.
+	Lookup(ctx context.Context, ids []string) ([]Alert, error)
+}
+
+// ManagementAlertsClient is the subset of the management SRE API used by ManagementSource.
+type ManagementAlertsClient interface {
+	GetManagementAlertsWithContext(ctx context.Context, getManagementAlertsOptions *backuprecoveryv1.GetManagementAlertsOptions) (result *backuprecoveryv1.AlertsList, response *core.DetailedResponse, err error)
+}
+
+var _ ManagementAlertsClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// AlertsClient is the subset of the management SRE API used by ClusterSource.
+type AlertsClient interface {
+	GetAlertsWithContext(ctx context.Context, getAlertsOptions *backuprecoveryv1.GetAlertsOptions) (result *backuprecoveryv1.AlertList, response *core.DetailedResponse, err error)
+}
+
+var _ AlertsClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// defaultMaxAlerts is the largest page the alert APIs return.
+const defaultMaxAlerts = 1000
+
+// minWindow is the narrowest time window that is split further when a page is full.
+const minWindow = time.Second
+
+// ManagementSource reads alerts with GetManagementAlerts.
+type ManagementSource struct {
+	client ManagementAlertsClient
+	filter backuprecoveryv1.GetManagementAlertsOptions
+
+	// MaxAlerts is the page size. A full page makes the source split the time window and query both halves.
+	// Defaults to 1000.
+	MaxAlerts int64
+}
+
+// NewManagementSource : Instantiate ManagementSource. The optional filter restricts the alerts, e.g. by severity,
+// category or cluster; its time range, id list and page size are set by the source.
+func NewManagementSource(client ManagementAlertsClient, filter *backuprecoveryv1.GetManagementAlertsOptions) *ManagementSource {
+	source := &ManagementSource{client: client, MaxAlerts: defaultMaxAlerts}
+	if filter != nil {
+		source.filter = *filter
+	}
+	return source
+}
+
+// Alerts implements Source.
+func (source *ManagementSource) Alerts(ctx context.Context, from time.Time, to time.Time) ([]Alert, error) {
+	return window(ctx, from, to, source.MaxAlerts, func(from time.Time, to time.Time) ([]Alert, error) {
+		options := source.filter
+		options.AlertIdList = nil
+		options.StartDateUsecs = core.Int64Ptr(from.UnixMicro())
+		options.EndDateUsecs = core.Int64Ptr(to.UnixMicro())
+		options.MaxAlerts = core.Int64Ptr(source.MaxAlerts)
+		return source.list(ctx, &options)
+	})
+}
+
+// Lookup implements Source.
+func (source *ManagementSource) Lookup(ctx context.Context, ids []string) ([]Alert, error) {
+	options := source.filter
+	options.AlertIdList = ids
+	options.StartDateUsecs = nil
+	options.EndDateUsecs = nil
+	options.MaxAlerts = core.Int64Ptr(int64(len(ids)))
+	return source.list(ctx, &options)
+}
+
+func (source *ManagementSource) list(ctx context.Context, options *backuprecoveryv1.GetManagementAlertsOptions) ([]Alert, error) {
+	result, _, err := source.client.GetManagementAlertsWithContext(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []Alert
+	if result != nil {
+		for i := range result.AlertsList {
+			alerts = append(alerts, FromManagementAlert(&result.AlertsList[i]))
+		}
+	}
+	return alerts, nil
+}
+
+// ClusterSource reads alerts with GetAlerts.
+type ClusterSource struct {
+	client AlertsClient
+	filter backuprecoveryv1.GetAlertsOptions
+
+	// MaxAlerts is the page size. A full page makes the source split the time window and query both halves.
+	// Defaults to 1000.
+	MaxAlerts int64
+}
+
+// NewClusterSource : Instantiate ClusterSource. The optional filter restricts the alerts; its time range, id list
+// and page size are set by the source.
+func NewClusterSource(client AlertsClient, filter *backuprecoveryv1.GetAlertsOptions) *ClusterSource {
+	source := &ClusterSource{client: client, MaxAlerts: defaultMaxAlerts}
+	if filter != nil {
+		source.filter = *filter
+	}
+	return source
+}
+
+// Alerts implements Source.
+func (source *ClusterSource) Alerts(ctx context.Context, from time.Time, to time.Time) ([]Alert, error) {
+	return window(ctx, from, to, source.MaxAlerts, func(from time.Time, to time.Time) ([]Alert, error) {
+		options := source.filter
+		options.AlertIds = nil
+		options.StartTimeUsecs = core.Int64Ptr(from.UnixMicro())
+		options.EndTimeUsecs = core.Int64Ptr(to.UnixMicro())
+		options.MaxAlerts = core.Int64Ptr(source.MaxAlerts)
+		return source.list(ctx, &options)
+	})
+}
+
+// Lookup implements Source.
+func (source *ClusterSource) Lookup(ctx context.Context, ids []string) ([]Alert, error) {
+	options := source.filter
+	options.AlertIds = ids
+	options.StartTimeUsecs = nil
+	options.EndTimeUsecs = nil
+	options.MaxAlerts = core.Int64Ptr(int64(len(ids)))
+	return source.list(ctx, &options)
+}
+
+func (source *ClusterSource) list(ctx context.Context, options *backuprecoveryv1.GetAlertsOptions) ([]Alert, error) {
+	result, _, err := source.client.GetAlertsWithContext(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []Alert
+	if result != nil {
+		for i := range result.Alerts {
+			alerts = append(alerts, FromAlertInfo(&result.Alerts[i]))
+		}
+	}
+	return alerts, nil
+}
+
+// window fetches the alerts of [from, to), halving the window while a page comes back full so that no alert is lost
+// to the page cap.
+func window(ctx context.Context, from time.Time, to time.Time, max int64, fetch func(time.Time, time.Time) ([]Alert, error)) ([]Alert, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	alerts, err := fetch(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(alerts)) < max || to.Sub(from) <= minWindow {
+		return alerts, nil
+	}
+	middle := from.Add(to.Sub(from) / 2)
+	older, err := window(ctx, from, middle, max, fetch)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := window(ctx, middle, to, max, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return append(older, newer...), nil
+}
@@ -0,0 +1,272 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// EventType is the kind of state change reported by the watcher.
+type EventType string
+
+const (
+	// EventOpened is emitted the first time an unresolved alert is seen, and when a resolved alert opens again.
+	EventOpened EventType = "opened"
+	// EventSeverityChanged is emitted when an open alert changes severity.
+	EventSeverityChanged EventType = "severity_changed"
+	// EventResolved is emitted when an alert is resolved, or when an open alert is no longer returned by the source
+	// for longer than the retention.
+	EventResolved EventType = "resolved"
+)
+
+// Event is a state change of one alert.
+type Event struct {
+	Type  EventType `json:"type"`
+	Alert Alert     `json:"alert"`
+
+	// PreviousSeverity is set for EventSeverityChanged.
+	PreviousSeverity string `json:"previousSeverity,omitempty"`
+
+	// Vanished is set for an EventResolved of an open alert the source no longer returns, e.g. because it was
+	// purged. Its Alert only carries the ID, the severity and the resolved state.
+	Vanished bool `json:"vanished,omitempty"`
+
+	// DetectedAt is the time the watcher noticed the change.
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// TrackedAlert is the last known state of an alert.
+type TrackedAlert struct {
+	Severity string    `json:"severity"`
+	Resolved bool      `json:"resolved,omitempty"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Cursor is the persisted state of a watcher: the high-water mark of the last poll and the alerts it has seen.
+type Cursor struct {
+	HighWater time.Time               `json:"highWater"`
+	Alerts    map[string]TrackedAlert `json:"alerts"`
+}
+
+// CursorStore persists the cursor between restarts.
+type CursorStore interface {
+	// Load returns the saved cursor, or nil when none was saved yet.
+	Load() (*Cursor, error)
+	Save(cursor *Cursor) error
+}
+
+// FileStore is a CursorStore backed by a JSON file.
+type FileStore = helpers.JSONFile[Cursor]
+
+// lookupBatch is the number of alert ids refreshed per Lookup call.
+const lookupBatch = 100
+
+// Watcher polls a source and reports each alert state change exactly once.
+//
+// Each poll asks for the alerts raised since the high-water mark of the previous poll, minus Overlap to tolerate
+// clock skew and late indexing, and refreshes the alerts that are still open because their resolution does not
+// show up in a time-filtered query.
+type Watcher struct {
+	source Source
+	store  CursorStore
+	cursor *Cursor
+
+	// Interval is the delay between polls in Watch. Defaults to one minute.
+	Interval time.Duration
+
+	// Overlap is subtracted from the high-water mark of each query. Defaults to ten minutes.
+	Overlap time.Duration
+
+	// InitialLookback is how far back the first poll looks when there is no saved cursor. Defaults to one hour.
+	InitialLookback time.Duration
+
+	// Retention is how long resolved alerts are remembered to suppress duplicates, and how long an open alert may be
+	// missing from the source before it is reported as resolved and forgotten. Defaults to seven days.
+	Retention time.Duration
+
+	// OnError receives poll errors in Watch, which keeps polling. Errors are dropped when nil.
+	OnError func(error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewWatcher : Instantiate Watcher. The store may be nil, in which case the cursor is only kept in memory.
+func NewWatcher(source Source, store CursorStore) *Watcher {
+	return &Watcher{
+		source:          source,
+		store:           store,
+		Interval:        time.Minute,
+		Overlap:         10 * time.Minute,
+		InitialLookback: time.Hour,
+		Retention:       7 * 24 * time.Hour,
+		Now:             time.Now,
+	}
+}
+
+// Poll queries the source once and returns the state changes since the previous poll. The cursor is saved before
+// Poll returns, so a restarted watcher does not report the same changes again.
+func (watcher *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	if err := watcher.load(); err != nil {
+		return nil, err
+	}
+	now := watcher.Now().UTC()
+	from := now.Add(-watcher.InitialLookback)
+	if !watcher.cursor.HighWater.IsZero() {
+		from = watcher.cursor.HighWater.Add(-watcher.Overlap)
+	}
+
+	raised, err := watcher.source.Alerts(ctx, from, now)
+	if err != nil {
+		return nil, fmt.Errorf("listing alerts: %w", err)
+	}
+	seen := map[string]bool{}
+	var current []Alert
+	for _, alert := range raised {
+		if alert.ID != "" && !seen[alert.ID] {
+			seen[alert.ID] = true
+			current = append(current, alert)
+		}
+	}
+
+	var open []string
+	for id, tracked := range watcher.cursor.Alerts {
+		if !tracked.Resolved && !seen[id] {
+			open = append(open, id)
+		}
+	}
+	sort.Strings(open)
+	for len(open) > 0 {
+		batch := open[:min(lookupBatch, len(open))]
+		open = open[len(batch):]
+		refreshed, err := watcher.source.Lookup(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("refreshing open alerts: %w", err)
+		}
+		for _, alert := range refreshed {
+			if alert.ID != "" && !seen[alert.ID] {
+				seen[alert.ID] = true
+				current = append(current, alert)
+			}
+		}
+	}
+
+	sort.SliceStable(current, func(i, j int) bool { return current[i].LastSeen.Before(current[j].LastSeen) })
+	var events []Event
+	for _, alert := range current {
+		events = append(events, watcher.track(alert, now)...)
+	}
+
+	watcher.cursor.HighWater = now
+	var vanished []Event
+	for id, tracked := range watcher.cursor.Alerts {
+		if now.Sub(tracked.LastSeen) <= watcher.Retention {
+			continue
+		}
+		if !tracked.Resolved {
+			alert := Alert{ID: id, State: backuprecoveryv1.Alert_AlertState_Kresolved, Severity: tracked.Severity}
+			vanished = append(vanished, Event{Type: EventResolved, Alert: alert, Vanished: true, DetectedAt: now})
+		}
+		delete(watcher.cursor.Alerts, id)
+	}
+	sort.Slice(vanished, func(i, j int) bool { return vanished[i].Alert.ID < vanished[j].Alert.ID })
+	events = append(events, vanished...)
+	if watcher.store != nil {
+		if err := watcher.store.Save(watcher.cursor); err != nil {
+			return events, fmt.Errorf("saving cursor: %w", err)
+		}
+	}
+	return events, nil
+}
+
+// track updates the known state of the alert and returns the resulting events.
+func (watcher *Watcher) track(alert Alert, now time.Time) []Event {
+	tracked, known := watcher.cursor.Alerts[alert.ID]
+	watcher.cursor.Alerts[alert.ID] = TrackedAlert{Severity: alert.Severity, Resolved: alert.Resolved(), LastSeen: now}
+
+	event := func(eventType EventType) Event {
+		return Event{Type: eventType, Alert: alert, DetectedAt: now}
+	}
+	switch {
+	case !known || (tracked.Resolved && !alert.Resolved()):
+		if alert.Resolved() {
+			// Raised and resolved between two polls.
+			return []Event{event(EventOpened), event(EventResolved)}
+		}
+		return []Event{event(EventOpened)}
+	case tracked.Resolved:
+		return nil
+	case alert.Resolved():
+		return []Event{event(EventResolved)}
+	case alert.Severity != tracked.Severity:
+		changed := event(EventSeverityChanged)
+		changed.PreviousSeverity = tracked.Severity
+		return []Event{changed}
+	}
+	return nil
+}
+
+func (watcher *Watcher) load() error {
+	if watcher.cursor != nil {
+		return nil
+	}
+	if watcher.store != nil {
+		cursor, err := watcher.store.Load()
+		if err != nil {
+			return fmt.Errorf("loading cursor: %w", err)
+		}
+		watcher.cursor = cursor
+	}
+	if watcher.cursor == nil {
+		watcher.cursor = &Cursor{}
+	}
+	if watcher.cursor.Alerts == nil {
+		watcher.cursor.Alerts = map[string]TrackedAlert{}
+	}
+	return nil
+}
+
+// Watch polls every Interval and sends the events to the channel until the context is cancelled. It does not close
+// the channel.
+func (watcher *Watcher) Watch(ctx context.Context, events chan<- Event) error {
+	ticker := time.NewTicker(watcher.Interval)
+	defer ticker.Stop()
+	for {
+		polled, err := watcher.Poll(ctx)
+		if err != nil && watcher.OnError != nil {
+			watcher.OnError(err)
+		}
+		for _, event := range polled {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
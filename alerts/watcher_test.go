@@ -0,0 +1,221 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeManagementClient struct {
+	alerts []backuprecoveryv1.Alert
+	calls  int
+}
+
+func managementAlert(id string, raised time.Time, severity string, state string) backuprecoveryv1.Alert {
+	return backuprecoveryv1.Alert{
+		ID:                   core.StringPtr(id),
+		AlertCategory:        core.StringPtr("kBackupRestore"),
+		AlertState:           core.StringPtr(state),
+		Severity:             core.StringPtr(severity),
+		ClusterID:            core.Int64Ptr(7),
+		ClusterName:          core.StringPtr("prod"),
+		FirstTimestampUsecs:  core.Int64Ptr(raised.UnixMicro()),
+		LatestTimestampUsecs: core.Int64Ptr(raised.UnixMicro()),
+		AlertDocument:        &backuprecoveryv1.AlertDocument{AlertName: core.StringPtr("BackupFailed")},
+		PropertyList:         []backuprecoveryv1.Label{{Key: core.StringPtr("jobName"), Value: core.StringPtr("files")}},
+	}
+}
+
+func (fake *fakeManagementClient) GetManagementAlertsWithContext(ctx context.Context, options *backuprecoveryv1.GetManagementAlertsOptions) (*backuprecoveryv1.AlertsList, *core.DetailedResponse, error) {
+	fake.calls++
+	result := &backuprecoveryv1.AlertsList{}
+	for _, alert := range fake.alerts {
+		if int64(len(result.AlertsList)) == *options.MaxAlerts {
+			break
+		}
+		if options.AlertIdList != nil {
+			for _, id := range options.AlertIdList {
+				if id == *alert.ID {
+					result.AlertsList = append(result.AlertsList, alert)
+				}
+			}
+			continue
+		}
+		raised := *alert.FirstTimestampUsecs
+		if raised >= *options.StartDateUsecs && raised < *options.EndDateUsecs {
+			result.AlertsList = append(result.AlertsList, alert)
+		}
+	}
+	return result, nil, nil
+}
+
+func (fake *fakeManagementClient) set(id string, severity string, state string) {
+	for i := range fake.alerts {
+		if *fake.alerts[i].ID == id {
+			fake.alerts[i].Severity = core.StringPtr(severity)
+			fake.alerts[i].AlertState = core.StringPtr(state)
+		}
+	}
+}
+
+func TestSourceSplitsFullPages(t *testing.T) {
+	client := &fakeManagementClient{}
+	for i := 0; i < 5; i++ {
+		client.alerts = append(client.alerts, managementAlert(fmt.Sprint(i), base.Add(time.Duration(i)*time.Minute), "kWarning", "kOpen"))
+	}
+	source := NewManagementSource(client, nil)
+	source.MaxAlerts = 2
+
+	alerts, err := source.Alerts(context.Background(), base, base.Add(time.Hour))
+	require.Nil(t, err)
+	assert.Len(t, alerts, 5)
+	assert.Greater(t, client.calls, 1)
+
+	alert := alerts[0]
+	assert.Equal(t, "BackupFailed", alert.Name)
+	assert.Equal(t, map[string]string{"jobName": "files"}, alert.Properties)
+	assert.Equal(t, base, alert.FirstSeen)
+}
+
+func TestWatcherEmitsStateChanges(t *testing.T) {
+	client := &fakeManagementClient{alerts: []backuprecoveryv1.Alert{
+		managementAlert("a1", base.Add(-30*time.Minute), "kWarning", "kOpen"),
+		managementAlert("a2", base.Add(-20*time.Minute), "kCritical", "kOpen"),
+	}}
+	store := FileStore{Path: filepath.Join(t.TempDir(), "cursor.json")}
+	now := base
+	newWatcher := func() *Watcher {
+		watcher := NewWatcher(NewManagementSource(client, nil), store)
+		watcher.Now = func() time.Time { return now }
+		return watcher
+	}
+	types := func(events []Event) []string {
+		var all []string
+		for _, event := range events {
+			all = append(all, event.Alert.ID+":"+string(event.Type))
+		}
+		return all
+	}
+
+	watcher := newWatcher()
+	events, err := watcher.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a1:opened", "a2:opened"}, types(events))
+
+	// Both alerts are now outside the time window, so their changes are only found by refreshing open alerts.
+	now = base.Add(2 * time.Hour)
+	client.set("a1", "kCritical", "kOpen")
+	client.set("a2", "kCritical", "kResolved")
+	events, err = watcher.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a1:severity_changed", "a2:resolved"}, types(events))
+	assert.Equal(t, "kWarning", events[0].PreviousSeverity)
+
+	now = now.Add(time.Minute)
+	events, err = watcher.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, events)
+
+	// A restarted watcher continues from the saved cursor.
+	client.alerts = append(client.alerts, managementAlert("a3", now.Add(30*time.Second), "kInfo", "kResolved"))
+	now = now.Add(time.Minute)
+	restarted := newWatcher()
+	events, err = restarted.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a3:opened", "a3:resolved"}, types(events))
+
+	cursor, err := store.Load()
+	require.Nil(t, err)
+	assert.Equal(t, now, cursor.HighWater)
+	assert.Len(t, cursor.Alerts, 3)
+}
+
+func TestWatcherForgetsVanishedAlerts(t *testing.T) {
+	client := &fakeManagementClient{alerts: []backuprecoveryv1.Alert{
+		managementAlert("a1", base.Add(-30*time.Minute), "kCritical", "kOpen"),
+		managementAlert("a2", base.Add(-20*time.Minute), "kWarning", "kOpen"),
+	}}
+	now := base
+	watcher := NewWatcher(NewManagementSource(client, nil), nil)
+	watcher.Retention = 24 * time.Hour
+	watcher.Now = func() time.Time { return now }
+	_, err := watcher.Poll(context.Background())
+	require.Nil(t, err)
+
+	// a1 is purged from the source and is no longer returned by lookups.
+	client.alerts = client.alerts[1:]
+	now = now.Add(12 * time.Hour)
+	events, err := watcher.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, events)
+	assert.Len(t, watcher.cursor.Alerts, 2)
+
+	now = now.Add(13 * time.Hour)
+	events, err = watcher.Poll(context.Background())
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventResolved, events[0].Type)
+	assert.True(t, events[0].Vanished)
+	assert.Equal(t, "a1", events[0].Alert.ID)
+	assert.Equal(t, "kCritical", events[0].Alert.Severity)
+	assert.True(t, events[0].Alert.Resolved())
+	assert.Equal(t, []string{"a2"}, keys(watcher.cursor.Alerts))
+
+	lookups := client.calls
+	now = now.Add(time.Hour)
+	events, err = watcher.Poll(context.Background())
+	require.Nil(t, err)
+	assert.Empty(t, events)
+	// Only the window query and the refresh of a2 remain.
+	assert.Equal(t, lookups+2, client.calls)
+}
+
+func keys(alerts map[string]TrackedAlert) []string {
+	var ids []string
+	for id := range alerts {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestWatch(t *testing.T) {
+	client := &fakeManagementClient{alerts: []backuprecoveryv1.Alert{managementAlert("a1", base, "kWarning", "kOpen")}}
+	watcher := NewWatcher(NewManagementSource(client, nil), nil)
+	watcher.Now = func() time.Time { return base.Add(time.Minute) }
+	watcher.Interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan Event)
+	done := make(chan error)
+	go func() { done <- watcher.Watch(ctx, events) }()
+
+	event := <-events
+	assert.Equal(t, EventOpened, event.Type)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
@@ -0,0 +1,391 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package anomaly flags protection group runs whose statistics deviate sharply from the recent history of the same
+// group or object, such as a surge of changed data during ransomware encryption or a collapse in backed up data
+// after a silent exclusion.
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+	UpdateProtectionGroupRunWithContext(ctx context.Context, updateProtectionGroupRunOptions *backuprecoveryv1.UpdateProtectionGroupRunOptions) (result *backuprecoveryv1.UpdateProtectionGroupRunResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Metric is a run statistic that is baselined.
+type Metric string
+
+const (
+	// MetricLogicalBytes is the logical size of the protected data.
+	MetricLogicalBytes Metric = "logicalBytes"
+	// MetricBytesRead is the amount of data read from the source, which tracks the changed data of incremental runs.
+	MetricBytesRead Metric = "bytesRead"
+	// MetricBytesWritten is the amount of data written to the cluster after reduction.
+	MetricBytesWritten Metric = "bytesWritten"
+	// MetricDuration is the run duration in seconds.
+	MetricDuration Metric = "durationSeconds"
+	// MetricObjects is the number of successful objects of a run, or the number of files of an object snapshot.
+	MetricObjects Metric = "objects"
+)
+
+// AllMetrics lists every metric in a stable order.
+var AllMetrics = []Metric{MetricLogicalBytes, MetricBytesRead, MetricBytesWritten, MetricDuration, MetricObjects}
+
+// Direction tells whether an anomalous value lies above or below the baseline.
+type Direction string
+
+const (
+	// DirectionSpike means the value is abnormally high.
+	DirectionSpike Direction = "spike"
+	// DirectionCollapse means the value is abnormally low.
+	DirectionCollapse Direction = "collapse"
+)
+
+// Finding is one anomalous metric of one run. ObjectID is zero for findings on the run as a whole.
+type Finding struct {
+	GroupID    string    `json:"groupId"`
+	GroupName  string    `json:"groupName,omitempty"`
+	RunID      string    `json:"runId"`
+	RunType    string    `json:"runType,omitempty"`
+	RunTime    time.Time `json:"runTime"`
+	ObjectID   int64     `json:"objectId,omitempty"`
+	ObjectName string    `json:"objectName,omitempty"`
+	Metric     Metric    `json:"metric"`
+	Value      float64   `json:"value"`
+	Baseline   Baseline  `json:"baseline"`
+	Score      float64   `json:"score"`
+	Direction  Direction `json:"direction"`
+}
+
+// Hold records a legal hold placed on a suspicious run.
+type Hold struct {
+	GroupID string `json:"groupId"`
+	RunID   string `json:"runId"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report is the outcome of an analysis.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Findings    []Finding `json:"findings"`
+	Holds       []Hold    `json:"holds,omitempty"`
+}
+
+// HoldSpikes is a hold policy that places runs on legal hold when any of their metrics spiked.
+func HoldSpikes(finding Finding) bool {
+	return finding.Direction == DirectionSpike
+}
+
+// Analyzer baselines run statistics and flags outliers.
+type Analyzer struct {
+	client   Client
+	tenantID string
+
+	// Metrics selects the metrics to analyze. Defaults to AllMetrics.
+	Metrics []Metric
+
+	// NumRuns is the number of runs fetched per group. Defaults to 60.
+	NumRuns int64
+
+	// Window is the number of preceding successful runs of the same type that form the baseline. Defaults to 20.
+	Window int
+
+	// MinSamples is the minimum baseline size below which a run is not scored. Defaults to 5.
+	MinSamples int
+
+	// Threshold is the absolute score from which a value is anomalous. Defaults to 4.
+	Threshold float64
+
+	// MinSpread floors the baseline spread at this fraction of the median. Defaults to 0.1.
+	MinSpread float64
+
+	// Since limits scoring to runs that started at or after it; earlier runs only serve as baseline.
+	Since time.Time
+
+	// HoldPolicy, when set, selects the findings whose runs are placed on legal hold.
+	HoldPolicy func(Finding) bool
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewAnalyzer : Instantiate Analyzer
+func NewAnalyzer(client Client, tenantID string) *Analyzer {
+	return &Analyzer{
+		client:     client,
+		tenantID:   tenantID,
+		Metrics:    AllMetrics,
+		NumRuns:    60,
+		Window:     20,
+		MinSamples: 5,
+		Threshold:  4,
+		MinSpread:  0.1,
+		Now:        time.Now,
+	}
+}
+
+// sample is the statistics of one run, or of one object within a run.
+type sample struct {
+	run    *backuprecoveryv1.ProtectionGroupRun
+	start  time.Time
+	values map[Metric]float64
+}
+
+// seriesKey separates baselines per object and per run type, since full runs read far more data than incremental
+// ones.
+type seriesKey struct {
+	objectID int64
+	runType  string
+}
+
+type series struct {
+	objectName string
+	samples    []sample
+}
+
+// Analyze scores the runs of the given protection groups, or of every active group when none are given, and places
+// suspicious runs on legal hold when a HoldPolicy is set.
+func (analyzer *Analyzer) Analyze(ctx context.Context, groupIDs ...string) (*Report, error) {
+	if len(groupIDs) == 0 {
+		groups, _, err := analyzer.client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+			XIBMTenantID: core.StringPtr(analyzer.tenantID),
+			IsDeleted:    core.BoolPtr(false),
+			IsActive:     core.BoolPtr(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing protection groups: %w", err)
+		}
+		if groups != nil {
+			for _, group := range groups.ProtectionGroups {
+				if group.ID != nil {
+					groupIDs = append(groupIDs, *group.ID)
+				}
+			}
+		}
+	}
+
+	report := &Report{GeneratedAt: analyzer.Now().UTC(), Findings: []Finding{}}
+	onHold := map[string]bool{}
+	for _, groupID := range groupIDs {
+		result, _, err := analyzer.client.GetProtectionGroupRunsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupRunsOptions{
+			ID:                   core.StringPtr(groupID),
+			XIBMTenantID:         core.StringPtr(analyzer.tenantID),
+			NumRuns:              core.Int64Ptr(analyzer.NumRuns),
+			IncludeObjectDetails: core.BoolPtr(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing runs of protection group '%s': %w", groupID, err)
+		}
+		if result == nil {
+			continue
+		}
+		for i := range result.Runs {
+			run := &result.Runs[i]
+			if run.ID != nil && run.OnLegalHold != nil && *run.OnLegalHold {
+				onHold[groupID+"/"+*run.ID] = true
+			}
+		}
+		report.Findings = append(report.Findings, analyzer.score(groupID, result.Runs)...)
+	}
+
+	if analyzer.HoldPolicy != nil {
+		for _, finding := range report.Findings {
+			key := finding.GroupID + "/" + finding.RunID
+			if onHold[key] || !analyzer.HoldPolicy(finding) {
+				continue
+			}
+			onHold[key] = true
+			report.Holds = append(report.Holds, analyzer.hold(ctx, finding.GroupID, finding.RunID))
+		}
+	}
+	return report, nil
+}
+
+// score builds the series of one group and scores each sample against the samples preceding it.
+func (analyzer *Analyzer) score(groupID string, runs []backuprecoveryv1.ProtectionGroupRun) []Finding {
+	all := map[seriesKey]*series{}
+	var keys []seriesKey
+	add := func(key seriesKey, name string, s sample) {
+		if all[key] == nil {
+			all[key] = &series{objectName: name}
+			keys = append(keys, key)
+		}
+		all[key].samples = append(all[key].samples, s)
+	}
+	for i := range runs {
+		run := &runs[i]
+		info := run.LocalBackupInfo
+		if run.ID == nil || info == nil || info.StartTimeUsecs == nil || !succeeded(helpers.Deref(info.Status)) {
+			continue
+		}
+		runType := helpers.Deref(info.RunType)
+		start := time.UnixMicro(*info.StartTimeUsecs).UTC()
+		add(seriesKey{runType: runType}, "", sample{run: run, start: start, values: runValues(info)})
+		for _, object := range run.Objects {
+			if object.Object == nil || object.Object.ID == nil || object.LocalSnapshotInfo == nil {
+				continue
+			}
+			snapshot := object.LocalSnapshotInfo.SnapshotInfo
+			if snapshot == nil || !snapshotSucceeded(helpers.Deref(snapshot.Status)) {
+				continue
+			}
+			add(seriesKey{objectID: *object.Object.ID, runType: runType}, helpers.Deref(object.Object.Name),
+				sample{run: run, start: start, values: snapshotValues(snapshot)})
+		}
+	}
+
+	var findings []Finding
+	for _, key := range keys {
+		s := all[key]
+		sort.SliceStable(s.samples, func(i, j int) bool { return s.samples[i].start.Before(s.samples[j].start) })
+		for i, current := range s.samples {
+			if current.start.Before(analyzer.Since) || i < analyzer.MinSamples {
+				continue
+			}
+			history := s.samples[max(0, i-analyzer.Window):i]
+			for _, metric := range analyzer.Metrics {
+				value, ok := current.values[metric]
+				if !ok {
+					continue
+				}
+				var values []float64
+				for _, previous := range history {
+					if v, ok := previous.values[metric]; ok {
+						values = append(values, v)
+					}
+				}
+				if len(values) < analyzer.MinSamples {
+					continue
+				}
+				baseline := NewBaseline(values)
+				score := baseline.Score(value, analyzer.MinSpread)
+				if score < analyzer.Threshold && score > -analyzer.Threshold {
+					continue
+				}
+				direction := DirectionSpike
+				if score < 0 {
+					direction = DirectionCollapse
+				}
+				findings = append(findings, Finding{
+					GroupID:    groupID,
+					GroupName:  helpers.Deref(current.run.ProtectionGroupName),
+					RunID:      *current.run.ID,
+					RunType:    key.runType,
+					RunTime:    current.start,
+					ObjectID:   key.objectID,
+					ObjectName: s.objectName,
+					Metric:     metric,
+					Value:      value,
+					Baseline:   baseline,
+					Score:      score,
+					Direction:  direction,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if !findings[i].RunTime.Equal(findings[j].RunTime) {
+			return findings[i].RunTime.Before(findings[j].RunTime)
+		}
+		return findings[i].ObjectID < findings[j].ObjectID
+	})
+	return findings
+}
+
+func (analyzer *Analyzer) hold(ctx context.Context, groupID string, runID string) Hold {
+	hold := Hold{GroupID: groupID, RunID: runID}
+	result, _, err := analyzer.client.UpdateProtectionGroupRunWithContext(ctx, &backuprecoveryv1.UpdateProtectionGroupRunOptions{
+		ID:           core.StringPtr(groupID),
+		XIBMTenantID: core.StringPtr(analyzer.tenantID),
+		UpdateProtectionGroupRunParams: []backuprecoveryv1.UpdateProtectionGroupRunParams{{
+			RunID:               core.StringPtr(runID),
+			LocalSnapshotConfig: &backuprecoveryv1.UpdateLocalSnapshotConfig{EnableLegalHold: core.BoolPtr(true)},
+		}},
+	})
+	if err != nil {
+		hold.Error = err.Error()
+		return hold
+	}
+	if result != nil {
+		for _, failed := range result.FailedRuns {
+			if helpers.Deref(failed.RunID) == runID {
+				hold.Error = helpers.Deref(failed.ErrorMessage)
+			}
+		}
+	}
+	return hold
+}
+
+func runValues(info *backuprecoveryv1.BackupRunSummary) map[Metric]float64 {
+	values := map[Metric]float64{}
+	addStats(values, info.LocalSnapshotStats)
+	if info.EndTimeUsecs != nil && info.StartTimeUsecs != nil {
+		values[MetricDuration] = float64(*info.EndTimeUsecs-*info.StartTimeUsecs) / 1e6
+	}
+	if info.SuccessfulObjectsCount != nil {
+		values[MetricObjects] = float64(*info.SuccessfulObjectsCount)
+	}
+	return values
+}
+
+func snapshotValues(info *backuprecoveryv1.SnapshotInfo) map[Metric]float64 {
+	values := map[Metric]float64{}
+	addStats(values, info.Stats)
+	if info.EndTimeUsecs != nil && info.StartTimeUsecs != nil {
+		values[MetricDuration] = float64(*info.EndTimeUsecs-*info.StartTimeUsecs) / 1e6
+	}
+	if info.TotalFileCount != nil {
+		values[MetricObjects] = float64(*info.TotalFileCount)
+	}
+	return values
+}
+
+func addStats(values map[Metric]float64, stats *backuprecoveryv1.BackupDataStats) {
+	if stats == nil {
+		return
+	}
+	if stats.LogicalSizeBytes != nil {
+		values[MetricLogicalBytes] = float64(*stats.LogicalSizeBytes)
+	}
+	if stats.BytesRead != nil {
+		values[MetricBytesRead] = float64(*stats.BytesRead)
+	}
+	if stats.BytesWritten != nil {
+		values[MetricBytesWritten] = float64(*stats.BytesWritten)
+	}
+}
+
+func succeeded(status string) bool {
+	return status == backuprecoveryv1.BackupRunSummary_Status_Succeeded || status == backuprecoveryv1.BackupRunSummary_Status_Succeededwithwarning
+}
+
+func snapshotSucceeded(status string) bool {
+	return status == backuprecoveryv1.SnapshotInfo_Status_Ksuccessful || status == backuprecoveryv1.SnapshotInfo_Status_Kwarning
+}
@@ -0,0 +1,148 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+const gib = 1 << 30
+
+func TestBaseline(t *testing.T) {
+	baseline := NewBaseline([]float64{10, 12, 11, 13, 100})
+	assert.Equal(t, Baseline{Samples: 5, Median: 12, MAD: 1}, baseline)
+	assert.InDelta(t, 0.0, baseline.Score(12, 0), 0.001)
+	assert.InDelta(t, -2/madScale, baseline.Score(10, 0), 0.001)
+	// The spread is floored at half the median, i.e. 6.
+	assert.InDelta(t, 3.0, baseline.Score(30, 0.5), 0.001)
+}
+
+type fakeClient struct {
+	runs    []backuprecoveryv1.ProtectionGroupRun
+	updates []*backuprecoveryv1.UpdateProtectionGroupRunOptions
+}
+
+func (fake *fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: []backuprecoveryv1.ProtectionGroupResponse{{ID: core.StringPtr("pg-1")}}}, nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: fake.runs}, nil, nil
+}
+
+func (fake *fakeClient) UpdateProtectionGroupRunWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionGroupRunOptions) (*backuprecoveryv1.UpdateProtectionGroupRunResponse, *core.DetailedResponse, error) {
+	fake.updates = append(fake.updates, options)
+	return &backuprecoveryv1.UpdateProtectionGroupRunResponse{SuccessfulRunIds: []string{*options.UpdateProtectionGroupRunParams[0].RunID}}, nil, nil
+}
+
+// run builds a successful incremental run whose single object read the given amount of data.
+func run(index int, logical int64, read int64) backuprecoveryv1.ProtectionGroupRun {
+	start := base.Add(time.Duration(index) * 24 * time.Hour)
+	end := start.Add(time.Hour)
+	stats := &backuprecoveryv1.BackupDataStats{LogicalSizeBytes: core.Int64Ptr(logical), BytesRead: core.Int64Ptr(read)}
+	return backuprecoveryv1.ProtectionGroupRun{
+		ID:                  core.StringPtr(fmt.Sprintf("run-%d", index)),
+		ProtectionGroupName: core.StringPtr("files"),
+		LocalBackupInfo: &backuprecoveryv1.BackupRunSummary{
+			RunType:            core.StringPtr("kRegular"),
+			Status:             core.StringPtr("Succeeded"),
+			StartTimeUsecs:     core.Int64Ptr(start.UnixMicro()),
+			EndTimeUsecs:       core.Int64Ptr(end.UnixMicro()),
+			LocalSnapshotStats: stats,
+		},
+		Objects: []backuprecoveryv1.ObjectRunResult{{
+			Object: &backuprecoveryv1.ObjectSummary{ID: core.Int64Ptr(7), Name: core.StringPtr("fs01")},
+			LocalSnapshotInfo: &backuprecoveryv1.BackupRun{SnapshotInfo: &backuprecoveryv1.SnapshotInfo{
+				Status:         core.StringPtr("kSuccessful"),
+				StartTimeUsecs: core.Int64Ptr(start.UnixMicro()),
+				EndTimeUsecs:   core.Int64Ptr(end.UnixMicro()),
+				Stats:          stats,
+			}},
+		}},
+	}
+}
+
+func history() []backuprecoveryv1.ProtectionGroupRun {
+	var runs []backuprecoveryv1.ProtectionGroupRun
+	for i := 0; i < 8; i++ {
+		runs = append(runs, run(i, 500*gib+int64(i)*gib, 10*gib+int64(i%3)*gib))
+	}
+	return runs
+}
+
+func TestAnalyzeFlagsSpikeAndHoldsRun(t *testing.T) {
+	client := &fakeClient{runs: append(history(), run(8, 508*gib, 120*gib))}
+	// A failed run is ignored entirely.
+	failed := run(9, 0, 0)
+	failed.LocalBackupInfo.Status = core.StringPtr("Failed")
+	client.runs = append(client.runs, failed)
+
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.Now = func() time.Time { return base }
+	analyzer.HoldPolicy = HoldSpikes
+
+	report, err := analyzer.Analyze(context.Background())
+	require.Nil(t, err)
+	require.Len(t, report.Findings, 2)
+	for _, finding := range report.Findings {
+		assert.Equal(t, "run-8", finding.RunID)
+		assert.Equal(t, MetricBytesRead, finding.Metric)
+		assert.Equal(t, DirectionSpike, finding.Direction)
+		assert.Equal(t, float64(11*gib), finding.Baseline.Median)
+	}
+	assert.Equal(t, int64(0), report.Findings[0].ObjectID)
+	assert.Equal(t, "fs01", report.Findings[1].ObjectName)
+
+	require.Len(t, client.updates, 1)
+	params := client.updates[0].UpdateProtectionGroupRunParams[0]
+	assert.Equal(t, "run-8", *params.RunID)
+	assert.True(t, *params.LocalSnapshotConfig.EnableLegalHold)
+	assert.Equal(t, []Hold{{GroupID: "pg-1", RunID: "run-8"}}, report.Holds)
+}
+
+func TestAnalyzeFlagsCollapse(t *testing.T) {
+	client := &fakeClient{runs: append(history(), run(8, 40*gib, 11*gib))}
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.HoldPolicy = HoldSpikes
+
+	report, err := analyzer.Analyze(context.Background(), "pg-1")
+	require.Nil(t, err)
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, MetricLogicalBytes, report.Findings[0].Metric)
+	assert.Equal(t, DirectionCollapse, report.Findings[0].Direction)
+	assert.Empty(t, client.updates)
+}
+
+func TestAnalyzeSkipsRunsBeforeSince(t *testing.T) {
+	client := &fakeClient{runs: append(history(), run(8, 508*gib, 120*gib))}
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.Since = base.Add(9 * 24 * time.Hour)
+
+	report, err := analyzer.Analyze(context.Background(), "pg-1")
+	require.Nil(t, err)
+	assert.Empty(t, report.Findings)
+}
@@ -0,0 +1,67 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package anomaly
+
+import (
+	"math"
+	"sort"
+)
+
+// madScale turns the median absolute deviation into an estimate of the standard deviation of normally distributed
+// samples, so scores read like z-scores.
+const madScale = 1.4826
+
+// Baseline summarises the recent values of one metric with outlier-resistant statistics.
+type Baseline struct {
+	Samples int     `json:"samples"`
+	Median  float64 `json:"median"`
+	MAD     float64 `json:"mad"`
+}
+
+// NewBaseline computes the baseline of the given samples.
+func NewBaseline(samples []float64) Baseline {
+	if len(samples) == 0 {
+		return Baseline{}
+	}
+	center := median(samples)
+	deviations := make([]float64, len(samples))
+	for i, sample := range samples {
+		deviations[i] = math.Abs(sample - center)
+	}
+	return Baseline{Samples: len(samples), Median: center, MAD: median(deviations)}
+}
+
+// Score returns how many robust standard deviations the value lies above (positive) or below (negative) the
+// median. The spread is floored at minSpread times the median so that perfectly stable series do not turn every
+// small wobble into an anomaly.
+func (baseline Baseline) Score(value float64, minSpread float64) float64 {
+	spread := math.Max(madScale*baseline.MAD, minSpread*math.Abs(baseline.Median))
+	if spread == 0 {
+		spread = 1
+	}
+	return (value - baseline.Median) / spread
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
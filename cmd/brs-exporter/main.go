@@ -0,0 +1,91 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command brs-exporter serves protection health metrics of a Backup and Recovery tenant in the OpenMetrics format.
+//
+// The service URL and credentials are read from the environment or a credentials file as described for external
+// configuration in the IBM Go SDK core, using the service names backup_recovery and, when -sre is set,
+// backup_recovery_management_sre_api.
+//
+// Usage:
+//
+//	brs-exporter -tenant <tenant id> [-listen :9464] [-interval 1m] [-max-groups 1000] [-sre]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/exporter"
+)
+
+func main() {
+	tenantID := flag.String("tenant", "", "tenant id (required)")
+	listen := flag.String("listen", ":9464", "address to serve /metrics on")
+	interval := flag.Duration("interval", time.Minute, "delay between collections")
+	maxGroups := flag.Int("max-groups", 1000, "maximum number of protection groups with per-group series")
+	alertLookback := flag.Duration("alert-lookback", 24*time.Hour, "time range of the active alert statistics")
+	sre := flag.Bool("sre", false, "also collect cluster and alert metrics from the management SRE API")
+	flag.Parse()
+	if *tenantID == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client, err := backuprecoveryv1.NewBackupRecoveryV1UsingExternalConfig(&backuprecoveryv1.BackupRecoveryV1Options{})
+	if err != nil {
+		log.Fatalf("creating client: %s", err)
+	}
+	var sreClient exporter.SreClient
+	if *sre {
+		sreClient, err = backuprecoveryv1.NewBackupRecoveryManagementSreApiV1UsingExternalConfig(&backuprecoveryv1.BackupRecoveryManagementSreApiV1Options{})
+		if err != nil {
+			log.Fatalf("creating SRE client: %s", err)
+		}
+	}
+
+	metrics := exporter.NewExporter(client, sreClient, *tenantID)
+	metrics.Interval = *interval
+	metrics.MaxGroups = *maxGroups
+	metrics.AlertLookback = *alertLookback
+	metrics.OnError = func(err error) { log.Printf("collection failed: %s", err) }
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go metrics.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	log.Printf("serving metrics on %s/metrics", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command brs-report-scheduler runs the report definitions of a configuration file on their cron schedules and
+// writes versioned outputs to a directory.
+//
+// The service URL and credentials are read from the environment or a credentials file as described for external
+// configuration in the IBM Go SDK core, using the service name backup_recovery_management_reporting_api.
+//
+// Usage:
+//
+//	brs-report-scheduler -config <file> [-out reports] [-history <file>] [-once <definition>]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	_ "time/tzdata"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/reportscheduler"
+)
+
+func main() {
+	configPath := flag.String("config", "", "YAML or JSON file of report definitions (required)")
+	out := flag.String("out", "reports", "directory of the outputs")
+	historyPath := flag.String("history", "", "file of the run history (default <out>/history.json)")
+	once := flag.String("once", "", "run this definition once and exit")
+	flag.Parse()
+	if *configPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *historyPath == "" {
+		*historyPath = filepath.Join(*out, "history.json")
+	}
+
+	config, err := reportscheduler.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*historyPath), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	client, err := backuprecoveryv1.NewBackupRecoveryManagementReportingApiV1UsingExternalConfig(&backuprecoveryv1.BackupRecoveryManagementReportingApiV1Options{})
+	if err != nil {
+		log.Fatalf("creating client: %s", err)
+	}
+	scheduler, err := reportscheduler.NewScheduler(client, reportscheduler.NewDirSink(*out), *historyPath, config.Definitions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scheduler.OnRun = func(run reportscheduler.Run) {
+		if run.Status == reportscheduler.RunFailed {
+			log.Printf("%s %s failed after %d attempts: %s", run.Definition, run.Version, run.Attempts, run.Error)
+			return
+		}
+		log.Printf("%s %s succeeded with %d outputs", run.Definition, run.Version, len(run.Outputs))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *once != "" {
+		if _, err := scheduler.RunNow(ctx, *once); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for name, next := range scheduler.Next(scheduler.Now()) {
+		log.Printf("%s next runs at %s", name, next.Format("2006-01-02 15:04 MST"))
+	}
+	if err := scheduler.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
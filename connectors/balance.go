@@ -0,0 +1,557 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// BalanceClient is the subset of the BackupRecoveryV1 operations used by the Balancer.
+//
+// Sources are served by the connectors of the connection they are registered with, so the Balancer spreads the load
+// by moving source registrations between connections. PatchDataSourceConnector and PatchDataSourceConnection only
+// rename connectors and connections, so moves go through UpdateProtectionSourceRegistration.
+type BalanceClient interface {
+	GetDataSourceConnectionsWithContext(ctx context.Context, getDataSourceConnectionsOptions *backuprecoveryv1.GetDataSourceConnectionsOptions) (result *backuprecoveryv1.DataSourceConnectionList, response *core.DetailedResponse, err error)
+	GetDataSourceConnectorsWithContext(ctx context.Context, getDataSourceConnectorsOptions *backuprecoveryv1.GetDataSourceConnectorsOptions) (result *backuprecoveryv1.DataSourceConnectorList, response *core.DetailedResponse, err error)
+	GetSourceRegistrationsWithContext(ctx context.Context, getSourceRegistrationsOptions *backuprecoveryv1.GetSourceRegistrationsOptions) (result *backuprecoveryv1.SourceRegistrations, response *core.DetailedResponse, err error)
+	GetProtectionSourceRegistrationWithContext(ctx context.Context, getProtectionSourceRegistrationOptions *backuprecoveryv1.GetProtectionSourceRegistrationOptions) (result *backuprecoveryv1.SourceRegistrationResponseParams, response *core.DetailedResponse, err error)
+	UpdateProtectionSourceRegistrationWithContext(ctx context.Context, updateProtectionSourceRegistrationOptions *backuprecoveryv1.UpdateProtectionSourceRegistrationOptions) (result *backuprecoveryv1.SourceRegistrationResponseParams, response *core.DetailedResponse, err error)
+}
+
+var _ BalanceClient = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Source is a registered source served through a connection.
+type Source struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name,omitempty"`
+	Environment  string `json:"environment,omitempty"`
+	ConnectionID string `json:"connectionId,omitempty"`
+}
+
+// ConnectionLoad is the load of one connection.
+type ConnectionLoad struct {
+	ConnectionID   string   `json:"connectionId"`
+	ConnectionName string   `json:"connectionName,omitempty"`
+	EnvType        string   `json:"envType,omitempty"`
+	Connectors     []string `json:"connectors,omitempty"`
+
+	// Connected is the number of connectors of the connection that are connected to the cluster.
+	Connected int      `json:"connected"`
+	Sources   []Source `json:"sources,omitempty"`
+}
+
+// Load returns the number of sources per connected connector. It is infinite for a connection with sources but no
+// connected connector.
+func (load *ConnectionLoad) Load() float64 {
+	if len(load.Sources) == 0 {
+		return 0
+	}
+	if load.Connected == 0 {
+		return math.Inf(1)
+	}
+	return float64(len(load.Sources)) / float64(load.Connected)
+}
+
+// Spread is how the sources of a tenant are spread over its connections.
+type Spread struct {
+	Connections []ConnectionLoad `json:"connections"`
+
+	// Unassigned lists the sources registered without a connection, or with a connection that does not exist.
+	Unassigned []Source `json:"unassigned,omitempty"`
+}
+
+// Connection returns the load of a connection, or nil when there is no such connection.
+func (spread *Spread) Connection(id string) *ConnectionLoad {
+	for i := range spread.Connections {
+		if spread.Connections[i].ConnectionID == id {
+			return &spread.Connections[i]
+		}
+	}
+	return nil
+}
+
+// WriteTable writes the spread as a table with one row per connection.
+func (spread *Spread) WriteTable(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CONNECTION\tNAME\tENV\tCONNECTORS\tCONNECTED\tSOURCES\tLOAD")
+	for _, connection := range spread.Connections {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%d\t%.2f\n", connection.ConnectionID, connection.ConnectionName,
+			connection.EnvType, len(connection.Connectors), connection.Connected, len(connection.Sources), connection.Load())
+	}
+	return table.Flush()
+}
+
+// MoveStatus is the progress of a move.
+type MoveStatus string
+
+// The values of MoveStatus.
+const (
+	MovePending    MoveStatus = "pending"
+	MoveApplied    MoveStatus = "applied"
+	MoveFailed     MoveStatus = "failed"
+	MoveRolledBack MoveStatus = "rolled_back"
+)
+
+// Move moves one source from a connection to another.
+type Move struct {
+	SourceID   int64      `json:"sourceId"`
+	SourceName string     `json:"sourceName,omitempty"`
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	Status     MoveStatus `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	AppliedAt  *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Plan is a rebalanced assignment, expressed as the moves that lead to it. Applied plans are saved with the
+// progress of every move, so that they can be resumed and rolled back.
+type Plan struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Moves     []Move    `json:"moves"`
+
+	// Before and After are the loads of the connections before and after all moves.
+	Before map[string]float64 `json:"before"`
+	After  map[string]float64 `json:"after"`
+}
+
+// ErrTargetDisconnected is returned by Apply when a connection that sources are moved to has no connected connector
+// left.
+var ErrTargetDisconnected = errors.New("target connection has no connected connector")
+
+// Balancer spreads the sources of a tenant over its connections according to their connected connectors.
+type Balancer struct {
+	client   BalanceClient
+	tenantID string
+	file     helpers.JSONFile[Plan]
+
+	// MaxMoves bounds the number of moves of a plan. Defaults to 20.
+	MaxMoves int
+
+	// BatchSize is the number of moves applied before waiting Settle. Defaults to 2.
+	BatchSize int
+
+	// Settle is the delay between batches. The connections moved to must still have a connected connector after it,
+	// or Apply and Rollback stop. Defaults to 10 minutes.
+	Settle time.Duration
+
+	// DryRun makes Apply and Rollback check every move without updating any registration or saving the plan.
+	DryRun bool
+
+	// OnMove, when set, is called after every move that was applied, failed or rolled back.
+	OnMove func(move Move)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewBalancer : Instantiate Balancer. Applied plans are kept in the file at planPath.
+func NewBalancer(client BalanceClient, tenantID string, planPath string) *Balancer {
+	return &Balancer{
+		client:    client,
+		tenantID:  tenantID,
+		file:      helpers.JSONFile[Plan]{Path: planPath},
+		MaxMoves:  20,
+		BatchSize: 2,
+		Settle:    10 * time.Minute,
+		Now:       time.Now,
+	}
+}
+
+// Load returns the saved plan, or nil when there is none.
+func (balancer *Balancer) Load() (*Plan, error) {
+	return balancer.file.Load()
+}
+
+// Spread reads how the sources are currently spread over the connections.
+func (balancer *Balancer) Spread(ctx context.Context) (*Spread, error) {
+	tenantID := core.StringPtr(balancer.tenantID)
+	connections, _, err := balancer.client.GetDataSourceConnectionsWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectionsOptions{XIBMTenantID: tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("listing connections: %w", err)
+	}
+	connectors, _, err := balancer.client.GetDataSourceConnectorsWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectorsOptions{XIBMTenantID: tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("listing connectors: %w", err)
+	}
+	registrations, _, err := balancer.client.GetSourceRegistrationsWithContext(ctx, &backuprecoveryv1.GetSourceRegistrationsOptions{XIBMTenantID: tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("listing source registrations: %w", err)
+	}
+
+	spread := &Spread{}
+	if connections != nil {
+		for _, connection := range connections.Connections {
+			spread.Connections = append(spread.Connections, ConnectionLoad{
+				ConnectionID:   helpers.Deref(connection.ConnectionID),
+				ConnectionName: helpers.Deref(connection.ConnectionName),
+				EnvType:        helpers.Deref(connection.ConnectionEnvType),
+			})
+		}
+	}
+	if connectors != nil {
+		for _, connector := range connectors.Connectors {
+			load := spread.Connection(helpers.Deref(connector.ConnectionID))
+			if load == nil {
+				continue
+			}
+			load.Connectors = append(load.Connectors, helpers.Deref(connector.ConnectorID))
+			if connected(connector) {
+				load.Connected++
+			}
+		}
+	}
+	if registrations != nil {
+		for _, registration := range registrations.Registrations {
+			source := Source{
+				ID:           helpers.Deref(registration.ID),
+				Name:         helpers.Deref(registration.Name),
+				Environment:  helpers.Deref(registration.Environment),
+				ConnectionID: registrationConnection(&registration),
+			}
+			if load := spread.Connection(source.ConnectionID); load != nil {
+				load.Sources = append(load.Sources, source)
+			} else {
+				spread.Unassigned = append(spread.Unassigned, source)
+			}
+		}
+	}
+	sort.Slice(spread.Connections, func(i, j int) bool {
+		return spread.Connections[i].ConnectionID < spread.Connections[j].ConnectionID
+	})
+	return spread, nil
+}
+
+// Propose computes the moves that even out the load of the connections. Sources only move between connections of
+// the same environment type, and only to connections with a connected connector. A move is proposed only when the
+// connection moved to stays less loaded than the connection moved from, so applying a plan never oscillates.
+func (balancer *Balancer) Propose(spread *Spread) *Plan {
+	loads := make([]ConnectionLoad, len(spread.Connections))
+	for i, connection := range spread.Connections {
+		loads[i] = connection
+		loads[i].Sources = append([]Source(nil), connection.Sources...)
+		// Move the most recently registered sources first.
+		sort.Slice(loads[i].Sources, func(a, b int) bool { return loads[i].Sources[a].ID < loads[i].Sources[b].ID })
+	}
+	plan := &Plan{CreatedAt: balancer.Now(), Before: map[string]float64{}, After: map[string]float64{}}
+	for _, load := range loads {
+		plan.Before[load.ConnectionID] = load.Load()
+	}
+
+	// A source moves at most once per plan.
+	moved := map[int64]bool{}
+	for len(plan.Moves) < balancer.MaxMoves {
+		var from, to *ConnectionLoad
+		index := -1
+		for i := range loads {
+			hot := &loads[i]
+			candidate := movable(hot, moved)
+			if candidate < 0 {
+				continue
+			}
+			cold := coldest(loads, hot)
+			if cold == nil || !improves(hot, cold) {
+				continue
+			}
+			if from == nil || hot.Load() > from.Load() {
+				from, to, index = hot, cold, candidate
+			}
+		}
+		if from == nil {
+			break
+		}
+		source := from.Sources[index]
+		from.Sources = append(from.Sources[:index], from.Sources[index+1:]...)
+		to.Sources = append(to.Sources, source)
+		moved[source.ID] = true
+		plan.Moves = append(plan.Moves, Move{
+			SourceID:   source.ID,
+			SourceName: source.Name,
+			From:       from.ConnectionID,
+			To:         to.ConnectionID,
+			Status:     MovePending,
+		})
+	}
+
+	for _, load := range loads {
+		plan.After[load.ConnectionID] = load.Load()
+	}
+	return plan
+}
+
+// movable returns the index of the most recently registered source of a connection that did not move yet, or -1.
+func movable(load *ConnectionLoad, moved map[int64]bool) int {
+	for i := len(load.Sources) - 1; i >= 0; i-- {
+		if !moved[load.Sources[i].ID] {
+			return i
+		}
+	}
+	return -1
+}
+
+// coldest returns the least loaded connection that sources of hot can move to.
+func coldest(loads []ConnectionLoad, hot *ConnectionLoad) *ConnectionLoad {
+	var cold *ConnectionLoad
+	for i := range loads {
+		candidate := &loads[i]
+		if candidate == hot || candidate.EnvType != hot.EnvType || candidate.Connected == 0 {
+			continue
+		}
+		if cold == nil || next(candidate) < next(cold) {
+			cold = candidate
+		}
+	}
+	return cold
+}
+
+// improves reports whether moving one source from hot to cold leaves cold no more loaded than hot.
+func improves(hot *ConnectionLoad, cold *ConnectionLoad) bool {
+	if hot.Connected == 0 {
+		return true
+	}
+	return next(cold) <= float64(len(hot.Sources)-1)/float64(hot.Connected)
+}
+
+// next returns the load of a connection after it received one more source.
+func next(load *ConnectionLoad) float64 {
+	return float64(len(load.Sources)+1) / float64(load.Connected)
+}
+
+// Apply applies the pending moves of a plan, BatchSize at a time with Settle in between. The plan is saved after
+// every move, so that an interrupted Apply is resumed by applying the plan returned by Load. Apply stops at the
+// first failed move; Rollback reverts the moves applied so far.
+func (balancer *Balancer) Apply(ctx context.Context, plan *Plan) error {
+	var steps []step
+	for i := range plan.Moves {
+		if move := &plan.Moves[i]; move.Status == MovePending {
+			steps = append(steps, step{move: move, from: move.From, to: move.To, done: MoveApplied, failed: MoveFailed})
+		}
+	}
+	return balancer.run(ctx, plan, steps)
+}
+
+// Rollback moves the sources of the applied moves of the saved plan back, in reverse order and in batches like
+// Apply. It stops at the first move that cannot be reverted; that move stays applied, so Rollback can be repeated.
+func (balancer *Balancer) Rollback(ctx context.Context) (*Plan, error) {
+	plan, err := balancer.file.Load()
+	if err != nil || plan == nil {
+		return plan, err
+	}
+	var steps []step
+	for i := len(plan.Moves) - 1; i >= 0; i-- {
+		if move := &plan.Moves[i]; move.Status == MoveApplied {
+			steps = append(steps, step{move: move, from: move.To, to: move.From, done: MoveRolledBack, failed: MoveApplied})
+		}
+	}
+	return plan, balancer.run(ctx, plan, steps)
+}
+
+// step is a move of a plan in the direction it is applied or rolled back, with the statuses it ends up in.
+type step struct {
+	move   *Move
+	from   string
+	to     string
+	done   MoveStatus
+	failed MoveStatus
+}
+
+// run moves the sources of the steps, BatchSize at a time with Settle in between, and saves the plan after every
+// step. With DryRun it only checks the steps.
+func (balancer *Balancer) run(ctx context.Context, plan *Plan, steps []step) error {
+	if balancer.DryRun {
+		for _, step := range steps {
+			if _, _, err := balancer.prepare(ctx, step.move.SourceID, step.from, step.to); err != nil {
+				return fmt.Errorf("moving source %d: %w", step.move.SourceID, err)
+			}
+		}
+		return nil
+	}
+
+	if err := balancer.save(plan); err != nil {
+		return err
+	}
+	moved := 0
+	for _, step := range steps {
+		move := step.move
+		if moved > 0 && moved%max(1, balancer.BatchSize) == 0 {
+			if err := balancer.settle(ctx, plan); err != nil {
+				return err
+			}
+		}
+		err := balancer.move(ctx, move.SourceID, step.from, step.to)
+		if err != nil {
+			move.Status, move.Error = step.failed, err.Error()
+		} else {
+			move.Status, move.Error = step.done, ""
+			if step.done == MoveApplied {
+				now := balancer.Now()
+				move.AppliedAt = &now
+			}
+			moved++
+		}
+		if saveErr := balancer.save(plan); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		if balancer.OnMove != nil {
+			balancer.OnMove(*move)
+		}
+		if err != nil {
+			return fmt.Errorf("moving source %d from connection '%s' to '%s': %w", move.SourceID, step.from, step.to, err)
+		}
+	}
+	return nil
+}
+
+// settle waits Settle and checks that every connection sources were moved to, by an applied or a rolled back move,
+// still has a connected connector.
+func (balancer *Balancer) settle(ctx context.Context, plan *Plan) error {
+	timer := time.NewTimer(balancer.Settle)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+	}
+	options := &backuprecoveryv1.GetDataSourceConnectorsOptions{XIBMTenantID: core.StringPtr(balancer.tenantID)}
+	list, _, err := balancer.client.GetDataSourceConnectorsWithContext(ctx, options)
+	if err != nil {
+		return fmt.Errorf("listing connectors: %w", err)
+	}
+	healthy := map[string]bool{}
+	if list != nil {
+		for _, connector := range list.Connectors {
+			if connected(connector) {
+				healthy[helpers.Deref(connector.ConnectionID)] = true
+			}
+		}
+	}
+	for _, move := range plan.Moves {
+		switch {
+		case move.Status == MoveApplied && !healthy[move.To]:
+			return fmt.Errorf("connection '%s': %w", move.To, ErrTargetDisconnected)
+		case move.Status == MoveRolledBack && !healthy[move.From]:
+			return fmt.Errorf("connection '%s': %w", move.From, ErrTargetDisconnected)
+		}
+	}
+	return nil
+}
+
+// move moves a source from a connection to another. A source already on the connection moved to is left alone.
+func (balancer *Balancer) move(ctx context.Context, sourceID int64, from string, to string) error {
+	options, done, err := balancer.prepare(ctx, sourceID, from, to)
+	if err != nil || done {
+		return err
+	}
+	if _, _, err := balancer.client.UpdateProtectionSourceRegistrationWithContext(ctx, options); err != nil {
+		return fmt.Errorf("updating registration: %w", err)
+	}
+	return nil
+}
+
+// prepare reads the registration of a source and returns the update that moves it, or done when it was moved
+// already.
+func (balancer *Balancer) prepare(ctx context.Context, sourceID int64, from string, to string) (*backuprecoveryv1.UpdateProtectionSourceRegistrationOptions, bool, error) {
+	registration, _, err := balancer.client.GetProtectionSourceRegistrationWithContext(ctx, &backuprecoveryv1.GetProtectionSourceRegistrationOptions{
+		ID:           core.Int64Ptr(sourceID),
+		XIBMTenantID: core.StringPtr(balancer.tenantID),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("getting registration: %w", err)
+	}
+	if registration == nil {
+		return nil, false, errors.New("getting registration: no registration returned")
+	}
+	switch current := registrationConnection(registration); current {
+	case to:
+		return nil, true, nil
+	case from:
+	default:
+		return nil, false, fmt.Errorf("source is on connection '%s' now", current)
+	}
+
+	options := &backuprecoveryv1.UpdateProtectionSourceRegistrationOptions{
+		ID:                     core.Int64Ptr(sourceID),
+		XIBMTenantID:           core.StringPtr(balancer.tenantID),
+		Environment:            registration.Environment,
+		Name:                   registration.Name,
+		ConnectorGroupID:       registration.ConnectorGroupID,
+		AdvancedConfigs:        registration.AdvancedConfigs,
+		DataSourceConnectionID: core.StringPtr(to),
+		PhysicalParams:         registration.PhysicalParams,
+		KubernetesParams:       registration.KubernetesParams,
+	}
+	if id, err := strconv.ParseInt(to, 10, 64); err == nil {
+		options.ConnectionID = core.Int64Ptr(id)
+	}
+	for _, connection := range registration.Connections {
+		if registrationConnectionConfig(connection) == from {
+			connection.ConnectionID, connection.DataSourceConnectionID = options.ConnectionID, core.StringPtr(to)
+		}
+		options.Connections = append(options.Connections, connection)
+	}
+	return options, false, nil
+}
+
+func (balancer *Balancer) save(plan *Plan) error {
+	if err := balancer.file.Save(plan); err != nil {
+		return fmt.Errorf("saving plan '%s': %w", balancer.file.Path, err)
+	}
+	return nil
+}
+
+// registrationConnection returns the ID of the connection a source is registered with.
+func registrationConnection(registration *backuprecoveryv1.SourceRegistrationResponseParams) string {
+	if id := helpers.Deref(registration.DataSourceConnectionID); id != "" {
+		return id
+	}
+	if registration.ConnectionID != nil {
+		return strconv.FormatInt(*registration.ConnectionID, 10)
+	}
+	for _, connection := range registration.Connections {
+		if id := registrationConnectionConfig(connection); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func registrationConnectionConfig(connection backuprecoveryv1.ConnectionConfig) string {
+	if id := helpers.Deref(connection.DataSourceConnectionID); id != "" {
+		return id
+	}
+	if connection.ConnectionID != nil {
+		return strconv.FormatInt(*connection.ConnectionID, 10)
+	}
+	return ""
+}
+
+func connected(connector backuprecoveryv1.DataSourceConnector) bool {
+	status := connector.ConnectivityStatus
+	return status != nil && status.IsConnected != nil && *status.IsConnected
+}
@@ -0,0 +1,268 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBalanceClient struct {
+	connections   []backuprecoveryv1.DataSourceConnection
+	connectors    []backuprecoveryv1.DataSourceConnector
+	registrations map[int64]*backuprecoveryv1.SourceRegistrationResponseParams
+	updates       []*backuprecoveryv1.UpdateProtectionSourceRegistrationOptions
+	failUpdate    map[int64]bool
+}
+
+func (client *fakeBalanceClient) GetDataSourceConnectionsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectionsOptions) (*backuprecoveryv1.DataSourceConnectionList, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.DataSourceConnectionList{Connections: client.connections}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeBalanceClient) GetDataSourceConnectorsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorsOptions) (*backuprecoveryv1.DataSourceConnectorList, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.DataSourceConnectorList{Connectors: client.connectors}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeBalanceClient) GetSourceRegistrationsWithContext(ctx context.Context, options *backuprecoveryv1.GetSourceRegistrationsOptions) (*backuprecoveryv1.SourceRegistrations, *core.DetailedResponse, error) {
+	result := &backuprecoveryv1.SourceRegistrations{}
+	for id := int64(1); id <= int64(len(client.registrations)); id++ {
+		result.Registrations = append(result.Registrations, *client.registrations[id])
+	}
+	return result, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeBalanceClient) GetProtectionSourceRegistrationWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionSourceRegistrationOptions) (*backuprecoveryv1.SourceRegistrationResponseParams, *core.DetailedResponse, error) {
+	registration := *client.registrations[*options.ID]
+	return &registration, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeBalanceClient) UpdateProtectionSourceRegistrationWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionSourceRegistrationOptions) (*backuprecoveryv1.SourceRegistrationResponseParams, *core.DetailedResponse, error) {
+	if client.failUpdate[*options.ID] {
+		return nil, &core.DetailedResponse{StatusCode: 400}, errors.New("credentials required")
+	}
+	client.updates = append(client.updates, options)
+	registration := client.registrations[*options.ID]
+	registration.DataSourceConnectionID = options.DataSourceConnectionID
+	registration.ConnectionID = options.ConnectionID
+	return registration, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+// newFakeBalanceClient has connection 10 with one connector serving six sources, and connection 20 with two idle
+// connectors.
+func newFakeBalanceClient() *fakeBalanceClient {
+	client := &fakeBalanceClient{registrations: map[int64]*backuprecoveryv1.SourceRegistrationResponseParams{}}
+	for _, id := range []string{"10", "20"} {
+		client.connections = append(client.connections, backuprecoveryv1.DataSourceConnection{
+			ConnectionID:      core.StringPtr(id),
+			ConnectionName:    core.StringPtr("connection-" + id),
+			ConnectionEnvType: core.StringPtr("kIksVpc"),
+		})
+	}
+	for _, connector := range [][2]string{{"1", "10"}, {"2", "20"}, {"3", "20"}} {
+		client.connectors = append(client.connectors, backuprecoveryv1.DataSourceConnector{
+			ConnectorID:        core.StringPtr(connector[0]),
+			ConnectionID:       core.StringPtr(connector[1]),
+			ConnectivityStatus: &backuprecoveryv1.ConnectorConnectivityStatus{IsConnected: core.BoolPtr(true)},
+		})
+	}
+	for id := int64(1); id <= 6; id++ {
+		client.registrations[id] = &backuprecoveryv1.SourceRegistrationResponseParams{
+			ID:                     core.Int64Ptr(id),
+			Name:                   core.StringPtr("source"),
+			Environment:            core.StringPtr("kPhysical"),
+			DataSourceConnectionID: core.StringPtr("10"),
+		}
+	}
+	return client
+}
+
+func newBalancer(t *testing.T, client BalanceClient) *Balancer {
+	balancer := NewBalancer(client, "tenant", filepath.Join(t.TempDir(), "plan.json"))
+	balancer.Settle = time.Millisecond
+	balancer.Now = func() time.Time { return base }
+	return balancer
+}
+
+func TestBalancerPropose(t *testing.T) {
+	client := newFakeBalanceClient()
+	balancer := newBalancer(t, client)
+
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, 6.0, spread.Connection("10").Load())
+	assert.Equal(t, 0.0, spread.Connection("20").Load())
+	var table bytes.Buffer
+	require.Nil(t, spread.WriteTable(&table))
+	assert.Contains(t, table.String(), "connection-10")
+
+	plan := balancer.Propose(spread)
+	require.Len(t, plan.Moves, 4)
+	assert.Equal(t, Move{SourceID: 6, SourceName: "source", From: "10", To: "20", Status: MovePending}, plan.Moves[0])
+	assert.Equal(t, 2.0, plan.After["10"])
+	assert.Equal(t, 2.0, plan.After["20"])
+
+	// A balanced spread needs no moves.
+	assert.Empty(t, balancer.Propose(&Spread{Connections: []ConnectionLoad{
+		{ConnectionID: "10", Connected: 1, Sources: []Source{{ID: 1}}},
+		{ConnectionID: "20", Connected: 1, Sources: []Source{{ID: 2}, {ID: 3}}},
+	}}).Moves)
+}
+
+func TestBalancerProposeDrainsDisconnected(t *testing.T) {
+	balancer := newBalancer(t, newFakeBalanceClient())
+	plan := balancer.Propose(&Spread{Connections: []ConnectionLoad{
+		{ConnectionID: "10", Connected: 0, Sources: []Source{{ID: 1}, {ID: 2}}},
+		{ConnectionID: "20", Connected: 1, Sources: []Source{{ID: 3}, {ID: 4}, {ID: 5}}},
+		{ConnectionID: "30", EnvType: "kRoksVpc", Connected: 1},
+	}})
+	require.Len(t, plan.Moves, 2)
+	assert.True(t, math.IsInf(plan.Before["10"], 1))
+	assert.Equal(t, 0.0, plan.After["10"])
+	assert.Equal(t, 0.0, plan.After["30"])
+}
+
+func TestBalancerApplyAndRollback(t *testing.T) {
+	client := newFakeBalanceClient()
+	balancer := newBalancer(t, client)
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	plan := balancer.Propose(spread)
+
+	balancer.DryRun = true
+	require.Nil(t, balancer.Apply(context.Background(), plan))
+	assert.Empty(t, client.updates)
+	saved, err := balancer.Load()
+	require.Nil(t, err)
+	assert.Nil(t, saved)
+
+	balancer.DryRun = false
+	require.Nil(t, balancer.Apply(context.Background(), plan))
+	require.Len(t, client.updates, 4)
+	assert.Equal(t, int64(20), *client.updates[0].ConnectionID)
+	assert.Equal(t, "kPhysical", *client.updates[0].Environment)
+	spread, err = balancer.Spread(context.Background())
+	require.Nil(t, err)
+	assert.Len(t, spread.Connection("20").Sources, 4)
+
+	balancer.DryRun = true
+	saved, err = balancer.Rollback(context.Background())
+	require.Nil(t, err)
+	assert.Len(t, client.updates, 4)
+	assert.Equal(t, MoveApplied, saved.Moves[0].Status)
+
+	balancer.DryRun = false
+	saved, err = balancer.Rollback(context.Background())
+	require.Nil(t, err)
+	for _, move := range saved.Moves {
+		assert.Equal(t, MoveRolledBack, move.Status)
+	}
+	spread, err = balancer.Spread(context.Background())
+	require.Nil(t, err)
+	assert.Len(t, spread.Connection("10").Sources, 6)
+}
+
+func TestBalancerApplyStopsOnFailure(t *testing.T) {
+	client := newFakeBalanceClient()
+	client.failUpdate = map[int64]bool{5: true}
+	balancer := newBalancer(t, client)
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	plan := balancer.Propose(spread)
+
+	err = balancer.Apply(context.Background(), plan)
+	assert.ErrorContains(t, err, "credentials required")
+	saved, err := balancer.Load()
+	require.Nil(t, err)
+	assert.Equal(t, MoveApplied, saved.Moves[0].Status)
+	assert.Equal(t, MoveFailed, saved.Moves[1].Status)
+	assert.Equal(t, MovePending, saved.Moves[2].Status)
+
+	// Resuming applies the pending moves.
+	client.failUpdate = nil
+	saved.Moves[1].Status = MovePending
+	require.Nil(t, balancer.Apply(context.Background(), saved))
+	assert.Len(t, client.updates, 4)
+}
+
+func TestBalancerApplyStopsWhenTargetDisconnects(t *testing.T) {
+	client := newFakeBalanceClient()
+	balancer := newBalancer(t, client)
+	balancer.BatchSize = 1
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	plan := balancer.Propose(spread)
+	balancer.OnMove = func(move Move) {
+		for i := range client.connectors {
+			client.connectors[i].ConnectivityStatus.IsConnected = core.BoolPtr(false)
+		}
+	}
+
+	err = balancer.Apply(context.Background(), plan)
+	assert.True(t, errors.Is(err, ErrTargetDisconnected))
+	assert.Len(t, client.updates, 1)
+}
+
+func TestBalancerRollbackStopsWhenTargetDisconnects(t *testing.T) {
+	client := newFakeBalanceClient()
+	balancer := newBalancer(t, client)
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	require.Nil(t, balancer.Apply(context.Background(), balancer.Propose(spread)))
+
+	balancer.BatchSize = 1
+	balancer.OnMove = func(move Move) {
+		for i := range client.connectors {
+			client.connectors[i].ConnectivityStatus.IsConnected = core.BoolPtr(false)
+		}
+	}
+	saved, err := balancer.Rollback(context.Background())
+	assert.True(t, errors.Is(err, ErrTargetDisconnected))
+	assert.Len(t, client.updates, 5)
+	assert.Equal(t, MoveApplied, saved.Moves[0].Status)
+	assert.Equal(t, MoveRolledBack, saved.Moves[3].Status)
+}
+
+type missingRegistrationClient struct {
+	*fakeBalanceClient
+}
+
+func (client missingRegistrationClient) GetProtectionSourceRegistrationWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionSourceRegistrationOptions) (*backuprecoveryv1.SourceRegistrationResponseParams, *core.DetailedResponse, error) {
+	return nil, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func TestBalancerApplyWithoutRegistration(t *testing.T) {
+	client := newFakeBalanceClient()
+	balancer := newBalancer(t, client)
+	spread, err := balancer.Spread(context.Background())
+	require.Nil(t, err)
+	plan := balancer.Propose(spread)
+
+	balancer.client = missingRegistrationClient{client}
+	err = balancer.Apply(context.Background(), plan)
+	assert.ErrorContains(t, err, "no registration returned")
+	assert.Empty(t, client.updates)
+}
@@ -0,0 +1,433 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// MonitorClient is the subset of the BackupRecoveryV1 operations used by the Monitor.
+type MonitorClient interface {
+	GetDataSourceConnectorsWithContext(ctx context.Context, getDataSourceConnectorsOptions *backuprecoveryv1.GetDataSourceConnectorsOptions) (result *backuprecoveryv1.DataSourceConnectorList, response *core.DetailedResponse, err error)
+	GetConnectorMetadataWithContext(ctx context.Context, getConnectorMetadataOptions *backuprecoveryv1.GetConnectorMetadataOptions) (result *backuprecoveryv1.ConnectorMetadata, response *core.DetailedResponse, err error)
+}
+
+var _ MonitorClient = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// LocalClient is the subset of the operations served by a connector VM that is used by the Monitor.
+type LocalClient interface {
+	GetDataSourceConnectorStatusWithContext(ctx context.Context, getDataSourceConnectorStatusOptions *backuprecoveryv1.GetDataSourceConnectorStatusOptions) (result *backuprecoveryv1.DataSourceConnectorLocalStatus, response *core.DetailedResponse, err error)
+	GetDataSourceConnectorLogsWithContext(ctx context.Context, getDataSourceConnectorLogsOptions *backuprecoveryv1.GetDataSourceConnectorLogsOptions) (result *backuprecoveryv1.DataSourceConnectorLogs, response *core.DetailedResponse, err error)
+}
+
+var _ LocalClient = (*backuprecoveryv1.BackupRecoveryV1Connector)(nil)
+
+// Health is the health of a connector.
+type Health string
+
+// The values of Health, from best to worst.
+const (
+	// HealthHealthy is a connector connected to the cluster whose VM reports no problem.
+	HealthHealthy Health = "healthy"
+	// HealthDegraded is a connector whose VM reports a problem, e.g. an invalid certificate or a failed upgrade.
+	HealthDegraded Health = "degraded"
+	// HealthUnreachable is a connector whose VM could not be queried.
+	HealthUnreachable Health = "unreachable"
+	// HealthDisconnected is a connector that the service does not see connected to the cluster.
+	HealthDisconnected Health = "disconnected"
+)
+
+// ConnectorHealth is the result of checking one connector.
+type ConnectorHealth struct {
+	ConnectorID   string    `json:"connectorId"`
+	ConnectorName string    `json:"connectorName,omitempty"`
+	ConnectionID  string    `json:"connectionId,omitempty"`
+	Health        Health    `json:"health"`
+	Reasons       []string  `json:"reasons,omitempty"`
+	CheckedAt     time.Time `json:"checkedAt"`
+
+	connector backuprecoveryv1.DataSourceConnector
+	status    *backuprecoveryv1.DataSourceConnectorLocalStatus
+	local     LocalClient
+}
+
+// HealthEvent reports a change of the health of a connector.
+type HealthEvent struct {
+	ConnectorHealth
+
+	// Previous is the health seen by the previous check, or empty when the connector was not seen before.
+	Previous Health `json:"previous,omitempty"`
+
+	// Bundle is the path of the support bundle collected for the event, if any.
+	Bundle string `json:"bundle,omitempty"`
+
+	// BundleError is set when collecting the support bundle failed.
+	BundleError string `json:"bundleError,omitempty"`
+}
+
+// Monitor checks the health of the connectors of a tenant and collects support bundles for those that turn
+// unhealthy.
+type Monitor struct {
+	client   MonitorClient
+	tenantID string
+
+	// Dial returns the client of the VM of a connector, e.g. a *backuprecoveryv1.BackupRecoveryV1Connector with the
+	// URL of the VM. When nil, only the health seen by the service is checked and bundles hold no logs.
+	Dial func(connector backuprecoveryv1.DataSourceConnector) (LocalClient, error)
+
+	// Interval is the delay between checks in Run. Defaults to one minute.
+	Interval time.Duration
+
+	// BundleDir is the directory support bundles are written to. No bundles are collected when empty.
+	BundleDir string
+
+	// OnEvent, when set, receives the health transitions.
+	OnEvent func(event HealthEvent)
+
+	// OnError receives check errors in Run, which keeps checking. Errors are dropped when nil.
+	OnError func(error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mutex sync.Mutex
+	last  map[string]Health
+}
+
+// NewMonitor : Instantiate Monitor
+func NewMonitor(client MonitorClient, tenantID string) *Monitor {
+	return &Monitor{
+		client:   client,
+		tenantID: tenantID,
+		Interval: time.Minute,
+		Now:      time.Now,
+		last:     map[string]Health{},
+	}
+}
+
+// Check checks every connector of the tenant once and returns their health together with the transitions since
+// the previous check. A support bundle is collected for every connector that turned unhealthy.
+func (monitor *Monitor) Check(ctx context.Context) ([]ConnectorHealth, []HealthEvent, error) {
+	options := &backuprecoveryv1.GetDataSourceConnectorsOptions{
+		XIBMTenantID: core.StringPtr(monitor.tenantID),
+	}
+	list, _, err := monitor.client.GetDataSourceConnectorsWithContext(ctx, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing connectors: %w", err)
+	}
+	var connectors []backuprecoveryv1.DataSourceConnector
+	if list != nil {
+		connectors = list.Connectors
+	}
+
+	results := make([]ConnectorHealth, len(connectors))
+	var group sync.WaitGroup
+	for i := range connectors {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			results[i] = monitor.check(ctx, connectors[i])
+		}()
+	}
+	group.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].ConnectorID < results[j].ConnectorID })
+
+	monitor.mutex.Lock()
+	var events []HealthEvent
+	seen := map[string]Health{}
+	for _, result := range results {
+		seen[result.ConnectorID] = result.Health
+		previous, known := monitor.last[result.ConnectorID]
+		if previous == result.Health || (!known && result.Health == HealthHealthy) {
+			continue
+		}
+		events = append(events, HealthEvent{ConnectorHealth: result, Previous: previous})
+	}
+	monitor.last = seen
+	monitor.mutex.Unlock()
+
+	for i := range events {
+		if events[i].Health == HealthHealthy || monitor.BundleDir == "" {
+			continue
+		}
+		path, err := monitor.CollectBundle(ctx, events[i].ConnectorHealth)
+		events[i].Bundle = path
+		if err != nil {
+			events[i].BundleError = err.Error()
+		}
+	}
+	if monitor.OnEvent != nil {
+		for _, event := range events {
+			monitor.OnEvent(event)
+		}
+	}
+	return results, events, nil
+}
+
+// Run checks the connectors every Interval until the context is cancelled.
+func (monitor *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(monitor.Interval)
+	defer ticker.Stop()
+	for {
+		if _, _, err := monitor.Check(ctx); err != nil && monitor.OnError != nil {
+			monitor.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (monitor *Monitor) check(ctx context.Context, connector backuprecoveryv1.DataSourceConnector) ConnectorHealth {
+	result := ConnectorHealth{
+		ConnectorID:   helpers.Deref(connector.ConnectorID),
+		ConnectorName: helpers.Deref(connector.ConnectorName),
+		ConnectionID:  helpers.Deref(connector.ConnectionID),
+		Health:        HealthHealthy,
+		CheckedAt:     monitor.Now(),
+		connector:     connector,
+	}
+	degrade := func(health Health, reason string) {
+		if severity(health) > severity(result.Health) {
+			result.Health = health
+		}
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	if status := connector.ConnectivityStatus; status == nil || status.IsConnected == nil || !*status.IsConnected {
+		reason := "not connected to the cluster"
+		if status != nil && helpers.Deref(status.Message) != "" {
+			reason += ": " + *status.Message
+		}
+		degrade(HealthDisconnected, reason)
+	}
+	if upgrade := connector.UpgradeStatus; upgrade != nil && helpers.Deref(upgrade.Status) == backuprecoveryv1.DataSourceConnectorUpgradeStatus_Status_Failed {
+		degrade(HealthDegraded, "upgrade failed: "+helpers.Deref(upgrade.Message))
+	}
+
+	if monitor.Dial == nil {
+		return result
+	}
+	local, err := monitor.Dial(connector)
+	if err != nil {
+		degrade(HealthUnreachable, fmt.Sprintf("connecting to the connector: %v", err))
+		return result
+	}
+	result.local = local
+	status, _, err := local.GetDataSourceConnectorStatusWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectorStatusOptions{})
+	if err != nil {
+		degrade(HealthUnreachable, fmt.Sprintf("getting connector status: %v", err))
+		return result
+	}
+	result.status = status
+	if status.IsCertificateValid != nil && !*status.IsCertificateValid {
+		degrade(HealthDegraded, "certificate is not valid")
+	}
+	if cluster := status.ClusterConnectionStatus; cluster != nil && cluster.IsActive != nil && !*cluster.IsActive {
+		degrade(HealthDegraded, "cluster connection is not active: "+helpers.Deref(cluster.Message))
+	}
+	if registration := registrationStatus(status); registration != "" && registration != backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Success {
+		degrade(HealthDegraded, fmt.Sprintf("registration status is %s: %s", registration, helpers.Deref(status.RegistrationStatus.Message)))
+	}
+	return result
+}
+
+func severity(health Health) int {
+	switch health {
+	case HealthDegraded:
+		return 1
+	case HealthUnreachable:
+		return 2
+	case HealthDisconnected:
+		return 3
+	}
+	return 0
+}
+
+// CollectBundle writes a support bundle for a connector to BundleDir and returns its path. The bundle is named after
+// the connector and the time of the check. Parts that could not be collected are listed in errors.txt inside the
+// bundle and returned as the error, next to the path of the partial bundle.
+func (monitor *Monitor) CollectBundle(ctx context.Context, health ConnectorHealth) (string, error) {
+	if err := os.MkdirAll(monitor.BundleDir, 0o755); err != nil {
+		return "", err
+	}
+	name := bundleName(health)
+	path := filepath.Join(monitor.BundleDir, name+".tar.gz")
+	var collectErr error
+	err := helpers.WriteFile(path, func(w io.Writer) error {
+		collectErr = monitor.WriteBundle(ctx, w, health)
+		var partial *BundleError
+		if errors.As(collectErr, &partial) {
+			return nil
+		}
+		return collectErr
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, collectErr
+}
+
+// BundleError lists the parts of a support bundle that could not be collected.
+type BundleError struct {
+	Errors []error
+}
+
+func (e *BundleError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "incomplete support bundle: " + strings.Join(messages, "; ")
+}
+
+func (e *BundleError) Unwrap() []error {
+	return e.Errors
+}
+
+// WriteBundle writes a support bundle for a connector to writer as a tar.gz archive. It holds the health, the
+// connector as seen by the service, the local status and logs of the VM, and the connector metadata. Parts that
+// could not be collected are listed in errors.txt and returned as a *BundleError.
+func (monitor *Monitor) WriteBundle(ctx context.Context, writer io.Writer, health ConnectorHealth) error {
+	var failures []error
+	now := monitor.Now()
+	prefix := bundleName(health) + "/"
+	compressed := gzip.NewWriter(writer)
+	archive := tar.NewWriter(compressed)
+	add := func(name string, content []byte) error {
+		header := &tar.Header{Name: prefix + name, Mode: 0o644, Size: int64(len(content)), ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := archive.Write(content)
+		return err
+	}
+	addJSON := func(name string, value any) error {
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, content)
+	}
+
+	if err := addJSON("health.json", health); err != nil {
+		return err
+	}
+	if err := addJSON("connector.json", health.connector); err != nil {
+		return err
+	}
+	if health.status != nil {
+		if err := addJSON("status.json", health.status); err != nil {
+			return err
+		}
+	}
+
+	local := health.local
+	if local == nil && monitor.Dial != nil {
+		var err error
+		if local, err = monitor.Dial(health.connector); err != nil {
+			failures = append(failures, fmt.Errorf("connecting to the connector: %w", err))
+		}
+	}
+	if local != nil {
+		logs, _, err := local.GetDataSourceConnectorLogsWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectorLogsOptions{})
+		if err != nil {
+			failures = append(failures, fmt.Errorf("getting connector logs: %w", err))
+		} else if logs != nil {
+			if err := addJSON("logs.json", logs); err != nil {
+				return err
+			}
+			if err := add("logs.txt", formatLogs(logs.ConnectorLogs)); err != nil {
+				return err
+			}
+		}
+	}
+
+	metadata, _, err := monitor.client.GetConnectorMetadataWithContext(ctx, &backuprecoveryv1.GetConnectorMetadataOptions{
+		XIBMTenantID: core.StringPtr(monitor.tenantID),
+	})
+	if err != nil {
+		failures = append(failures, fmt.Errorf("getting connector metadata: %w", err))
+	} else if err := addJSON("metadata.json", metadata); err != nil {
+		return err
+	}
+
+	if len(failures) > 0 {
+		var content strings.Builder
+		for _, failure := range failures {
+			content.WriteString(failure.Error() + "\n")
+		}
+		if err := add("errors.txt", []byte(content.String())); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return &BundleError{Errors: failures}
+	}
+	return nil
+}
+
+func bundleName(health ConnectorHealth) string {
+	name := health.ConnectorName
+	if name == "" {
+		name = health.ConnectorID
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == ':' {
+			return '_'
+		}
+		return r
+	}, name)
+	return fmt.Sprintf("connector-%s-%s", name, health.CheckedAt.UTC().Format("20060102T150405Z"))
+}
+
+func formatLogs(logs []backuprecoveryv1.DataSourceConnectorLog) []byte {
+	sorted := append([]backuprecoveryv1.DataSourceConnectorLog(nil), logs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return helpers.Deref(sorted[i].TimestampMsecs) < helpers.Deref(sorted[j].TimestampMsecs)
+	})
+	var content strings.Builder
+	for _, log := range sorted {
+		timestamp := time.UnixMilli(helpers.Deref(log.TimestampMsecs)).UTC().Format(time.RFC3339Nano)
+		fmt.Fprintf(&content, "%s %-7s %s\n", timestamp, helpers.Deref(log.Type), helpers.Deref(log.Message))
+	}
+	return []byte(content.String())
+}
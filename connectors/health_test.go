@@ -0,0 +1,186 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMonitorClient struct {
+	connectors []backuprecoveryv1.DataSourceConnector
+}
+
+func (client *fakeMonitorClient) GetDataSourceConnectorsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorsOptions) (*backuprecoveryv1.DataSourceConnectorList, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.DataSourceConnectorList{Connectors: client.connectors}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeMonitorClient) GetConnectorMetadataWithContext(ctx context.Context, options *backuprecoveryv1.GetConnectorMetadataOptions) (*backuprecoveryv1.ConnectorMetadata, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ConnectorMetadata{}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+type fakeLocal struct {
+	status *backuprecoveryv1.DataSourceConnectorLocalStatus
+	err    error
+}
+
+func (local *fakeLocal) GetDataSourceConnectorStatusWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorStatusOptions) (*backuprecoveryv1.DataSourceConnectorLocalStatus, *core.DetailedResponse, error) {
+	if local.err != nil {
+		return nil, nil, local.err
+	}
+	return local.status, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (local *fakeLocal) GetDataSourceConnectorLogsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorLogsOptions) (*backuprecoveryv1.DataSourceConnectorLogs, *core.DetailedResponse, error) {
+	if local.err != nil {
+		return nil, nil, local.err
+	}
+	return &backuprecoveryv1.DataSourceConnectorLogs{ConnectorLogs: []backuprecoveryv1.DataSourceConnectorLog{
+		{Message: core.StringPtr("lost cluster"), TimestampMsecs: core.Int64Ptr(base.UnixMilli()), Type: core.StringPtr("Error")},
+		{Message: core.StringPtr("started"), TimestampMsecs: core.Int64Ptr(base.Add(-time.Hour).UnixMilli()), Type: core.StringPtr("Info")},
+	}}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func monitoredConnector(id string, connected bool) backuprecoveryv1.DataSourceConnector {
+	return backuprecoveryv1.DataSourceConnector{
+		ConnectorID:        core.StringPtr(id),
+		ConnectorName:      core.StringPtr("vm-" + id),
+		ConnectionID:       core.StringPtr("conn-1"),
+		ConnectivityStatus: &backuprecoveryv1.ConnectorConnectivityStatus{IsConnected: core.BoolPtr(connected), Message: core.StringPtr("timeout")},
+	}
+}
+
+func healthyStatus() *backuprecoveryv1.DataSourceConnectorLocalStatus {
+	return &backuprecoveryv1.DataSourceConnectorLocalStatus{
+		IsCertificateValid:      core.BoolPtr(true),
+		ClusterConnectionStatus: &backuprecoveryv1.DataSourceConnectorClusterConnectionStatus{IsActive: core.BoolPtr(true)},
+		RegistrationStatus:      &backuprecoveryv1.DataSourceConnectorRegistrationStatus{Status: core.StringPtr("Success")},
+	}
+}
+
+func TestMonitorTransitions(t *testing.T) {
+	client := &fakeMonitorClient{connectors: []backuprecoveryv1.DataSourceConnector{monitoredConnector("1", true), monitoredConnector("2", true)}}
+	locals := map[string]*fakeLocal{"1": {status: healthyStatus()}, "2": {status: healthyStatus()}}
+	monitor := NewMonitor(client, "tenant")
+	monitor.Now = func() time.Time { return base }
+	monitor.BundleDir = t.TempDir()
+	monitor.Dial = func(connector backuprecoveryv1.DataSourceConnector) (LocalClient, error) {
+		return locals[*connector.ConnectorID], nil
+	}
+	var received []HealthEvent
+	monitor.OnEvent = func(event HealthEvent) { received = append(received, event) }
+
+	results, events, err := monitor.Check(context.Background())
+	require.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Empty(t, events)
+
+	client.connectors[0] = monitoredConnector("1", false)
+	locals["2"].status.IsCertificateValid = core.BoolPtr(false)
+	results, events, err = monitor.Check(context.Background())
+	require.Nil(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, HealthDegraded, results[1].Health)
+	assert.Equal(t, events, received)
+	assert.Equal(t, HealthDisconnected, events[0].Health)
+	assert.Equal(t, HealthHealthy, events[0].Previous)
+	assert.Equal(t, []string{"not connected to the cluster: timeout"}, events[0].Reasons)
+	assert.Equal(t, HealthDegraded, events[1].Health)
+	assert.Equal(t, filepath.Join(monitor.BundleDir, "connector-vm-1-20260601T120000Z.tar.gz"), events[0].Bundle)
+	assert.Empty(t, events[0].BundleError)
+
+	// Unchanged health is not reported again, a recovery is.
+	client.connectors[0] = monitoredConnector("1", true)
+	_, events, err = monitor.Check(context.Background())
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, HealthHealthy, events[0].Health)
+	assert.Empty(t, events[0].Bundle)
+}
+
+func TestMonitorUnreachable(t *testing.T) {
+	client := &fakeMonitorClient{connectors: []backuprecoveryv1.DataSourceConnector{monitoredConnector("1", true)}}
+	monitor := NewMonitor(client, "tenant")
+	monitor.Now = func() time.Time { return base }
+	monitor.BundleDir = t.TempDir()
+	monitor.Dial = func(connector backuprecoveryv1.DataSourceConnector) (LocalClient, error) {
+		return &fakeLocal{err: errors.New("no route to host")}, nil
+	}
+
+	_, events, err := monitor.Check(context.Background())
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, HealthUnreachable, events[0].Health)
+	assert.Empty(t, events[0].Previous)
+	assert.Contains(t, events[0].BundleError, "no route to host")
+	files := readBundle(t, events[0].Bundle)
+	assert.Contains(t, files, "connector-vm-1-20260601T120000Z/errors.txt")
+	assert.Contains(t, files, "connector-vm-1-20260601T120000Z/metadata.json")
+	assert.NotContains(t, files, "connector-vm-1-20260601T120000Z/logs.txt")
+}
+
+func TestWriteBundle(t *testing.T) {
+	client := &fakeMonitorClient{}
+	monitor := NewMonitor(client, "tenant")
+	monitor.Now = func() time.Time { return base }
+	health := ConnectorHealth{ConnectorID: "1", ConnectorName: "vm 1", Health: HealthDegraded, CheckedAt: base, local: &fakeLocal{}}
+
+	var buffer bytes.Buffer
+	require.Nil(t, monitor.WriteBundle(context.Background(), &buffer, health))
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.Nil(t, os.WriteFile(path, buffer.Bytes(), 0o644))
+	files := readBundle(t, path)
+	prefix := "connector-vm_1-20260601T120000Z/"
+	assert.Contains(t, files, prefix+"health.json")
+	assert.Contains(t, files, prefix+"connector.json")
+	assert.Contains(t, files, prefix+"logs.json")
+	assert.NotContains(t, files, prefix+"errors.txt")
+	assert.Equal(t, "2026-06-01T11:00:00Z Info    started\n2026-06-01T12:00:00Z Error   lost cluster\n", files[prefix+"logs.txt"])
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+	compressed, err := gzip.NewReader(file)
+	require.Nil(t, err)
+	archive := tar.NewReader(compressed)
+	files := map[string]string{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.Nil(t, err)
+		content, err := io.ReadAll(archive)
+		require.Nil(t, err)
+		files[header.Name] = string(content)
+	}
+}
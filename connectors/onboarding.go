@@ -0,0 +1,482 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package connectors onboards, monitors and balances the data source connectors of a tenant.
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetDataSourceConnectionsWithContext(ctx context.Context, getDataSourceConnectionsOptions *backuprecoveryv1.GetDataSourceConnectionsOptions) (result *backuprecoveryv1.DataSourceConnectionList, response *core.DetailedResponse, err error)
+	CreateDataSourceConnectionWithContext(ctx context.Context, createDataSourceConnectionOptions *backuprecoveryv1.CreateDataSourceConnectionOptions) (result *backuprecoveryv1.DataSourceConnection, response *core.DetailedResponse, err error)
+	DeleteDataSourceConnectionWithContext(ctx context.Context, deleteDataSourceConnectionOptions *backuprecoveryv1.DeleteDataSourceConnectionOptions) (response *core.DetailedResponse, err error)
+	GenerateDataSourceConnectionRegistrationTokenWithContext(ctx context.Context, generateDataSourceConnectionRegistrationTokenOptions *backuprecoveryv1.GenerateDataSourceConnectionRegistrationTokenOptions) (result *string, response *core.DetailedResponse, err error)
+	GetDataSourceConnectorsWithContext(ctx context.Context, getDataSourceConnectorsOptions *backuprecoveryv1.GetDataSourceConnectorsOptions) (result *backuprecoveryv1.DataSourceConnectorList, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// ConnectorClient is the subset of the operations served by a connector VM that is used by this package.
+type ConnectorClient interface {
+	RegisterDataSourceConnectorWithContext(ctx context.Context, registerDataSourceConnectorOptions *backuprecoveryv1.RegisterDataSourceConnectorOptions) (response *core.DetailedResponse, err error)
+	GetDataSourceConnectorStatusWithContext(ctx context.Context, getDataSourceConnectorStatusOptions *backuprecoveryv1.GetDataSourceConnectorStatusOptions) (result *backuprecoveryv1.DataSourceConnectorLocalStatus, response *core.DetailedResponse, err error)
+}
+
+var _ ConnectorClient = (*backuprecoveryv1.BackupRecoveryV1Connector)(nil)
+
+// Step names a step of the onboarding workflow.
+type Step string
+
+// The steps of the onboarding workflow, in the order they run.
+const (
+	StepCreateConnection  Step = "create_connection"
+	StepGenerateToken     Step = "generate_token"
+	StepRegisterConnector Step = "register_connector"
+	StepAwaitRegistration Step = "await_registration"
+	StepAwaitConnectivity Step = "await_connectivity"
+)
+
+// Steps lists the steps of the onboarding workflow in order.
+var Steps = []Step{StepCreateConnection, StepGenerateToken, StepRegisterConnector, StepAwaitRegistration, StepAwaitConnectivity}
+
+// StepStatus is the progress of a step.
+type StepStatus string
+
+// The values of StepStatus.
+const (
+	StepPending StepStatus = "pending"
+	StepRunning StepStatus = "running"
+	StepDone    StepStatus = "done"
+	StepFailed  StepStatus = "failed"
+)
+
+// StepState is the saved progress of one step.
+type StepState struct {
+	Name       Step       `json:"name"`
+	Status     StepStatus `json:"status"`
+	Attempts   int        `json:"attempts,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Request describes the connection to onboard.
+type Request struct {
+	// ConnectionName names the data source connection. It is required.
+	ConnectionName string `json:"connectionName"`
+
+	// ConnectionEnvType is the environment of the connection, e.g. backuprecoveryv1.CreateDataSourceConnectionOptions_ConnectionEnvType_Kiksvpc.
+	ConnectionEnvType string `json:"connectionEnvType,omitempty"`
+
+	// ConnectorID is passed on to the connector when registering it. It is omitted when zero.
+	ConnectorID int64 `json:"connectorId,omitempty"`
+
+	// ConnectorName, when set, selects the connector to wait for among those of the connection.
+	ConnectorName string `json:"connectorName,omitempty"`
+}
+
+// State is the saved progress of an onboarding.
+type State struct {
+	Request   Request     `json:"request"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Steps     []StepState `json:"steps"`
+
+	// ConnectionID is the ID of the connection once it was created or found.
+	ConnectionID string `json:"connectionId,omitempty"`
+
+	// Adopted is set when the connection existed before the onboarding started. Adopted connections are never
+	// deleted on rollback.
+	Adopted bool `json:"adopted,omitempty"`
+
+	// ConnectorID is the ID of the connector seen connected to the cluster.
+	ConnectorID string `json:"connectorId,omitempty"`
+
+	// RolledBack is set once the connection was deleted after a failure. The next Run starts over.
+	RolledBack bool `json:"rolledBack,omitempty"`
+
+	// registrationToken is kept in memory only. It is generated again when a resumed onboarding still has to
+	// register the connector.
+	registrationToken string
+}
+
+// Step returns the saved progress of a step.
+func (state *State) Step(name Step) *StepState {
+	for i := range state.Steps {
+		if state.Steps[i].Name == name {
+			return &state.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Finished reports whether every step is done.
+func (state *State) Finished() bool {
+	for _, step := range state.Steps {
+		if step.Status != StepDone {
+			return false
+		}
+	}
+	return true
+}
+
+// StepError reports the step an onboarding failed in.
+type StepError struct {
+	Step Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("onboarding step '%s': %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// ErrTimeout is wrapped by the errors of the steps that waited longer than allowed.
+var ErrTimeout = errors.New("timed out")
+
+// Onboarder creates a data source connection and registers a connector VM with it.
+type Onboarder struct {
+	client    Client
+	connector ConnectorClient
+	tenantID  string
+	file      helpers.JSONFile[State]
+
+	// PollInterval is the delay between status checks. Defaults to 10 seconds.
+	PollInterval time.Duration
+
+	// RegistrationTimeout bounds the wait for the connector to report a successful registration. Defaults to 10
+	// minutes.
+	RegistrationTimeout time.Duration
+
+	// ConnectivityTimeout bounds the wait for the connector to be seen connected to the cluster. Defaults to 15
+	// minutes.
+	ConnectivityTimeout time.Duration
+
+	// RollbackOnFailure deletes the connection when a step fails. Cancelling the context never rolls back, so that
+	// the onboarding can be resumed. Defaults to true.
+	RollbackOnFailure bool
+
+	// OnChange, when set, is called after every saved change of the state.
+	OnChange func(state *State)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewOnboarder : Instantiate Onboarder. The connector client talks to the connector VM being onboarded, and the
+// progress is kept in the file at statePath.
+func NewOnboarder(client Client, connector ConnectorClient, tenantID string, statePath string) *Onboarder {
+	return &Onboarder{
+		client:              client,
+		connector:           connector,
+		tenantID:            tenantID,
+		file:                helpers.JSONFile[State]{Path: statePath},
+		PollInterval:        10 * time.Second,
+		RegistrationTimeout: 10 * time.Minute,
+		ConnectivityTimeout: 15 * time.Minute,
+		RollbackOnFailure:   true,
+		Now:                 time.Now,
+	}
+}
+
+// Load returns the saved state, or nil when there is none.
+func (onboarder *Onboarder) Load() (*State, error) {
+	return onboarder.file.Load()
+}
+
+// Run onboards the connection described by request. A saved onboarding of the same connection is resumed from its
+// first step that is not done; one that was rolled back starts over. Failures are returned as a *StepError.
+func (onboarder *Onboarder) Run(ctx context.Context, request Request) (*State, error) {
+	if request.ConnectionName == "" {
+		return nil, errors.New("connection name is required")
+	}
+	state, err := onboarder.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Request.ConnectionName != request.ConnectionName {
+		return state, fmt.Errorf("state '%s' belongs to the onboarding of connection '%s'", onboarder.file.Path, state.Request.ConnectionName)
+	}
+	if state == nil || state.RolledBack {
+		state = &State{Request: request, CreatedAt: onboarder.Now()}
+		for _, name := range Steps {
+			state.Steps = append(state.Steps, StepState{Name: name, Status: StepPending})
+		}
+	}
+	if step := state.Step(StepRegisterConnector); step.Status != StepDone {
+		// The token is not saved, so it has to be generated again.
+		state.Step(StepGenerateToken).Status = StepPending
+	}
+	if err := onboarder.save(state); err != nil {
+		return state, err
+	}
+
+	for i := range state.Steps {
+		step := &state.Steps[i]
+		if step.Status == StepDone {
+			continue
+		}
+		started := onboarder.Now()
+		step.Status, step.StartedAt, step.FinishedAt, step.Error = StepRunning, &started, nil, ""
+		step.Attempts++
+		if err := onboarder.save(state); err != nil {
+			return state, err
+		}
+
+		err := onboarder.run(ctx, state, step)
+		finished := onboarder.Now()
+		step.FinishedAt = &finished
+		if err == nil {
+			step.Status = StepDone
+			if err := onboarder.save(state); err != nil {
+				return state, err
+			}
+			continue
+		}
+
+		step.Status, step.Error = StepFailed, err.Error()
+		failure := &StepError{Step: step.Name, Err: err}
+		if saveErr := onboarder.save(state); saveErr != nil {
+			return state, errors.Join(failure, saveErr)
+		}
+		if ctx.Err() != nil || !onboarder.RollbackOnFailure {
+			return state, failure
+		}
+		if rollbackErr := onboarder.rollback(ctx, state); rollbackErr != nil {
+			return state, errors.Join(failure, fmt.Errorf("rolling back: %w", rollbackErr))
+		}
+		return state, failure
+	}
+	return state, nil
+}
+
+// Rollback deletes the connection of the saved onboarding, unless it was adopted. It does nothing when there is no
+// saved onboarding or when it was already rolled back.
+func (onboarder *Onboarder) Rollback(ctx context.Context) (*State, error) {
+	state, err := onboarder.file.Load()
+	if err != nil || state == nil || state.RolledBack {
+		return state, err
+	}
+	return state, onboarder.rollback(ctx, state)
+}
+
+func (onboarder *Onboarder) rollback(ctx context.Context, state *State) error {
+	if state.ConnectionID != "" && !state.Adopted {
+		options := &backuprecoveryv1.DeleteDataSourceConnectionOptions{
+			ConnectionID: core.StringPtr(state.ConnectionID),
+			XIBMTenantID: core.StringPtr(onboarder.tenantID),
+		}
+		response, err := onboarder.client.DeleteDataSourceConnectionWithContext(ctx, options)
+		if err != nil && (response == nil || response.StatusCode != 404) {
+			return fmt.Errorf("deleting connection '%s': %w", state.ConnectionID, err)
+		}
+	}
+	state.RolledBack = true
+	return onboarder.save(state)
+}
+
+func (onboarder *Onboarder) run(ctx context.Context, state *State, step *StepState) error {
+	switch step.Name {
+	case StepCreateConnection:
+		return onboarder.createConnection(ctx, state, step)
+	case StepGenerateToken:
+		return onboarder.generateToken(ctx, state)
+	case StepRegisterConnector:
+		return onboarder.registerConnector(ctx, state)
+	case StepAwaitRegistration:
+		return onboarder.awaitRegistration(ctx)
+	case StepAwaitConnectivity:
+		return onboarder.awaitConnectivity(ctx, state)
+	}
+	return fmt.Errorf("unknown step '%s'", step.Name)
+}
+
+func (onboarder *Onboarder) createConnection(ctx context.Context, state *State, step *StepState) error {
+	// Look the connection up first, so that a create that succeeded before the state was saved is not repeated.
+	existing, err := onboarder.findConnection(ctx, state.Request.ConnectionName)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		state.ConnectionID = helpers.Deref(existing.ConnectionID)
+		// A connection found on the first attempt was not created by this onboarding.
+		state.Adopted = step.Attempts == 1
+		return nil
+	}
+	options := &backuprecoveryv1.CreateDataSourceConnectionOptions{
+		XIBMTenantID:   core.StringPtr(onboarder.tenantID),
+		ConnectionName: core.StringPtr(state.Request.ConnectionName),
+	}
+	if state.Request.ConnectionEnvType != "" {
+		options.ConnectionEnvType = core.StringPtr(state.Request.ConnectionEnvType)
+	}
+	connection, _, err := onboarder.client.CreateDataSourceConnectionWithContext(ctx, options)
+	if err != nil {
+		return fmt.Errorf("creating connection '%s': %w", state.Request.ConnectionName, err)
+	}
+	if connection == nil || helpers.Deref(connection.ConnectionID) == "" {
+		return fmt.Errorf("creating connection '%s': no connection ID returned", state.Request.ConnectionName)
+	}
+	state.ConnectionID, state.Adopted = *connection.ConnectionID, false
+	return nil
+}
+
+func (onboarder *Onboarder) findConnection(ctx context.Context, name string) (*backuprecoveryv1.DataSourceConnection, error) {
+	options := &backuprecoveryv1.GetDataSourceConnectionsOptions{
+		XIBMTenantID:    core.StringPtr(onboarder.tenantID),
+		ConnectionNames: []string{name},
+	}
+	list, _, err := onboarder.client.GetDataSourceConnectionsWithContext(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("listing connections named '%s': %w", name, err)
+	}
+	if list == nil {
+		return nil, nil
+	}
+	for i := range list.Connections {
+		if helpers.Deref(list.Connections[i].ConnectionName) == name {
+			return &list.Connections[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (onboarder *Onboarder) generateToken(ctx context.Context, state *State) error {
+	options := &backuprecoveryv1.GenerateDataSourceConnectionRegistrationTokenOptions{
+		ConnectionID: core.StringPtr(state.ConnectionID),
+		XIBMTenantID: core.StringPtr(onboarder.tenantID),
+	}
+	token, _, err := onboarder.client.GenerateDataSourceConnectionRegistrationTokenWithContext(ctx, options)
+	if err != nil {
+		return fmt.Errorf("generating registration token for connection '%s': %w", state.ConnectionID, err)
+	}
+	if helpers.Deref(token) == "" {
+		return fmt.Errorf("generating registration token for connection '%s': empty token", state.ConnectionID)
+	}
+	state.registrationToken = *token
+	return nil
+}
+
+func (onboarder *Onboarder) registerConnector(ctx context.Context, state *State) error {
+	// A connector that registered before the state was saved is not registered again.
+	status, _, err := onboarder.connector.GetDataSourceConnectorStatusWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectorStatusOptions{})
+	if err == nil && registrationStatus(status) == backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Success {
+		return nil
+	}
+	options := &backuprecoveryv1.RegisterDataSourceConnectorOptions{
+		RegistrationToken: core.StringPtr(state.registrationToken),
+	}
+	if state.Request.ConnectorID != 0 {
+		options.ConnectorID = core.Int64Ptr(state.Request.ConnectorID)
+	}
+	if _, err := onboarder.connector.RegisterDataSourceConnectorWithContext(ctx, options); err != nil {
+		return fmt.Errorf("registering connector with connection '%s': %w", state.ConnectionID, err)
+	}
+	return nil
+}
+
+func (onboarder *Onboarder) awaitRegistration(ctx context.Context) error {
+	return onboarder.poll(ctx, onboarder.RegistrationTimeout, func() (bool, error) {
+		status, _, err := onboarder.connector.GetDataSourceConnectorStatusWithContext(ctx, &backuprecoveryv1.GetDataSourceConnectorStatusOptions{})
+		if err != nil {
+			// The connector restarts services while registering, so errors are retried until the timeout.
+			return false, nil
+		}
+		switch registrationStatus(status) {
+		case backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Success:
+			return true, nil
+		case backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Failed:
+			return false, fmt.Errorf("connector registration failed: %s", helpers.Deref(status.RegistrationStatus.Message))
+		}
+		return false, nil
+	})
+}
+
+func (onboarder *Onboarder) awaitConnectivity(ctx context.Context, state *State) error {
+	return onboarder.poll(ctx, onboarder.ConnectivityTimeout, func() (bool, error) {
+		options := &backuprecoveryv1.GetDataSourceConnectorsOptions{
+			XIBMTenantID: core.StringPtr(onboarder.tenantID),
+			ConnectionID: core.StringPtr(state.ConnectionID),
+		}
+		list, _, err := onboarder.client.GetDataSourceConnectorsWithContext(ctx, options)
+		if err != nil {
+			return false, fmt.Errorf("listing connectors of connection '%s': %w", state.ConnectionID, err)
+		}
+		if list == nil {
+			return false, nil
+		}
+		for _, connector := range list.Connectors {
+			if state.Request.ConnectorName != "" && helpers.Deref(connector.ConnectorName) != state.Request.ConnectorName {
+				continue
+			}
+			if connected(connector) {
+				state.ConnectorID = helpers.Deref(connector.ConnectorID)
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// poll calls check until it reports done or fails, or until timeout elapsed.
+func (onboarder *Onboarder) poll(ctx context.Context, timeout time.Duration, check func() (bool, error)) error {
+	deadline := onboarder.Now().Add(timeout)
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		if !onboarder.Now().Before(deadline) {
+			return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		timer := time.NewTimer(onboarder.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (onboarder *Onboarder) save(state *State) error {
+	if err := onboarder.file.Save(state); err != nil {
+		return fmt.Errorf("saving state '%s': %w", onboarder.file.Path, err)
+	}
+	if onboarder.OnChange != nil {
+		onboarder.OnChange(state)
+	}
+	return nil
+}
+
+func registrationStatus(status *backuprecoveryv1.DataSourceConnectorLocalStatus) string {
+	if status == nil || status.RegistrationStatus == nil {
+		return ""
+	}
+	return helpers.Deref(status.RegistrationStatus.Status)
+}
@@ -0,0 +1,246 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	connections []backuprecoveryv1.DataSourceConnection
+	connectors  []backuprecoveryv1.DataSourceConnector
+	created     int
+	deleted     []string
+	tokens      int
+	failCreate  error
+}
+
+func (client *fakeClient) GetDataSourceConnectionsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectionsOptions) (*backuprecoveryv1.DataSourceConnectionList, *core.DetailedResponse, error) {
+	list := &backuprecoveryv1.DataSourceConnectionList{}
+	for _, connection := range client.connections {
+		for _, name := range options.ConnectionNames {
+			if *connection.ConnectionName == name {
+				list.Connections = append(list.Connections, connection)
+			}
+		}
+	}
+	return list, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeClient) CreateDataSourceConnectionWithContext(ctx context.Context, options *backuprecoveryv1.CreateDataSourceConnectionOptions) (*backuprecoveryv1.DataSourceConnection, *core.DetailedResponse, error) {
+	if client.failCreate != nil {
+		return nil, &core.DetailedResponse{StatusCode: 500}, client.failCreate
+	}
+	client.created++
+	connection := backuprecoveryv1.DataSourceConnection{
+		ConnectionID:   core.StringPtr("conn-1"),
+		ConnectionName: options.ConnectionName,
+	}
+	client.connections = append(client.connections, connection)
+	return &connection, &core.DetailedResponse{StatusCode: 201}, nil
+}
+
+func (client *fakeClient) DeleteDataSourceConnectionWithContext(ctx context.Context, options *backuprecoveryv1.DeleteDataSourceConnectionOptions) (*core.DetailedResponse, error) {
+	client.deleted = append(client.deleted, *options.ConnectionID)
+	client.connections = nil
+	return &core.DetailedResponse{StatusCode: 204}, nil
+}
+
+func (client *fakeClient) GenerateDataSourceConnectionRegistrationTokenWithContext(ctx context.Context, options *backuprecoveryv1.GenerateDataSourceConnectionRegistrationTokenOptions) (*string, *core.DetailedResponse, error) {
+	client.tokens++
+	return core.StringPtr("token-" + *options.ConnectionID), &core.DetailedResponse{StatusCode: 201}, nil
+}
+
+func (client *fakeClient) GetDataSourceConnectorsWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorsOptions) (*backuprecoveryv1.DataSourceConnectorList, *core.DetailedResponse, error) {
+	list := &backuprecoveryv1.DataSourceConnectorList{}
+	for _, connector := range client.connectors {
+		if *connector.ConnectionID == *options.ConnectionID {
+			list.Connectors = append(list.Connectors, connector)
+		}
+	}
+	return list, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+type fakeConnector struct {
+	client       *fakeClient
+	tokens       []string
+	status       string
+	failStatus   bool
+	failRegister error
+}
+
+func (connector *fakeConnector) RegisterDataSourceConnectorWithContext(ctx context.Context, options *backuprecoveryv1.RegisterDataSourceConnectorOptions) (*core.DetailedResponse, error) {
+	if connector.failRegister != nil {
+		return &core.DetailedResponse{StatusCode: 400}, connector.failRegister
+	}
+	connector.tokens = append(connector.tokens, *options.RegistrationToken)
+	if connector.status == "" {
+		connector.status = backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Success
+	}
+	connector.client.connectors = append(connector.client.connectors, backuprecoveryv1.DataSourceConnector{
+		ConnectionID:       core.StringPtr("conn-1"),
+		ConnectorID:        core.StringPtr("7"),
+		ConnectorName:      core.StringPtr("vm-1"),
+		ConnectivityStatus: &backuprecoveryv1.ConnectorConnectivityStatus{IsConnected: core.BoolPtr(true)},
+	})
+	return &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (connector *fakeConnector) GetDataSourceConnectorStatusWithContext(ctx context.Context, options *backuprecoveryv1.GetDataSourceConnectorStatusOptions) (*backuprecoveryv1.DataSourceConnectorLocalStatus, *core.DetailedResponse, error) {
+	if connector.failStatus {
+		return nil, nil, errors.New("connection refused")
+	}
+	return &backuprecoveryv1.DataSourceConnectorLocalStatus{
+		RegistrationStatus: &backuprecoveryv1.DataSourceConnectorRegistrationStatus{
+			Status:  core.StringPtr(connector.status),
+			Message: core.StringPtr("bad token"),
+		},
+	}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func newOnboarder(t *testing.T) (*Onboarder, *fakeClient, *fakeConnector) {
+	client := &fakeClient{}
+	connector := &fakeConnector{client: client}
+	onboarder := NewOnboarder(client, connector, "tenant", filepath.Join(t.TempDir(), "onboarding.json"))
+	onboarder.PollInterval = time.Millisecond
+	onboarder.Now = func() time.Time { return base }
+	return onboarder, client, connector
+}
+
+func TestOnboarderRun(t *testing.T) {
+	onboarder, client, connector := newOnboarder(t)
+
+	state, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod", ConnectorName: "vm-1"})
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, "conn-1", state.ConnectionID)
+	assert.Equal(t, "7", state.ConnectorID)
+	assert.False(t, state.Adopted)
+	assert.Equal(t, []string{"token-conn-1"}, connector.tokens)
+	assert.Equal(t, 1, client.created)
+
+	saved, err := onboarder.Load()
+	require.Nil(t, err)
+	assert.True(t, saved.Finished())
+
+	// Running again finds nothing left to do.
+	_, err = onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.Nil(t, err)
+	assert.Equal(t, 1, client.created)
+	assert.Equal(t, 1, client.tokens)
+}
+
+func TestOnboarderRollsBackOnFailure(t *testing.T) {
+	onboarder, client, connector := newOnboarder(t)
+	connector.failRegister = errors.New("invalid token")
+
+	state, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	var stepErr *StepError
+	require.True(t, errors.As(err, &stepErr))
+	assert.Equal(t, StepRegisterConnector, stepErr.Step)
+	assert.Equal(t, StepFailed, state.Step(StepRegisterConnector).Status)
+	assert.Equal(t, []string{"conn-1"}, client.deleted)
+	assert.True(t, state.RolledBack)
+
+	// The next run starts over.
+	connector.failRegister = nil
+	state, err = onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, 2, client.created)
+}
+
+func TestOnboarderResumes(t *testing.T) {
+	onboarder, client, connector := newOnboarder(t)
+	onboarder.RollbackOnFailure = false
+	connector.failRegister = errors.New("connector unreachable")
+
+	_, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.NotNil(t, err)
+	assert.Empty(t, client.deleted)
+
+	connector.failRegister = nil
+	state, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, 1, client.created)
+	// The token is not saved, so it is generated again on resume.
+	assert.Equal(t, 2, client.tokens)
+	assert.Equal(t, 2, state.Step(StepRegisterConnector).Attempts)
+	assert.False(t, state.Adopted)
+}
+
+func TestOnboarderRegistrationFailure(t *testing.T) {
+	onboarder, client, connector := newOnboarder(t)
+	connector.status = backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Failed
+
+	_, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	var stepErr *StepError
+	require.True(t, errors.As(err, &stepErr))
+	assert.Equal(t, StepAwaitRegistration, stepErr.Step)
+	assert.Contains(t, err.Error(), "bad token")
+	assert.Equal(t, []string{"conn-1"}, client.deleted)
+}
+
+func TestOnboarderTimeout(t *testing.T) {
+	onboarder, _, connector := newOnboarder(t)
+	connector.status = backuprecoveryv1.DataSourceConnectorRegistrationStatus_Status_Inprogress
+	now := base
+	onboarder.Now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	_, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	assert.True(t, errors.Is(err, ErrTimeout))
+}
+
+func TestOnboarderKeepsAdoptedConnection(t *testing.T) {
+	onboarder, client, connector := newOnboarder(t)
+	client.connections = []backuprecoveryv1.DataSourceConnection{{
+		ConnectionID:   core.StringPtr("conn-1"),
+		ConnectionName: core.StringPtr("prod"),
+	}}
+	connector.failRegister = errors.New("invalid token")
+
+	state, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.NotNil(t, err)
+	assert.True(t, state.Adopted)
+	assert.Equal(t, 0, client.created)
+	assert.Empty(t, client.deleted)
+	assert.True(t, state.RolledBack)
+}
+
+func TestOnboarderRejectsOtherConnection(t *testing.T) {
+	onboarder, _, _ := newOnboarder(t)
+	_, err := onboarder.Run(context.Background(), Request{ConnectionName: "prod"})
+	require.Nil(t, err)
+
+	_, err = onboarder.Run(context.Background(), Request{ConnectionName: "dev"})
+	assert.ErrorContains(t, err, "'prod'")
+}
@@ -0,0 +1,404 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+	"sigs.k8s.io/yaml"
+)
+
+// UserClient is the subset of the operations served by a connector VM that is used by the Syncer.
+type UserClient interface {
+	GetUsersWithContext(ctx context.Context, getUsersOptions *backuprecoveryv1.GetUsersOptions) (result []backuprecoveryv1.UserDetails, response *core.DetailedResponse, err error)
+	UpdateUserWithContext(ctx context.Context, updateUserOptions *backuprecoveryv1.UpdateUserOptions) (result *backuprecoveryv1.UserDetails, response *core.DetailedResponse, err error)
+}
+
+var _ UserClient = (*backuprecoveryv1.BackupRecoveryV1Connector)(nil)
+
+// DefaultDomain is the domain of users whose domain is not set.
+const DefaultDomain = "LOCAL"
+
+// DesiredUser is the baseline of one connector user. Unset fields are not checked.
+type DesiredUser struct {
+	Username     string   `json:"username"`
+	Domain       string   `json:"domain,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+	Restricted   *bool    `json:"restricted,omitempty"`
+	Active       *bool    `json:"active,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	EmailAddress *string  `json:"emailAddress,omitempty"`
+}
+
+func (user DesiredUser) key() string {
+	return userKey(user.Domain, user.Username)
+}
+
+// Baseline is the desired state of the users of connectors, usually read from a YAML file such as
+//
+//	exclusive: true
+//	users:
+//	  - username: admin
+//	    roles: [COHESITY_ADMIN]
+//	    restricted: false
+//	  - username: auditor
+//	    roles: [COHESITY_VIEWER]
+//	    restricted: true
+type Baseline struct {
+	Users []DesiredUser `json:"users"`
+
+	// Exclusive reports the users of a connector that are not in the baseline as drift. They are never changed.
+	Exclusive bool `json:"exclusive,omitempty"`
+}
+
+// ParseBaseline decodes and validates a baseline in YAML or JSON. Unknown fields are rejected.
+func ParseBaseline(content []byte) (*Baseline, error) {
+	baseline := &Baseline{}
+	if err := yaml.UnmarshalStrict(content, baseline); err != nil {
+		return nil, fmt.Errorf("decoding baseline: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, user := range baseline.Users {
+		if user.Username == "" {
+			return nil, fmt.Errorf("user %d of the baseline has no username", i+1)
+		}
+		if seen[user.key()] {
+			return nil, fmt.Errorf("user '%s' is listed twice in the baseline", user.key())
+		}
+		seen[user.key()] = true
+	}
+	return baseline, nil
+}
+
+// LoadBaseline reads the baseline in the file at path.
+func LoadBaseline(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := ParseBaseline(content)
+	if err != nil {
+		return nil, fmt.Errorf("loading '%s': %w", path, err)
+	}
+	return baseline, nil
+}
+
+// DriftKind classifies a drift.
+type DriftKind string
+
+// The values of DriftKind.
+const (
+	// DriftMissing is a user of the baseline that does not exist on the connector. Users are not created.
+	DriftMissing DriftKind = "missing"
+	// DriftChanged is a user whose fields differ from the baseline.
+	DriftChanged DriftKind = "changed"
+	// DriftUnmanaged is a user of the connector that is not in an exclusive baseline.
+	DriftUnmanaged DriftKind = "unmanaged"
+)
+
+// FieldDrift is a field of a user that differs from the baseline.
+type FieldDrift struct {
+	Field  string `json:"field"`
+	Want   string `json:"want"`
+	Actual string `json:"actual"`
+}
+
+// Drift is a user of a connector that does not match the baseline.
+type Drift struct {
+	Username string       `json:"username"`
+	Domain   string       `json:"domain"`
+	Kind     DriftKind    `json:"kind"`
+	Fields   []FieldDrift `json:"fields,omitempty"`
+}
+
+// Diff compares the users of a connector with the baseline.
+func (baseline *Baseline) Diff(users []backuprecoveryv1.UserDetails) []Drift {
+	actual := map[string]*backuprecoveryv1.UserDetails{}
+	for i := range users {
+		actual[userKey(helpers.Deref(users[i].Domain), helpers.Deref(users[i].Username))] = &users[i]
+	}
+	var drifts []Drift
+	managed := map[string]bool{}
+	for _, want := range baseline.Users {
+		managed[want.key()] = true
+		domain, username := splitUserKey(want.key())
+		user, ok := actual[want.key()]
+		if !ok {
+			drifts = append(drifts, Drift{Username: username, Domain: domain, Kind: DriftMissing})
+			continue
+		}
+		if fields := diffUser(want, user); len(fields) > 0 {
+			drifts = append(drifts, Drift{Username: username, Domain: domain, Kind: DriftChanged, Fields: fields})
+		}
+	}
+	if baseline.Exclusive {
+		var unmanaged []Drift
+		for key := range actual {
+			if !managed[key] {
+				domain, username := splitUserKey(key)
+				unmanaged = append(unmanaged, Drift{Username: username, Domain: domain, Kind: DriftUnmanaged})
+			}
+		}
+		sort.Slice(unmanaged, func(i, j int) bool {
+			return userKey(unmanaged[i].Domain, unmanaged[i].Username) < userKey(unmanaged[j].Domain, unmanaged[j].Username)
+		})
+		drifts = append(drifts, unmanaged...)
+	}
+	return drifts
+}
+
+func diffUser(want DesiredUser, user *backuprecoveryv1.UserDetails) []FieldDrift {
+	var fields []FieldDrift
+	if want.Roles != nil {
+		wantRoles, actualRoles := sortedCopy(want.Roles), sortedCopy(user.Roles)
+		if !slices.Equal(wantRoles, actualRoles) {
+			fields = append(fields, FieldDrift{Field: "roles", Want: strings.Join(wantRoles, ","), Actual: strings.Join(actualRoles, ",")})
+		}
+	}
+	compareBool := func(field string, want *bool, actual *bool) {
+		if want != nil && *want != (actual != nil && *actual) {
+			fields = append(fields, FieldDrift{Field: field, Want: fmt.Sprint(*want), Actual: fmt.Sprint(!*want)})
+		}
+	}
+	compareString := func(field string, want *string, actual *string) {
+		if want != nil && *want != helpers.Deref(actual) {
+			fields = append(fields, FieldDrift{Field: field, Want: *want, Actual: helpers.Deref(actual)})
+		}
+	}
+	compareBool("restricted", want.Restricted, user.Restricted)
+	compareBool("active", want.Active, user.IsActive)
+	compareString("description", want.Description, user.Description)
+	compareString("emailAddress", want.EmailAddress, user.EmailAddress)
+	return fields
+}
+
+// Endpoint is a connector whose users are synchronized.
+type Endpoint struct {
+	// Name identifies the connector in reports.
+	Name string
+
+	Client UserClient
+
+	// SessionName is the session of the connector the users are read and updated with.
+	SessionName string
+}
+
+// EndpointReport is the result of synchronizing one connector.
+type EndpointReport struct {
+	Endpoint string  `json:"endpoint"`
+	Drift    []Drift `json:"drift,omitempty"`
+
+	// Updated lists the users that were updated to match the baseline, as domain\username.
+	Updated []string `json:"updated,omitempty"`
+
+	// Errors lists the failures. The users that could not be read or updated are still reported as drift.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SyncReport is the result of synchronizing a set of connectors.
+type SyncReport struct {
+	DryRun    bool             `json:"dryRun"`
+	Endpoints []EndpointReport `json:"endpoints"`
+}
+
+// Drifted reports whether a connector has a user that does not match the baseline, or could not be checked.
+func (report *SyncReport) Drifted() bool {
+	for _, endpoint := range report.Endpoints {
+		if len(endpoint.Errors) > 0 {
+			return true
+		}
+		for _, drift := range endpoint.Drift {
+			if drift.Kind != DriftChanged || !slices.Contains(endpoint.Updated, userKey(drift.Domain, drift.Username)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteTable writes one row per drifted field, missing or unmanaged user, and error.
+func (report *SyncReport) WriteTable(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ENDPOINT\tUSER\tDRIFT\tFIELD\tWANT\tACTUAL\tFIXED")
+	for _, endpoint := range report.Endpoints {
+		for _, drift := range endpoint.Drift {
+			user := userKey(drift.Domain, drift.Username)
+			fixed := slices.Contains(endpoint.Updated, user)
+			if len(drift.Fields) == 0 {
+				fmt.Fprintf(table, "%s\t%s\t%s\t\t\t\t%t\n", endpoint.Endpoint, user, drift.Kind, fixed)
+			}
+			for _, field := range drift.Fields {
+				fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%t\n", endpoint.Endpoint, user, drift.Kind, field.Field, field.Want, field.Actual, fixed)
+			}
+		}
+		for _, message := range endpoint.Errors {
+			fmt.Fprintf(table, "%s\t\terror\t%s\t\t\t\n", endpoint.Endpoint, message)
+		}
+	}
+	return table.Flush()
+}
+
+// Syncer makes the users of connectors match a baseline.
+type Syncer struct {
+	baseline *Baseline
+
+	// Concurrency bounds the number of connectors synchronized at once. Defaults to 8.
+	Concurrency int
+
+	// DryRun reports the drift without updating any user.
+	DryRun bool
+}
+
+// NewSyncer : Instantiate Syncer
+func NewSyncer(baseline *Baseline) *Syncer {
+	return &Syncer{
+		baseline:    baseline,
+		Concurrency: 8,
+	}
+}
+
+// Sync compares the users of every endpoint with the baseline and updates those that drifted, unless DryRun is set.
+// Missing and unmanaged users are only reported. Endpoints are synchronized in parallel, and the failure of one does
+// not stop the others.
+func (syncer *Syncer) Sync(ctx context.Context, endpoints []Endpoint) *SyncReport {
+	report := &SyncReport{DryRun: syncer.DryRun, Endpoints: make([]EndpointReport, len(endpoints))}
+	slots := make(chan struct{}, max(1, syncer.Concurrency))
+	var group sync.WaitGroup
+	for i, endpoint := range endpoints {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				report.Endpoints[i] = syncer.sync(ctx, endpoint)
+			case <-ctx.Done():
+				report.Endpoints[i] = EndpointReport{Endpoint: endpoint.Name, Errors: []string{ctx.Err().Error()}}
+			}
+		}()
+	}
+	group.Wait()
+	return report
+}
+
+func (syncer *Syncer) sync(ctx context.Context, endpoint Endpoint) EndpointReport {
+	report := EndpointReport{Endpoint: endpoint.Name}
+	users, _, err := endpoint.Client.GetUsersWithContext(ctx, &backuprecoveryv1.GetUsersOptions{
+		SessionName: core.StringPtr(endpoint.SessionName),
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("listing users: %v", err))
+		return report
+	}
+	report.Drift = syncer.baseline.Diff(users)
+	if syncer.DryRun {
+		return report
+	}
+
+	desired := map[string]DesiredUser{}
+	for _, user := range syncer.baseline.Users {
+		desired[user.key()] = user
+	}
+	actual := map[string]*backuprecoveryv1.UserDetails{}
+	for i := range users {
+		actual[userKey(helpers.Deref(users[i].Domain), helpers.Deref(users[i].Username))] = &users[i]
+	}
+	for _, drift := range report.Drift {
+		if drift.Kind != DriftChanged {
+			continue
+		}
+		key := userKey(drift.Domain, drift.Username)
+		options, err := updateUserOptions(actual[key], desired[key], endpoint.SessionName)
+		if err == nil {
+			_, _, err = endpoint.Client.UpdateUserWithContext(ctx, options)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("updating user '%s': %v", key, err))
+			continue
+		}
+		report.Updated = append(report.Updated, key)
+	}
+	return report
+}
+
+// updateUserOptions returns the update of a user that applies the baseline. The update carries every other field
+// of the user unchanged.
+func updateUserOptions(user *backuprecoveryv1.UserDetails, want DesiredUser, sessionName string) (*backuprecoveryv1.UpdateUserOptions, error) {
+	content, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	options := &backuprecoveryv1.UpdateUserOptions{}
+	if err := json.Unmarshal(content, options); err != nil {
+		return nil, err
+	}
+	if options.Username == nil {
+		return nil, errors.New("user has no username")
+	}
+	options.SessionName = core.StringPtr(sessionName)
+	if want.Roles != nil {
+		options.Roles = want.Roles
+	}
+	if want.Restricted != nil {
+		options.Restricted = want.Restricted
+	}
+	if want.Active != nil {
+		options.IsActive = want.Active
+	}
+	if want.Description != nil {
+		options.Description = want.Description
+	}
+	if want.EmailAddress != nil {
+		options.EmailAddress = want.EmailAddress
+	}
+	return options, nil
+}
+
+// userKey returns domain\username with the domain defaulted and upper-cased, as domains are case insensitive.
+func userKey(domain string, username string) string {
+	if domain == "" {
+		domain = DefaultDomain
+	}
+	return strings.ToUpper(domain) + `\` + username
+}
+
+func splitUserKey(key string) (string, string) {
+	domain, username, _ := strings.Cut(key, `\`)
+	return domain, username
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,150 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineYAML = `
+exclusive: true
+users:
+  - username: admin
+    roles: [COHESITY_ADMIN]
+    restricted: false
+  - username: auditor
+    domain: local
+    roles: [COHESITY_VIEWER, COHESITY_AUDITOR]
+    restricted: true
+    description: Security review account
+  - username: backup
+    roles: [COHESITY_BACKUP]
+`
+
+type fakeUserClient struct {
+	mutex   sync.Mutex
+	users   []backuprecoveryv1.UserDetails
+	updates []*backuprecoveryv1.UpdateUserOptions
+	fail    error
+}
+
+func (client *fakeUserClient) GetUsersWithContext(ctx context.Context, options *backuprecoveryv1.GetUsersOptions) ([]backuprecoveryv1.UserDetails, *core.DetailedResponse, error) {
+	if client.fail != nil {
+		return nil, nil, client.fail
+	}
+	return client.users, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeUserClient) UpdateUserWithContext(ctx context.Context, options *backuprecoveryv1.UpdateUserOptions) (*backuprecoveryv1.UserDetails, *core.DetailedResponse, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.updates = append(client.updates, options)
+	return &backuprecoveryv1.UserDetails{}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func connectorUsers() []backuprecoveryv1.UserDetails {
+	return []backuprecoveryv1.UserDetails{
+		{Username: core.StringPtr("admin"), Domain: core.StringPtr("LOCAL"), Roles: []string{"COHESITY_ADMIN"}, Restricted: core.BoolPtr(false), Sid: core.StringPtr("S-1")},
+		{Username: core.StringPtr("auditor"), Domain: core.StringPtr("LOCAL"), Roles: []string{"COHESITY_ADMIN"}, Sid: core.StringPtr("S-2"), EmailAddress: core.StringPtr("audit@example.com")},
+		{Username: core.StringPtr("legacy"), Domain: core.StringPtr("LOCAL"), Roles: []string{"COHESITY_ADMIN"}},
+	}
+}
+
+func TestParseBaseline(t *testing.T) {
+	baseline, err := ParseBaseline([]byte(baselineYAML))
+	require.Nil(t, err)
+	assert.True(t, baseline.Exclusive)
+	require.Len(t, baseline.Users, 3)
+	assert.Equal(t, "Security review account", *baseline.Users[1].Description)
+	assert.Nil(t, baseline.Users[2].Restricted)
+
+	_, err = ParseBaseline([]byte("users:\n  - username: a\n    role: [x]\n"))
+	assert.NotNil(t, err)
+	_, err = ParseBaseline([]byte("users:\n  - username: a\n  - username: a\n    domain: LOCAL\n"))
+	assert.ErrorContains(t, err, "listed twice")
+	_, err = ParseBaseline([]byte("users:\n  - roles: [x]\n"))
+	assert.ErrorContains(t, err, "no username")
+}
+
+func TestBaselineDiff(t *testing.T) {
+	baseline, err := ParseBaseline([]byte(baselineYAML))
+	require.Nil(t, err)
+
+	drifts := baseline.Diff(connectorUsers())
+	assert.Equal(t, []Drift{
+		{Username: "auditor", Domain: "LOCAL", Kind: DriftChanged, Fields: []FieldDrift{
+			{Field: "roles", Want: "COHESITY_AUDITOR,COHESITY_VIEWER", Actual: "COHESITY_ADMIN"},
+			{Field: "restricted", Want: "true", Actual: "false"},
+			{Field: "description", Want: "Security review account", Actual: ""},
+		}},
+		{Username: "backup", Domain: "LOCAL", Kind: DriftMissing},
+		{Username: "legacy", Domain: "LOCAL", Kind: DriftUnmanaged},
+	}, drifts)
+}
+
+func TestSyncerSync(t *testing.T) {
+	baseline, err := ParseBaseline([]byte(baselineYAML))
+	require.Nil(t, err)
+	first := &fakeUserClient{users: connectorUsers()}
+	second := &fakeUserClient{fail: errors.New("session expired")}
+	endpoints := []Endpoint{
+		{Name: "vm-1", Client: first, SessionName: "token-1"},
+		{Name: "vm-2", Client: second, SessionName: "token-2"},
+	}
+	syncer := NewSyncer(baseline)
+
+	syncer.DryRun = true
+	report := syncer.Sync(context.Background(), endpoints)
+	assert.True(t, report.Drifted())
+	assert.Empty(t, first.updates)
+	assert.Len(t, report.Endpoints[0].Drift, 3)
+	assert.Equal(t, []string{"listing users: session expired"}, report.Endpoints[1].Errors)
+
+	syncer.DryRun = false
+	report = syncer.Sync(context.Background(), endpoints)
+	require.Len(t, first.updates, 1)
+	update := first.updates[0]
+	assert.Equal(t, "token-1", *update.SessionName)
+	assert.Equal(t, "auditor", *update.Username)
+	assert.Equal(t, "S-2", *update.Sid)
+	assert.Equal(t, "audit@example.com", *update.EmailAddress)
+	assert.Equal(t, []string{"COHESITY_VIEWER", "COHESITY_AUDITOR"}, update.Roles)
+	assert.True(t, *update.Restricted)
+	assert.Equal(t, []string{`LOCAL\auditor`}, report.Endpoints[0].Updated)
+
+	var table bytes.Buffer
+	require.Nil(t, report.WriteTable(&table))
+	assert.Contains(t, table.String(), `vm-1      LOCAL\backup`)
+	assert.Contains(t, table.String(), "session expired")
+
+	// Only missing users are left on a reachable connector with a baseline that is not exclusive.
+	baseline.Exclusive = false
+	second.fail = nil
+	second.users = connectorUsers()[:1]
+	report = NewSyncer(baseline).Sync(context.Background(), endpoints[1:])
+	assert.True(t, report.Drifted())
+	assert.Len(t, second.updates, 0)
+}
@@ -0,0 +1,342 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package coverage finds production assets that are not, or no longer effectively, protected.
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	ListProtectionSourcesWithContext(ctx context.Context, listProtectionSourcesOptions *backuprecoveryv1.ListProtectionSourcesOptions) (result []backuprecoveryv1.ProtectionSourceNodes, response *core.DetailedResponse, err error)
+	SearchObjectsWithContext(ctx context.Context, searchObjectsOptions *backuprecoveryv1.SearchObjectsOptions) (result *backuprecoveryv1.ObjectsSearchResponseBody, response *core.DetailedResponse, err error)
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// DefaultAssetTypes maps each supported environment to the protection source types that count as assets: hosts
+// for kPhysical, databases for kSQL and namespaces for kKubernetes.
+var DefaultAssetTypes = map[string][]string{
+	backuprecoveryv1.ListProtectionSourcesOptions_Environments_Kphysical:   {backuprecoveryv1.PhysicalProtectionSource_Type_Khost},
+	backuprecoveryv1.ListProtectionSourcesOptions_Environments_Ksql:        {backuprecoveryv1.SqlProtectionSource_Type_Kdatabase},
+	backuprecoveryv1.ListProtectionSourcesOptions_Environments_Kkubernetes: {backuprecoveryv1.KubernetesProtectionSource_Type_Knamespace},
+}
+
+// FindingKind classifies a coverage gap.
+type FindingKind string
+
+const (
+	// FindingUnprotected means no protection group covers the asset.
+	FindingUnprotected FindingKind = "unprotected"
+	// FindingLastRunFailed means the last run that covered the asset did not succeed.
+	FindingLastRunFailed FindingKind = "last_run_failed"
+	// FindingGroupPaused means the asset is only protected by a paused or inactive group.
+	FindingGroupPaused FindingKind = "group_paused"
+	// FindingGroupDeleted means the asset is only protected by a deleted group.
+	FindingGroupDeleted FindingKind = "group_deleted"
+)
+
+// Finding is one coverage gap for one asset.
+type Finding struct {
+	Kind          FindingKind `json:"kind"`
+	Environment   string      `json:"environment"`
+	ObjectID      int64       `json:"objectId"`
+	ObjectName    string      `json:"objectName"`
+	ObjectType    string      `json:"objectType"`
+	ParentName    string      `json:"parentName,omitempty"`
+	GroupID       string      `json:"groupId,omitempty"`
+	GroupName     string      `json:"groupName,omitempty"`
+	LastRunStatus string      `json:"lastRunStatus,omitempty"`
+}
+
+// EnvironmentSummary counts the assets found in one environment.
+type EnvironmentSummary struct {
+	Environment string `json:"environment"`
+	Assets      int    `json:"assets"`
+	Protected   int    `json:"protected"`
+	Unprotected int    `json:"unprotected"`
+	AtRisk      int    `json:"atRisk"`
+}
+
+// Report is the outcome of a coverage analysis.
+type Report struct {
+	GeneratedAt  time.Time            `json:"generatedAt"`
+	Environments []EnvironmentSummary `json:"environments"`
+	Findings     []Finding            `json:"findings"`
+}
+
+// Analyzer cross-references the source inventory with the protected objects of a tenant.
+type Analyzer struct {
+	client   Client
+	tenantID string
+
+	// AssetTypes selects the environments to analyze and the source types that count as assets in each of them.
+	// Defaults to DefaultAssetTypes.
+	AssetTypes map[string][]string
+
+	// PageSize is the number of objects requested per SearchObjects page. Defaults to 500.
+	PageSize int64
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewAnalyzer : Instantiate Analyzer
+func NewAnalyzer(client Client, tenantID string) *Analyzer {
+	return &Analyzer{client: client, tenantID: tenantID, AssetTypes: DefaultAssetTypes, PageSize: 500, Now: time.Now}
+}
+
+// asset is an inventory entry that is expected to be protected.
+type asset struct {
+	id         int64
+	name       string
+	sourceType string
+	parent     string
+}
+
+// protection is what SearchObjects reports about a protected object.
+type protection struct {
+	groups        []backuprecoveryv1.ObjectProtectionGroupSummary
+	lastRunStatus string
+}
+
+type groupState struct {
+	paused  bool
+	deleted bool
+}
+
+// Analyze builds a coverage report for every configured environment.
+func (analyzer *Analyzer) Analyze(ctx context.Context) (*Report, error) {
+	environments := make([]string, 0, len(analyzer.AssetTypes))
+	for environment := range analyzer.AssetTypes {
+		environments = append(environments, environment)
+	}
+	sort.Strings(environments)
+
+	groups, err := analyzer.groups(ctx, environments)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{GeneratedAt: analyzer.Now()}
+	for _, environment := range environments {
+		assets, err := analyzer.inventory(ctx, environment)
+		if err != nil {
+			return nil, err
+		}
+		protected, err := analyzer.protected(ctx, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := EnvironmentSummary{Environment: environment, Assets: len(assets)}
+		for _, a := range assets {
+			findings := evaluate(environment, a, protected, groups)
+			if _, ok := protected[a.id]; ok {
+				summary.Protected++
+			} else {
+				summary.Unprotected++
+			}
+			if len(findings) > 0 && findings[0].Kind != FindingUnprotected {
+				summary.AtRisk++
+			}
+			report.Findings = append(report.Findings, findings...)
+		}
+		report.Environments = append(report.Environments, summary)
+	}
+	return report, nil
+}
+
+func evaluate(environment string, a asset, protected map[int64]protection, groups map[string]groupState) []Finding {
+	base := Finding{Environment: environment, ObjectID: a.id, ObjectName: a.name, ObjectType: a.sourceType, ParentName: a.parent}
+	info, ok := protected[a.id]
+	if !ok {
+		base.Kind = FindingUnprotected
+		return []Finding{base}
+	}
+
+	var findings []Finding
+	if isFailure(info.lastRunStatus) {
+		f := base
+		f.Kind = FindingLastRunFailed
+		f.LastRunStatus = info.lastRunStatus
+		findings = append(findings, f)
+	}
+
+	var inactive []Finding
+	active := false
+	for _, group := range info.groups {
+		id := helpers.Deref(group.ID)
+		f := base
+		f.GroupID, f.GroupName = id, helpers.Deref(group.Name)
+		state, known := groups[id]
+		switch {
+		case !known || state.deleted:
+			f.Kind = FindingGroupDeleted
+		case state.paused:
+			f.Kind = FindingGroupPaused
+		default:
+			active = true
+			if status := helpers.Deref(group.LastBackupRunStatus); isFailure(status) && info.lastRunStatus == "" {
+				f.Kind = FindingLastRunFailed
+				f.LastRunStatus = status
+				findings = append(findings, f)
+			}
+			continue
+		}
+		inactive = append(inactive, f)
+	}
+	if !active {
+		findings = append(findings, inactive...)
+	}
+	return findings
+}
+
+func isFailure(status string) bool {
+	switch status {
+	case backuprecoveryv1.SearchObjectsOptions_LastRunStatusList_Failed,
+		backuprecoveryv1.SearchObjectsOptions_LastRunStatusList_Canceled,
+		backuprecoveryv1.SearchObjectsOptions_LastRunStatusList_Missed:
+		return true
+	}
+	return false
+}
+
+// groups returns the state of every protection group, including deleted ones, of the analyzed environments.
+func (analyzer *Analyzer) groups(ctx context.Context, environments []string) (map[string]groupState, error) {
+	states := make(map[string]groupState)
+	for _, deleted := range []bool{false, true} {
+		result, _, err := analyzer.client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+			XIBMTenantID: core.StringPtr(analyzer.tenantID),
+			Environments: environments,
+			IsDeleted:    core.BoolPtr(deleted),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing protection groups: %w", err)
+		}
+		if result == nil {
+			continue
+		}
+		for _, group := range result.ProtectionGroups {
+			states[helpers.Deref(group.ID)] = groupState{
+				paused:  helpers.Deref(group.IsPaused) || (group.IsActive != nil && !*group.IsActive),
+				deleted: helpers.Deref(group.IsDeleted),
+			}
+		}
+	}
+	return states, nil
+}
+
+// inventory lists the assets of an environment from the protection source tree.
+func (analyzer *Analyzer) inventory(ctx context.Context, environment string) ([]asset, error) {
+	nodes, _, err := analyzer.client.ListProtectionSourcesWithContext(ctx, &backuprecoveryv1.ListProtectionSourcesOptions{
+		XIBMTenantID: core.StringPtr(analyzer.tenantID),
+		Environments: []string{environment},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s protection sources: %w", environment, err)
+	}
+	wanted := make(map[string]bool)
+	for _, sourceType := range analyzer.AssetTypes[environment] {
+		wanted[sourceType] = true
+	}
+
+	seen := make(map[int64]bool)
+	var assets []asset
+	var walk func(nodes []backuprecoveryv1.ProtectionSourceNodes, parent string)
+	walk = func(nodes []backuprecoveryv1.ProtectionSourceNodes, parent string) {
+		for _, node := range nodes {
+			name := parent
+			if source := node.ProtectionSource; source != nil {
+				name = helpers.Deref(source.Name)
+				if sourceType := sourceType(source); wanted[sourceType] && !seen[helpers.Deref(source.ID)] {
+					seen[helpers.Deref(source.ID)] = true
+					assets = append(assets, asset{id: helpers.Deref(source.ID), name: name, sourceType: sourceType, parent: parent})
+				}
+			}
+			walk(node.Nodes, name)
+			walk(node.ApplicationNodes, name)
+		}
+	}
+	walk(nodes, "")
+	return assets, nil
+}
+
+func sourceType(source *backuprecoveryv1.ProtectionSourceNode) string {
+	switch {
+	case source.PhysicalProtectionSource != nil:
+		return helpers.Deref(source.PhysicalProtectionSource.Type)
+	case source.SqlProtectionSource != nil:
+		return helpers.Deref(source.SqlProtectionSource.Type)
+	case source.KubernetesProtectionSource != nil:
+		return helpers.Deref(source.KubernetesProtectionSource.Type)
+	}
+	return ""
+}
+
+// protected pages through SearchObjects and returns the protected objects of an environment by object id.
+func (analyzer *Analyzer) protected(ctx context.Context, environment string) (map[int64]protection, error) {
+	protected := make(map[int64]protection)
+	var cookie *string
+	for {
+		result, _, err := analyzer.client.SearchObjectsWithContext(ctx, &backuprecoveryv1.SearchObjectsOptions{
+			XIBMTenantID:     core.StringPtr(analyzer.tenantID),
+			Environments:     []string{environment},
+			IsProtected:      core.BoolPtr(true),
+			Count:            core.Int64Ptr(analyzer.PageSize),
+			PaginationCookie: cookie,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("searching protected %s objects: %w", environment, err)
+		}
+		if result == nil {
+			break
+		}
+		for _, object := range result.Objects {
+			// Objects whose protections were all deleted are left out, so they are reported as unprotected.
+			var info protection
+			active := false
+			for _, protectionInfo := range object.ObjectProtectionInfos {
+				if helpers.Deref(protectionInfo.IsDeleted) {
+					continue
+				}
+				active = true
+				info.groups = append(info.groups, protectionInfo.ProtectionGroups...)
+				if status := helpers.Deref(protectionInfo.LastRunStatus); status != "" {
+					info.lastRunStatus = status
+				}
+			}
+			if active {
+				protected[helpers.Deref(object.ID)] = info
+			}
+		}
+		if result.PaginationCookie == nil || *result.PaginationCookie == "" || len(result.Objects) == 0 {
+			break
+		}
+		cookie = result.PaginationCookie
+	}
+	return protected, nil
+}
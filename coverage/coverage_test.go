@@ -0,0 +1,157 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package coverage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	pages int
+}
+
+func host(id int64, name string, children ...backuprecoveryv1.ProtectionSourceNodes) backuprecoveryv1.ProtectionSourceNodes {
+	return backuprecoveryv1.ProtectionSourceNodes{
+		ProtectionSource: &backuprecoveryv1.ProtectionSourceNode{
+			ID:                       core.Int64Ptr(id),
+			Name:                     core.StringPtr(name),
+			PhysicalProtectionSource: &backuprecoveryv1.PhysicalProtectionSource{Type: core.StringPtr("kHost")},
+		},
+		ApplicationNodes: children,
+	}
+}
+
+func database(id int64, name string) backuprecoveryv1.ProtectionSourceNodes {
+	return backuprecoveryv1.ProtectionSourceNodes{
+		ProtectionSource: &backuprecoveryv1.ProtectionSourceNode{
+			ID:                  core.Int64Ptr(id),
+			Name:                core.StringPtr(name),
+			SqlProtectionSource: &backuprecoveryv1.SqlProtectionSource{Type: core.StringPtr("kDatabase")},
+		},
+	}
+}
+
+func (fake *fakeClient) ListProtectionSourcesWithContext(ctx context.Context, options *backuprecoveryv1.ListProtectionSourcesOptions) ([]backuprecoveryv1.ProtectionSourceNodes, *core.DetailedResponse, error) {
+	if options.Environments[0] != "kPhysical" && options.Environments[0] != "kSQL" {
+		return nil, nil, nil
+	}
+	root := backuprecoveryv1.ProtectionSourceNodes{
+		Nodes: []backuprecoveryv1.ProtectionSourceNodes{
+			host(1, "web01"),
+			host(2, "db01", database(20, "sales"), database(21, "hr")),
+			host(3, "app01"),
+		},
+	}
+	return []backuprecoveryv1.ProtectionSourceNodes{root}, nil, nil
+}
+
+func protectedObject(id int64, status string, groupIDs ...string) backuprecoveryv1.SearchObject {
+	info := backuprecoveryv1.ObjectProtectionInfo{ObjectID: core.Int64Ptr(id)}
+	if status != "" {
+		info.LastRunStatus = core.StringPtr(status)
+	}
+	for _, groupID := range groupIDs {
+		info.ProtectionGroups = append(info.ProtectionGroups, backuprecoveryv1.ObjectProtectionGroupSummary{
+			ID: core.StringPtr(groupID), Name: core.StringPtr("group " + groupID),
+		})
+	}
+	return backuprecoveryv1.SearchObject{ID: core.Int64Ptr(id), ObjectProtectionInfos: []backuprecoveryv1.ObjectProtectionInfo{info}}
+}
+
+func (fake *fakeClient) SearchObjectsWithContext(ctx context.Context, options *backuprecoveryv1.SearchObjectsOptions) (*backuprecoveryv1.ObjectsSearchResponseBody, *core.DetailedResponse, error) {
+	fake.pages++
+	switch {
+	case options.Environments[0] == "kPhysical" && options.PaginationCookie == nil:
+		return &backuprecoveryv1.ObjectsSearchResponseBody{
+			Objects:          []backuprecoveryv1.SearchObject{protectedObject(1, "Succeeded", "pg-active")},
+			PaginationCookie: core.StringPtr("next"),
+		}, nil, nil
+	case options.Environments[0] == "kPhysical":
+		return &backuprecoveryv1.ObjectsSearchResponseBody{
+			Objects: []backuprecoveryv1.SearchObject{protectedObject(2, "Failed", "pg-active"), protectedObject(3, "", "pg-paused", "pg-gone")},
+		}, nil, nil
+	case options.Environments[0] == "kSQL":
+		// The only protection of hr was deleted, so it is still unprotected.
+		deleted := protectedObject(21, "Succeeded", "pg-old")
+		deleted.ObjectProtectionInfos[0].IsDeleted = core.BoolPtr(true)
+		return &backuprecoveryv1.ObjectsSearchResponseBody{
+			Objects: []backuprecoveryv1.SearchObject{protectedObject(20, "Succeeded", "pg-active"), deleted},
+		}, nil, nil
+	}
+	return &backuprecoveryv1.ObjectsSearchResponseBody{}, nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	if *options.IsDeleted {
+		return &backuprecoveryv1.ProtectionGroupsResponse{}, nil, nil
+	}
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: []backuprecoveryv1.ProtectionGroupResponse{
+		{ID: core.StringPtr("pg-active"), IsActive: core.BoolPtr(true)},
+		{ID: core.StringPtr("pg-paused"), IsPaused: core.BoolPtr(true)},
+	}}, nil, nil
+}
+
+func TestAnalyze(t *testing.T) {
+	client := &fakeClient{}
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.Now = func() time.Time { return time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC) }
+
+	report, err := analyzer.Analyze(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, 4, client.pages)
+
+	require.Len(t, report.Environments, 3)
+	assert.Equal(t, EnvironmentSummary{Environment: "kKubernetes"}, report.Environments[0])
+	assert.Equal(t, EnvironmentSummary{Environment: "kPhysical", Assets: 3, Protected: 3, AtRisk: 2}, report.Environments[1])
+	assert.Equal(t, EnvironmentSummary{Environment: "kSQL", Assets: 2, Protected: 1, Unprotected: 1}, report.Environments[2])
+
+	var kinds []FindingKind
+	for _, finding := range report.Findings {
+		kinds = append(kinds, finding.Kind)
+	}
+	assert.Equal(t, []FindingKind{FindingLastRunFailed, FindingGroupPaused, FindingGroupDeleted, FindingUnprotected}, kinds)
+	assert.Equal(t, "hr", report.Findings[3].ObjectName)
+	assert.Equal(t, "db01", report.Findings[3].ParentName)
+}
+
+func TestReportOutput(t *testing.T) {
+	report := &Report{
+		GeneratedAt:  time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC),
+		Environments: []EnvironmentSummary{{Environment: "kSQL", Assets: 1, Unprotected: 1}},
+		Findings:     []Finding{{Kind: FindingUnprotected, Environment: "kSQL", ObjectID: 7, ObjectName: "hr", ObjectType: "kDatabase"}},
+	}
+
+	var text bytes.Buffer
+	require.Nil(t, report.WriteText(&text))
+	assert.Contains(t, text.String(), "2026-01-02T00:00:00Z")
+	assert.Contains(t, text.String(), "unprotected  kSQL         hr (7)")
+
+	var encoded bytes.Buffer
+	require.Nil(t, report.WriteJSON(&encoded))
+	var decoded Report
+	require.Nil(t, json.Unmarshal(encoded.Bytes(), &decoded))
+	assert.Equal(t, report.Findings, decoded.Findings)
+}
@@ -0,0 +1,61 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteJSON writes the report as indented JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteText writes the report as aligned plain text tables for human readers.
+func (report *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Protection coverage report generated %s\n\n", report.GeneratedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintln(tw, "ENVIRONMENT\tASSETS\tPROTECTED\tUNPROTECTED\tAT RISK")
+	for _, summary := range report.Environments {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n",
+			summary.Environment, summary.Assets, summary.Protected, summary.Unprotected, summary.AtRisk)
+	}
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(tw, "\nNo coverage gaps found.")
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "\nFINDING\tENVIRONMENT\tOBJECT\tTYPE\tPARENT\tGROUP\tLAST RUN")
+	for _, finding := range report.Findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s (%d)\t%s\t%s\t%s\t%s\n",
+			finding.Kind, finding.Environment, finding.ObjectName, finding.ObjectID, finding.ObjectType,
+			dash(finding.ParentName), dash(finding.GroupName), dash(finding.LastRunStatus))
+	}
+	return tw.Flush()
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,334 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package drill runs recovery drills: it restores the latest snapshot of selected objects to a sandbox, verifies
+// the result and records the achieved recovery time.
+package drill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetObjectSnapshotsWithContext(ctx context.Context, getObjectSnapshotsOptions *backuprecoveryv1.GetObjectSnapshotsOptions) (result *backuprecoveryv1.GetObjectSnapshotsResponse, response *core.DetailedResponse, err error)
+	CreateRecoveryWithContext(ctx context.Context, createRecoveryOptions *backuprecoveryv1.CreateRecoveryOptions) (result *backuprecoveryv1.Recovery, response *core.DetailedResponse, err error)
+	GetRecoveryByIDWithContext(ctx context.Context, getRecoveryByIdOptions *backuprecoveryv1.GetRecoveryByIdOptions) (result *backuprecoveryv1.Recovery, response *core.DetailedResponse, err error)
+	CancelRecoveryByIDWithContext(ctx context.Context, cancelRecoveryByIdOptions *backuprecoveryv1.CancelRecoveryByIdOptions) (response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Verifier is a pluggable check run after a recovery completed successfully, e.g. a file checksum comparison,
+// a SQL query against the restored database or a readiness probe in the restored namespace.
+type Verifier struct {
+	Name   string
+	Verify func(ctx context.Context, recovery *backuprecoveryv1.Recovery) error
+}
+
+// Target describes one object to drill.
+type Target struct {
+	// Name labels the target in the report.
+	Name string
+
+	// ObjectID is the id of the protected object whose latest snapshot is restored.
+	ObjectID int64
+
+	// SnapshotActions and SnapshotTargetType optionally restrict the snapshots considered restorable, e.g.
+	// RecoverFiles and Local.
+	SnapshotActions    []string
+	SnapshotTargetType string
+
+	// Request is the recovery request template pointing at the sandbox. The runner sets the snapshot id of every
+	// object of the physical, kubernetes and mssql params, adding one object entry when the template has none.
+	Request *backuprecoveryv1.CreateRecoveryOptions
+
+	Verifiers []Verifier
+
+	// Timeout bounds the recovery. The runner's DefaultTimeout applies when zero.
+	Timeout time.Duration
+}
+
+// Runner executes drills against a tenant.
+type Runner struct {
+	client   Client
+	tenantID string
+
+	// PollInterval is the delay between recovery status checks. Defaults to 30 seconds.
+	PollInterval time.Duration
+
+	// DefaultTimeout bounds recoveries of targets without their own timeout. Defaults to 4 hours.
+	DefaultTimeout time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewRunner : Instantiate Runner
+func NewRunner(client Client, tenantID string) *Runner {
+	return &Runner{client: client, tenantID: tenantID, PollInterval: 30 * time.Second, DefaultTimeout: 4 * time.Hour, Now: time.Now}
+}
+
+// Run drills every target in turn and returns the report. Failures of individual targets are recorded in the
+// report; Run itself only fails when the context is cancelled.
+func (runner *Runner) Run(ctx context.Context, targets []Target) (*Report, error) {
+	report := &Report{StartedAt: runner.Now().UTC()}
+	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		report.Outcomes = append(report.Outcomes, runner.drill(ctx, target))
+	}
+	report.FinishedAt = runner.Now().UTC()
+	report.Passed = len(report.Outcomes) > 0
+	for _, outcome := range report.Outcomes {
+		report.Passed = report.Passed && outcome.Passed
+	}
+	return report, nil
+}
+
+// RunEvery runs the drills immediately and then at every interval until the context is cancelled, passing each
+// report to the given function.
+func (runner *Runner) RunEvery(ctx context.Context, interval time.Duration, targets []Target, deliver func(*Report)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := runner.Run(ctx, targets)
+		if err != nil {
+			return err
+		}
+		deliver(report)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (runner *Runner) drill(ctx context.Context, target Target) Outcome {
+	outcome := Outcome{Target: target.Name, ObjectID: target.ObjectID}
+	fail := func(err error) Outcome {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	snapshot, err := runner.latestSnapshot(ctx, target)
+	if err != nil {
+		return fail(err)
+	}
+	outcome.SnapshotID = helpers.Deref(snapshot.ID)
+	if snapshot.SnapshotTimestampUsecs != nil {
+		outcome.SnapshotTime = time.UnixMicro(*snapshot.SnapshotTimestampUsecs).UTC()
+	}
+
+	request, err := runner.request(target, snapshot)
+	if err != nil {
+		return fail(err)
+	}
+	recovery, _, err := runner.client.CreateRecoveryWithContext(ctx, request)
+	if err != nil {
+		return fail(fmt.Errorf("creating recovery: %w", err))
+	}
+	if recovery == nil || recovery.ID == nil {
+		return fail(errors.New("creating recovery: no recovery id returned"))
+	}
+	outcome.RecoveryID = *recovery.ID
+
+	timeout := target.Timeout
+	if timeout == 0 {
+		timeout = runner.DefaultTimeout
+	}
+	recovery, err = runner.wait(ctx, *recovery.ID, timeout)
+	if recovery != nil {
+		outcome.Status = helpers.Deref(recovery.Status)
+		if recovery.StartTimeUsecs != nil {
+			outcome.StartTime = time.UnixMicro(*recovery.StartTimeUsecs).UTC()
+		}
+		if recovery.EndTimeUsecs != nil {
+			outcome.EndTime = time.UnixMicro(*recovery.EndTimeUsecs).UTC()
+		}
+		if !outcome.StartTime.IsZero() && !outcome.EndTime.IsZero() {
+			outcome.RTO = outcome.EndTime.Sub(outcome.StartTime)
+		}
+	}
+	if errors.Is(err, errTimeout) {
+		outcome.Cancelled = true
+		if _, cancelErr := runner.client.CancelRecoveryByIDWithContext(context.WithoutCancel(ctx), &backuprecoveryv1.CancelRecoveryByIdOptions{
+			ID:           core.StringPtr(outcome.RecoveryID),
+			XIBMTenantID: core.StringPtr(runner.tenantID),
+		}); cancelErr != nil {
+			err = fmt.Errorf("%w; cancelling recovery: %v", err, cancelErr)
+		}
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if !succeeded(outcome.Status) {
+		return fail(fmt.Errorf("recovery finished with status '%s'", outcome.Status))
+	}
+
+	outcome.Passed = true
+	for _, verifier := range target.Verifiers {
+		result := VerificationResult{Name: verifier.Name, Passed: true}
+		if err := verifier.Verify(ctx, recovery); err != nil {
+			result.Passed = false
+			result.Error = err.Error()
+			outcome.Passed = false
+		}
+		outcome.Verifications = append(outcome.Verifications, result)
+	}
+	return outcome
+}
+
+// latestSnapshot returns the newest unexpired snapshot of the target object.
+func (runner *Runner) latestSnapshot(ctx context.Context, target Target) (*backuprecoveryv1.ObjectSnapshot, error) {
+	result, _, err := runner.client.GetObjectSnapshotsWithContext(ctx, &backuprecoveryv1.GetObjectSnapshotsOptions{
+		ID:              core.Int64Ptr(target.ObjectID),
+		XIBMTenantID:    core.StringPtr(runner.tenantID),
+		SnapshotActions: target.SnapshotActions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots of object %d: %w", target.ObjectID, err)
+	}
+	now := runner.Now().UnixMicro()
+	var latest *backuprecoveryv1.ObjectSnapshot
+	if result != nil {
+		for i := range result.Snapshots {
+			snapshot := &result.Snapshots[i]
+			if snapshot.ID == nil || snapshot.SnapshotTimestampUsecs == nil {
+				continue
+			}
+			if snapshot.ExpiryTimeUsecs != nil && *snapshot.ExpiryTimeUsecs <= now {
+				continue
+			}
+			if target.SnapshotTargetType != "" && helpers.Deref(snapshot.SnapshotTargetType) != target.SnapshotTargetType {
+				continue
+			}
+			if latest == nil || *snapshot.SnapshotTimestampUsecs > *latest.SnapshotTimestampUsecs {
+				latest = snapshot
+			}
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no restorable snapshot of object %d", target.ObjectID)
+	}
+	return latest, nil
+}
+
+// request copies the target's recovery template and points it at the snapshot.
+func (runner *Runner) request(target Target, snapshot *backuprecoveryv1.ObjectSnapshot) (*backuprecoveryv1.CreateRecoveryOptions, error) {
+	if target.Request == nil {
+		return nil, errors.New("target has no recovery request template")
+	}
+	encoded, err := json.Marshal(target.Request)
+	if err != nil {
+		return nil, fmt.Errorf("copying recovery request: %w", err)
+	}
+	request := &backuprecoveryv1.CreateRecoveryOptions{}
+	if err := json.Unmarshal(encoded, request); err != nil {
+		return nil, fmt.Errorf("copying recovery request: %w", err)
+	}
+
+	request.XIBMTenantID = core.StringPtr(runner.tenantID)
+	if request.Name == nil {
+		request.Name = core.StringPtr(fmt.Sprintf("drill-%s-%s", target.Name, runner.Now().UTC().Format("20060102T150405Z")))
+	}
+	if request.SnapshotEnvironment == nil {
+		request.SnapshotEnvironment = snapshot.Environment
+	}
+
+	objectParams := func(objects []backuprecoveryv1.CommonRecoverObjectSnapshotParams) []backuprecoveryv1.CommonRecoverObjectSnapshotParams {
+		if len(objects) == 0 {
+			objects = []backuprecoveryv1.CommonRecoverObjectSnapshotParams{{}}
+		}
+		for i := range objects {
+			objects[i].SnapshotID = snapshot.ID
+		}
+		return objects
+	}
+	if params := request.PhysicalParams; params != nil {
+		params.Objects = objectParams(params.Objects)
+	}
+	if params := request.KubernetesParams; params != nil {
+		params.Objects = objectParams(params.Objects)
+	}
+	if params := request.MssqlParams; params != nil {
+		if len(params.RecoverAppParams) == 0 {
+			return nil, errors.New("mssql recovery template needs at least one recoverAppParams entry")
+		}
+		for i := range params.RecoverAppParams {
+			params.RecoverAppParams[i].SnapshotID = snapshot.ID
+		}
+	}
+	return request, nil
+}
+
+var errTimeout = errors.New("recovery did not finish before the drill timeout")
+
+// wait polls the recovery until it reaches a terminal status or the timeout expires.
+func (runner *Runner) wait(ctx context.Context, id string, timeout time.Duration) (*backuprecoveryv1.Recovery, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	var last *backuprecoveryv1.Recovery
+	for {
+		recovery, _, err := runner.client.GetRecoveryByIDWithContext(ctx, &backuprecoveryv1.GetRecoveryByIdOptions{
+			ID:           core.StringPtr(id),
+			XIBMTenantID: core.StringPtr(runner.tenantID),
+		})
+		if err != nil {
+			return last, fmt.Errorf("getting recovery '%s': %w", id, err)
+		}
+		if recovery != nil {
+			last = recovery
+			if terminal(helpers.Deref(recovery.Status)) {
+				return recovery, nil
+			}
+		}
+		poll := time.NewTimer(runner.PollInterval)
+		select {
+		case <-ctx.Done():
+			poll.Stop()
+			return last, ctx.Err()
+		case <-deadline.C:
+			poll.Stop()
+			return last, errTimeout
+		case <-poll.C:
+		}
+	}
+}
+
+func terminal(status string) bool {
+	switch status {
+	case backuprecoveryv1.Recovery_Status_Succeeded, backuprecoveryv1.Recovery_Status_Succeededwithwarning,
+		backuprecoveryv1.Recovery_Status_Failed, backuprecoveryv1.Recovery_Status_Canceled,
+		backuprecoveryv1.Recovery_Status_Skipped, backuprecoveryv1.Recovery_Status_Missed:
+		return true
+	}
+	return false
+}
+
+func succeeded(status string) bool {
+	return status == backuprecoveryv1.Recovery_Status_Succeeded || status == backuprecoveryv1.Recovery_Status_Succeededwithwarning
+}
@@ -0,0 +1,196 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drill
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	// statuses are returned by successive GetRecoveryByID calls; the last one repeats.
+	statuses  []string
+	polls     int
+	created   []*backuprecoveryv1.CreateRecoveryOptions
+	cancelled []string
+}
+
+func (fake *fakeClient) GetObjectSnapshotsWithContext(ctx context.Context, options *backuprecoveryv1.GetObjectSnapshotsOptions) (*backuprecoveryv1.GetObjectSnapshotsResponse, *core.DetailedResponse, error) {
+	snapshot := func(id string, at time.Time, expiry time.Time) backuprecoveryv1.ObjectSnapshot {
+		return backuprecoveryv1.ObjectSnapshot{
+			ID:                     core.StringPtr(id),
+			Environment:            core.StringPtr("kPhysical"),
+			SnapshotTimestampUsecs: core.Int64Ptr(at.UnixMicro()),
+			ExpiryTimeUsecs:        core.Int64Ptr(expiry.UnixMicro()),
+		}
+	}
+	return &backuprecoveryv1.GetObjectSnapshotsResponse{Snapshots: []backuprecoveryv1.ObjectSnapshot{
+		snapshot("old", base.Add(-48*time.Hour), base.Add(24*time.Hour)),
+		snapshot("latest", base.Add(-time.Hour), base.Add(24*time.Hour)),
+		snapshot("expired", base.Add(-30*time.Minute), base.Add(-time.Minute)),
+	}}, nil, nil
+}
+
+func (fake *fakeClient) CreateRecoveryWithContext(ctx context.Context, options *backuprecoveryv1.CreateRecoveryOptions) (*backuprecoveryv1.Recovery, *core.DetailedResponse, error) {
+	fake.created = append(fake.created, options)
+	return &backuprecoveryv1.Recovery{ID: core.StringPtr("r-1"), Status: core.StringPtr("Accepted")}, nil, nil
+}
+
+func (fake *fakeClient) GetRecoveryByIDWithContext(ctx context.Context, options *backuprecoveryv1.GetRecoveryByIdOptions) (*backuprecoveryv1.Recovery, *core.DetailedResponse, error) {
+	status := fake.statuses[min(fake.polls, len(fake.statuses)-1)]
+	fake.polls++
+	recovery := &backuprecoveryv1.Recovery{ID: options.ID, Status: core.StringPtr(status), StartTimeUsecs: core.Int64Ptr(base.UnixMicro())}
+	if terminal(status) {
+		recovery.EndTimeUsecs = core.Int64Ptr(base.Add(17 * time.Minute).UnixMicro())
+	}
+	return recovery, nil, nil
+}
+
+func (fake *fakeClient) CancelRecoveryByIDWithContext(ctx context.Context, options *backuprecoveryv1.CancelRecoveryByIdOptions) (*core.DetailedResponse, error) {
+	fake.cancelled = append(fake.cancelled, *options.ID)
+	return nil, nil
+}
+
+func newRunner(client *fakeClient) *Runner {
+	runner := NewRunner(client, "tenant")
+	runner.PollInterval = time.Millisecond
+	runner.Now = func() time.Time { return base }
+	return runner
+}
+
+func physicalTarget(verifiers ...Verifier) Target {
+	return Target{
+		Name:     "web01",
+		ObjectID: 1,
+		Request: &backuprecoveryv1.CreateRecoveryOptions{
+			PhysicalParams: &backuprecoveryv1.RecoverPhysicalParams{
+				RecoveryAction: core.StringPtr("RecoverFiles"),
+			},
+		},
+		Verifiers: verifiers,
+	}
+}
+
+func TestRunSucceeds(t *testing.T) {
+	client := &fakeClient{statuses: []string{"Running", "Running", "Succeeded"}}
+	checked := Verifier{Name: "checksum", Verify: func(ctx context.Context, recovery *backuprecoveryv1.Recovery) error { return nil }}
+	failing := Verifier{Name: "probe", Verify: func(ctx context.Context, recovery *backuprecoveryv1.Recovery) error {
+		return errors.New("connection refused")
+	}}
+
+	report, err := newRunner(client).Run(context.Background(), []Target{physicalTarget(checked), physicalTarget(checked, failing)})
+	require.Nil(t, err)
+	require.Len(t, report.Outcomes, 2)
+
+	require.Len(t, client.created, 2)
+	request := client.created[0]
+	assert.Equal(t, "tenant", *request.XIBMTenantID)
+	assert.Equal(t, "drill-web01-20260302T000000Z", *request.Name)
+	assert.Equal(t, "kPhysical", *request.SnapshotEnvironment)
+	require.Len(t, request.PhysicalParams.Objects, 1)
+	assert.Equal(t, "latest", *request.PhysicalParams.Objects[0].SnapshotID)
+
+	first := report.Outcomes[0]
+	assert.True(t, first.Passed)
+	assert.Equal(t, "latest", first.SnapshotID)
+	assert.Equal(t, "Succeeded", first.Status)
+	assert.Equal(t, 17*time.Minute, first.RTO)
+	assert.Equal(t, []VerificationResult{{Name: "checksum", Passed: true}}, first.Verifications)
+
+	second := report.Outcomes[1]
+	assert.False(t, second.Passed)
+	assert.Equal(t, VerificationResult{Name: "probe", Error: "connection refused"}, second.Verifications[1])
+	assert.False(t, report.Passed)
+	assert.Empty(t, client.cancelled)
+}
+
+func TestRunCancelsOnTimeout(t *testing.T) {
+	client := &fakeClient{statuses: []string{"Running"}}
+	target := physicalTarget()
+	target.Timeout = 20 * time.Millisecond
+
+	report, err := newRunner(client).Run(context.Background(), []Target{target})
+	require.Nil(t, err)
+	outcome := report.Outcomes[0]
+	assert.False(t, outcome.Passed)
+	assert.True(t, outcome.Cancelled)
+	assert.Equal(t, "Running", outcome.Status)
+	assert.Equal(t, []string{"r-1"}, client.cancelled)
+}
+
+func TestRunRecordsFailedRecovery(t *testing.T) {
+	client := &fakeClient{statuses: []string{"Failed"}}
+	verified := false
+	target := physicalTarget(Verifier{Name: "never", Verify: func(ctx context.Context, recovery *backuprecoveryv1.Recovery) error {
+		verified = true
+		return nil
+	}})
+
+	report, err := newRunner(client).Run(context.Background(), []Target{target})
+	require.Nil(t, err)
+	assert.False(t, report.Outcomes[0].Passed)
+	assert.Equal(t, "recovery finished with status 'Failed'", report.Outcomes[0].Error)
+	assert.False(t, verified)
+}
+
+func TestRequestTemplateIsNotModified(t *testing.T) {
+	client := &fakeClient{statuses: []string{"Succeeded"}}
+	target := physicalTarget()
+	target.Request.MssqlParams = &backuprecoveryv1.RecoverSqlParams{
+		RecoverAppParams: []backuprecoveryv1.RecoverSqlAppParams{{TargetEnvironment: core.StringPtr("kSQL")}},
+	}
+
+	_, err := newRunner(client).Run(context.Background(), []Target{target})
+	require.Nil(t, err)
+	assert.Empty(t, target.Request.PhysicalParams.Objects)
+	assert.Nil(t, target.Request.MssqlParams.RecoverAppParams[0].SnapshotID)
+	assert.Equal(t, "latest", *client.created[0].MssqlParams.RecoverAppParams[0].SnapshotID)
+}
+
+func TestReportSignOff(t *testing.T) {
+	report := &Report{
+		StartedAt:  base,
+		FinishedAt: base.Add(time.Hour),
+		Outcomes:   []Outcome{{Target: "web01", ObjectID: 1, Status: "Succeeded", RTO: 17 * time.Minute, Passed: true}},
+		Passed:     true,
+	}
+	key := []byte("secret")
+	assert.False(t, report.VerifySignature(key))
+	assert.NotNil(t, report.SignOff("", base, key))
+
+	require.Nil(t, report.SignOff("jdoe", base.Add(2*time.Hour), key))
+	assert.True(t, report.VerifySignature(key))
+	assert.False(t, report.VerifySignature([]byte("other")))
+
+	var page bytes.Buffer
+	require.Nil(t, report.WriteHTML(&page))
+	assert.Contains(t, page.String(), "<td>17m0s</td>")
+	assert.Contains(t, page.String(), "Signed off by jdoe at 2026-03-02T02:00:00Z")
+
+	report.Outcomes[0].RTO = time.Minute
+	assert.False(t, report.VerifySignature(key))
+}
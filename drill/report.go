@@ -0,0 +1,177 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drill
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"time"
+)
+
+// VerificationResult is the outcome of one verifier.
+type VerificationResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Outcome is the result of drilling one target.
+type Outcome struct {
+	Target       string    `json:"target"`
+	ObjectID     int64     `json:"objectId"`
+	SnapshotID   string    `json:"snapshotId,omitempty"`
+	SnapshotTime time.Time `json:"snapshotTime,omitempty"`
+	RecoveryID   string    `json:"recoveryId,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	StartTime    time.Time `json:"startTime,omitempty"`
+	EndTime      time.Time `json:"endTime,omitempty"`
+
+	// RTO is the achieved recovery time, from the start to the end of the recovery task.
+	RTO time.Duration `json:"rto"`
+
+	// Cancelled is set when the recovery was cancelled because it exceeded the drill timeout.
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	Verifications []VerificationResult `json:"verifications,omitempty"`
+	Passed        bool                 `json:"passed"`
+	Error         string               `json:"error,omitempty"`
+}
+
+// Report is the result of one drill run. Once signed off it carries the name of the approver and an HMAC-SHA256
+// signature over its content.
+type Report struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Outcomes   []Outcome `json:"outcomes"`
+	Passed     bool      `json:"passed"`
+
+	SignedOffBy string    `json:"signedOffBy,omitempty"`
+	SignedOffAt time.Time `json:"signedOffAt,omitempty"`
+	Signature   string    `json:"signature,omitempty"`
+}
+
+// SignOff records the approver and signs the report with the given key.
+func (report *Report) SignOff(approver string, at time.Time, key []byte) error {
+	if approver == "" {
+		return errors.New("approver must be set")
+	}
+	if len(key) == 0 {
+		return errors.New("signing key must be set")
+	}
+	report.SignedOffBy = approver
+	report.SignedOffAt = at.UTC()
+	signature, err := report.sign(key)
+	if err != nil {
+		return err
+	}
+	report.Signature = signature
+	return nil
+}
+
+// VerifySignature reports whether the report is signed off and unchanged since it was signed with the given key.
+func (report *Report) VerifySignature(key []byte) bool {
+	if report.Signature == "" {
+		return false
+	}
+	expected, err := report.sign(key)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(report.Signature))
+}
+
+func (report *Report) sign(key []byte) (string, error) {
+	unsigned := *report
+	unsigned.Signature = ""
+	content, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"timestamp": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.UTC().Format(time.RFC3339)
+	},
+	"verdict": func(passed bool) string {
+		if passed {
+			return "PASSED"
+		}
+		return "FAILED"
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Recovery drill report {{timestamp .StartedAt}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 4px 8px; text-align: left; vertical-align: top; }
+.PASSED { color: #1a7f37; }
+.FAILED { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Recovery drill report</h1>
+<p>Started {{timestamp .StartedAt}}, finished {{timestamp .FinishedAt}}: <strong class="{{verdict .Passed}}">{{verdict .Passed}}</strong></p>
+<table>
+<tr><th>Target</th><th>Object</th><th>Snapshot</th><th>Recovery</th><th>Status</th><th>RTO</th><th>Verifications</th><th>Result</th></tr>
+{{- range .Outcomes}}
+<tr>
+<td>{{.Target}}</td>
+<td>{{.ObjectID}}</td>
+<td>{{timestamp .SnapshotTime}}</td>
+<td>{{.RecoveryID}}</td>
+<td>{{.Status}}{{if .Cancelled}} (cancelled after timeout){{end}}</td>
+<td>{{.RTO}}</td>
+<td>{{range .Verifications}}<div class="{{verdict .Passed}}">{{.Name}}: {{verdict .Passed}}{{with .Error}} ({{.}}){{end}}</div>{{end}}</td>
+<td class="{{verdict .Passed}}">{{verdict .Passed}}{{with .Error}}<br>{{.}}{{end}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .SignedOffBy}}
+<p>Signed off by {{.SignedOffBy}} at {{timestamp .SignedOffAt}}.<br>Signature: <code>{{.Signature}}</code></p>
+{{- else}}
+<p>Not signed off.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a standalone HTML page.
+func (report *Report) WriteHTML(w io.Writer) error {
+	return htmlReport.Execute(w, report)
+}
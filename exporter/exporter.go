@@ -0,0 +1,427 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package exporter collects protection health from the Backup and Recovery APIs and serves it in the OpenMetrics
+// text format for Prometheus and compatible scrapers.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetUpgradeTasksWithContext(ctx context.Context, getUpgradeTasksOptions *backuprecoveryv1.GetUpgradeTasksOptions) (result *backuprecoveryv1.AgentUpgradeTaskStates, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// SreClient is the subset of the management SRE API used by this package.
+type SreClient interface {
+	GetManagementAlertsStatsWithContext(ctx context.Context, getManagementAlertsStatsOptions *backuprecoveryv1.GetManagementAlertsStatsOptions) (result *backuprecoveryv1.McmActiveAlertsStats, response *core.DetailedResponse, err error)
+	GetClustersInfoWithContext(ctx context.Context, getClustersInfoOptions *backuprecoveryv1.GetClustersInfoOptions) (result *backuprecoveryv1.ClusterDetails, response *core.DetailedResponse, err error)
+}
+
+var _ SreClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// Collector names used in the brs_exporter_collector_success metric.
+const (
+	CollectorProtectionGroups = "protection_groups"
+	CollectorAgentUpgrades    = "agent_upgrades"
+	CollectorClusters         = "clusters"
+	CollectorAlerts           = "alerts"
+)
+
+// Exporter periodically collects metrics and serves the most recent collection on /metrics.
+//
+// Every protection group sample carries tenant, cluster, environment and group labels. The number of groups with
+// their own series is capped by MaxGroups so that a tenant with many groups cannot blow up the cardinality of the
+// scraping Prometheus; the aggregated brs_protection_groups counts always cover every group.
+type Exporter struct {
+	client   Client
+	sre      SreClient
+	tenantID string
+
+	// Interval is the delay between collections in Run. Defaults to one minute.
+	Interval time.Duration
+
+	// AlertLookback is the time range of the active alert statistics. Defaults to 24 hours.
+	AlertLookback time.Duration
+
+	// MaxGroups is the maximum number of protection groups exported with per-group series. Defaults to 1000.
+	MaxGroups int
+
+	// OnError receives collection errors in Run, which keeps collecting. Errors are dropped when nil.
+	OnError func(error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mutex    sync.RWMutex
+	families []*Family
+}
+
+// NewExporter : Instantiate Exporter. The SRE client may be nil, in which case cluster and alert metrics are not
+// collected.
+func NewExporter(client Client, sre SreClient, tenantID string) *Exporter {
+	return &Exporter{
+		client:        client,
+		sre:           sre,
+		tenantID:      tenantID,
+		Interval:      time.Minute,
+		AlertLookback: 24 * time.Hour,
+		MaxGroups:     1000,
+		Now:           time.Now,
+	}
+}
+
+// collection accumulates the families of one collection.
+type collection struct {
+	families map[string]*Family
+	order    []string
+}
+
+func (c *collection) family(name string, help string, metricType Type, unit string) *Family {
+	if family, ok := c.families[name]; ok {
+		return family
+	}
+	family := &Family{Name: name, Help: help, Type: metricType, Unit: unit}
+	c.families[name] = family
+	c.order = append(c.order, name)
+	return family
+}
+
+func (c *collection) list() []*Family {
+	families := make([]*Family, 0, len(c.order))
+	for _, name := range c.order {
+		families = append(families, c.families[name])
+	}
+	return families
+}
+
+// Collect queries the APIs once and returns the metric families. A failing collector does not prevent the others
+// from running; its failure is reported by brs_exporter_collector_success and in the returned error.
+func (exporter *Exporter) Collect(ctx context.Context) ([]*Family, error) {
+	started := exporter.Now()
+	c := &collection{families: map[string]*Family{}}
+	success := &Family{Name: "brs_exporter_collector_success", Help: "Whether the last collection of the collector succeeded.", Type: Gauge}
+
+	clusterNames := map[string]string{}
+	var errs []error
+	run := func(name string, collect func() error) {
+		value := 1.0
+		if err := collect(); err != nil {
+			value = 0
+			errs = append(errs, fmt.Errorf("collecting %s: %w", name, err))
+		}
+		success.Add(value, Labels{"tenant": exporter.tenantID, "collector": name})
+	}
+	if exporter.sre != nil {
+		// Clusters come first so that the other collectors can label samples with cluster names.
+		run(CollectorClusters, func() error { return exporter.collectClusters(ctx, c, clusterNames) })
+		run(CollectorAlerts, func() error { return exporter.collectAlerts(ctx, c, clusterNames, started) })
+	}
+	run(CollectorProtectionGroups, func() error { return exporter.collectGroups(ctx, c, clusterNames, started) })
+	run(CollectorAgentUpgrades, func() error { return exporter.collectAgentUpgrades(ctx, c) })
+
+	families := c.list()
+	families = append(families, success, &Family{
+		Name:    "brs_exporter_collection_duration_seconds",
+		Help:    "Duration of the last collection.",
+		Type:    Gauge,
+		Unit:    "seconds",
+		Samples: []Sample{{Labels: Labels{"tenant": exporter.tenantID}, Value: exporter.Now().Sub(started).Seconds()}},
+	}, &Family{
+		Name:    "brs_exporter_last_collection_timestamp_seconds",
+		Help:    "Time of the last collection.",
+		Type:    Gauge,
+		Unit:    "seconds",
+		Samples: []Sample{{Labels: Labels{"tenant": exporter.tenantID}, Value: seconds(started)}},
+	})
+	return families, errors.Join(errs...)
+}
+
+func (exporter *Exporter) collectClusters(ctx context.Context, c *collection, clusterNames map[string]string) error {
+	result, _, err := exporter.sre.GetClustersInfoWithContext(ctx, &backuprecoveryv1.GetClustersInfoOptions{})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	info := c.family("brs_cluster", "Cluster version and health.", Info, "")
+	nodes := c.family("brs_cluster_nodes", "Number of nodes of the cluster.", Gauge, "")
+	connected := c.family("brs_cluster_connected", "Whether the cluster is connected to the management service.", Gauge, "")
+	for _, cluster := range result.CohesityClusters {
+		id := strconv.FormatInt(helpers.Deref(cluster.ClusterID), 10)
+		name := helpers.Deref(cluster.ClusterName)
+		if name == "" {
+			name = id
+		}
+		clusterNames[id] = name
+		labels := Labels{"tenant": exporter.tenantID, "cluster": name}
+		info.Add(1, Labels{
+			"tenant":  exporter.tenantID,
+			"cluster": name,
+			"version": helpers.Deref(cluster.CurrentVersion),
+			"health":  helpers.Deref(cluster.Health),
+			"status":  helpers.Deref(cluster.Status),
+		})
+		nodes.Add(float64(helpers.Deref(cluster.NumberOfNodes)), labels)
+		connected.Add(boolValue(cluster.IsConnectedToHelios), labels)
+	}
+	return nil
+}
+
+func (exporter *Exporter) collectAlerts(ctx context.Context, c *collection, clusterNames map[string]string, now time.Time) error {
+	result, _, err := exporter.sre.GetManagementAlertsStatsWithContext(ctx, &backuprecoveryv1.GetManagementAlertsStatsOptions{
+		StartTimeUsecs: core.Int64Ptr(now.Add(-exporter.AlertLookback).UnixMicro()),
+		EndTimeUsecs:   core.Int64Ptr(now.UnixMicro()),
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	bySeverity := c.family("brs_active_alerts", "Number of active alerts by severity.", Gauge, "")
+	byCategory := c.family("brs_active_alerts_by_category", "Number of active alerts by category.", Gauge, "")
+	for _, cluster := range result.StatsByCluster {
+		if cluster.AlertsStats == nil {
+			continue
+		}
+		id := strconv.FormatInt(helpers.Deref(cluster.ClusterID), 10)
+		label := clusterLabel(clusterNames, id)
+		stats := cluster.AlertsStats
+		for severity, value := range map[string]*int64{
+			"critical": stats.NumCriticalAlerts,
+			"warning":  warningAlerts(stats),
+			"info":     stats.NumInfoAlerts,
+		} {
+			bySeverity.Add(float64(helpers.Deref(value)), Labels{"tenant": exporter.tenantID, "cluster": label, "severity": severity})
+		}
+		for category, value := range map[string]*int64{
+			"data_service": stats.NumDataServiceAlerts,
+			"hardware":     stats.NumHardwareAlerts,
+			"maintenance":  stats.NumMaintenanceAlerts,
+			"software":     stats.NumSoftwareAlerts,
+		} {
+			byCategory.Add(float64(helpers.Deref(value)), Labels{"tenant": exporter.tenantID, "cluster": label, "category": category})
+		}
+	}
+	sortSamples(bySeverity)
+	sortSamples(byCategory)
+	return nil
+}
+
+// warningAlerts sums the warning alerts of all categories, since the statistics have no overall warning count.
+func warningAlerts(stats *backuprecoveryv1.ActiveAlertsStats) *int64 {
+	total := helpers.Deref(stats.NumDataServiceWarningAlerts) + helpers.Deref(stats.NumHardwareWarningAlerts) +
+		helpers.Deref(stats.NumMaintenanceWarningAlerts) + helpers.Deref(stats.NumSoftwareWarningAlerts)
+	return &total
+}
+
+func (exporter *Exporter) collectGroups(ctx context.Context, c *collection, clusterNames map[string]string, now time.Time) error {
+	result, _, err := exporter.client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+		XIBMTenantID:       core.StringPtr(exporter.tenantID),
+		IsDeleted:          core.BoolPtr(false),
+		IncludeLastRunInfo: core.BoolPtr(true),
+	})
+	if err != nil {
+		return err
+	}
+	var groups []backuprecoveryv1.ProtectionGroupResponse
+	if result != nil {
+		groups = result.ProtectionGroups
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return helpers.Deref(groups[i].Name) < helpers.Deref(groups[j].Name) })
+
+	type countKey struct{ cluster, environment, status string }
+	counts := map[countKey]int{}
+	success := c.family("brs_protection_group_last_run_success", "Whether the last local backup run of the group succeeded.", Gauge, "")
+	duration := c.family("brs_protection_group_last_run_duration_seconds", "Duration of the last finished local backup run.", Gauge, "seconds")
+	end := c.family("brs_protection_group_last_run_end_timestamp_seconds", "End time of the last finished local backup run.", Gauge, "seconds")
+	logical := c.family("brs_protection_group_last_run_logical_bytes", "Logical size of the last local backup run.", Gauge, "bytes")
+	read := c.family("brs_protection_group_last_run_read_bytes", "Bytes read by the last local backup run.", Gauge, "bytes")
+	written := c.family("brs_protection_group_last_run_written_bytes", "Bytes written by the last local backup run.", Gauge, "bytes")
+	violated := c.family("brs_protection_group_last_run_sla_violated", "Whether the last local backup run violated its SLA.", Gauge, "")
+	objects := c.family("brs_protection_group_protected_objects", "Number of objects protected by the group.", Gauge, "")
+	paused := c.family("brs_protection_group_paused", "Whether the group is paused.", Gauge, "")
+
+	exported := 0
+	for _, group := range groups {
+		cluster := clusterLabel(clusterNames, helpers.Deref(group.ClusterID))
+		environment := helpers.Deref(group.Environment)
+		var summary *backuprecoveryv1.BackupRunSummary
+		if group.LastRun != nil {
+			summary = group.LastRun.LocalBackupInfo
+		}
+		status := "None"
+		if summary != nil && summary.Status != nil {
+			status = *summary.Status
+		}
+		counts[countKey{cluster, environment, status}]++
+
+		if exported >= exporter.MaxGroups {
+			continue
+		}
+		exported++
+		labels := Labels{"tenant": exporter.tenantID, "cluster": cluster, "environment": environment, "group": helpers.Deref(group.Name)}
+		objects.Add(float64(helpers.Deref(group.NumProtectedObjects)), labels)
+		paused.Add(boolValue(group.IsPaused), labels)
+		if summary == nil {
+			continue
+		}
+		success.Add(boolValue(core.BoolPtr(status == backuprecoveryv1.BackupRunSummary_Status_Succeeded ||
+			status == backuprecoveryv1.BackupRunSummary_Status_Succeededwithwarning)), labels)
+		violated.Add(boolValue(summary.IsSlaViolated), labels)
+		if summary.EndTimeUsecs != nil && summary.StartTimeUsecs != nil {
+			duration.Add(float64(*summary.EndTimeUsecs-*summary.StartTimeUsecs)/1e6, labels)
+			end.Add(float64(*summary.EndTimeUsecs)/1e6, labels)
+		}
+		if stats := summary.LocalSnapshotStats; stats != nil {
+			logical.Add(float64(helpers.Deref(stats.LogicalSizeBytes)), labels)
+			read.Add(float64(helpers.Deref(stats.BytesRead)), labels)
+			written.Add(float64(helpers.Deref(stats.BytesWritten)), labels)
+		}
+	}
+
+	total := c.family("brs_protection_groups", "Number of protection groups by the status of their last local backup run.", Gauge, "")
+	for key, count := range counts {
+		total.Add(float64(count), Labels{"tenant": exporter.tenantID, "cluster": key.cluster, "environment": key.environment, "status": key.status})
+	}
+	sortSamples(total)
+	c.family("brs_exporter_dropped_groups", "Protection groups without per-group series because of the MaxGroups limit.", Gauge, "").
+		Add(float64(len(groups)-exported), Labels{"tenant": exporter.tenantID})
+	return nil
+}
+
+func (exporter *Exporter) collectAgentUpgrades(ctx context.Context, c *collection) error {
+	result, _, err := exporter.client.GetUpgradeTasksWithContext(ctx, &backuprecoveryv1.GetUpgradeTasksOptions{
+		XIBMTenantID: core.StringPtr(exporter.tenantID),
+	})
+	if err != nil {
+		return err
+	}
+	tasks := map[string]int{}
+	agents := map[string]int{}
+	if result != nil {
+		for _, task := range result.Tasks {
+			status := helpers.Deref(task.Status)
+			tasks[status]++
+			agents[status] += max(len(task.Agents), len(task.AgentIDs))
+		}
+	}
+	taskFamily := c.family("brs_agent_upgrade_tasks", "Number of agent upgrade tasks by status.", Gauge, "")
+	agentFamily := c.family("brs_agent_upgrade_task_agents", "Number of agents in upgrade tasks by task status.", Gauge, "")
+	for status, count := range tasks {
+		taskFamily.Add(float64(count), Labels{"tenant": exporter.tenantID, "status": status})
+		agentFamily.Add(float64(agents[status]), Labels{"tenant": exporter.tenantID, "status": status})
+	}
+	sortSamples(taskFamily)
+	sortSamples(agentFamily)
+	return nil
+}
+
+// Refresh collects the metrics and makes them the ones served. The families of a partially failed collection are
+// still served.
+func (exporter *Exporter) Refresh(ctx context.Context) error {
+	families, err := exporter.Collect(ctx)
+	exporter.mutex.Lock()
+	exporter.families = families
+	exporter.mutex.Unlock()
+	return err
+}
+
+// Run refreshes the metrics every Interval until the context is cancelled.
+func (exporter *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(exporter.Interval)
+	defer ticker.Stop()
+	for {
+		if err := exporter.Refresh(ctx); err != nil && exporter.OnError != nil {
+			exporter.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP serves the metrics of the last collection. Scrapes never trigger API calls, so the scrape interval does
+// not affect the load on the service.
+func (exporter *Exporter) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	exporter.mutex.RLock()
+	families := exporter.families
+	exporter.mutex.RUnlock()
+	if families == nil {
+		http.Error(writer, "no collection has completed yet", http.StatusServiceUnavailable)
+		return
+	}
+	writer.Header().Set("Content-Type", ContentType)
+	WriteOpenMetrics(writer, families)
+}
+
+func clusterLabel(clusterNames map[string]string, id string) string {
+	if name, ok := clusterNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// sortSamples orders samples whose labels were produced by iterating a map.
+func sortSamples(family *Family) {
+	sort.Slice(family.Samples, func(i, j int) bool {
+		return labelKey(family.Samples[i].Labels) < labelKey(family.Samples[j].Labels)
+	})
+}
+
+func labelKey(labels Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	key := ""
+	for _, name := range names {
+		key += name + "\x00" + labels[name] + "\x00"
+	}
+	return key
+}
+
+func seconds(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+func boolValue(value *bool) float64 {
+	if value != nil && *value {
+		return 1
+	}
+	return 0
+}
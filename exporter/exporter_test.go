@@ -0,0 +1,154 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	groups   []backuprecoveryv1.ProtectionGroupResponse
+	tasksErr error
+}
+
+func (fake *fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: fake.groups}, nil, nil
+}
+
+func (fake *fakeClient) GetUpgradeTasksWithContext(ctx context.Context, options *backuprecoveryv1.GetUpgradeTasksOptions) (*backuprecoveryv1.AgentUpgradeTaskStates, *core.DetailedResponse, error) {
+	if fake.tasksErr != nil {
+		return nil, nil, fake.tasksErr
+	}
+	return &backuprecoveryv1.AgentUpgradeTaskStates{Tasks: []backuprecoveryv1.AgentUpgradeTaskState{
+		{Status: core.StringPtr("Running"), AgentIDs: []int64{1, 2}},
+		{Status: core.StringPtr("Failed"), AgentIDs: []int64{3}},
+	}}, nil, nil
+}
+
+type fakeSreClient struct{}
+
+func (fakeSreClient) GetManagementAlertsStatsWithContext(ctx context.Context, options *backuprecoveryv1.GetManagementAlertsStatsOptions) (*backuprecoveryv1.McmActiveAlertsStats, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.McmActiveAlertsStats{StatsByCluster: []backuprecoveryv1.McmActiveAlertsStatsByCluster{{
+		ClusterID: core.Int64Ptr(7),
+		AlertsStats: &backuprecoveryv1.ActiveAlertsStats{
+			NumCriticalAlerts:        core.Int64Ptr(2),
+			NumHardwareAlerts:        core.Int64Ptr(3),
+			NumHardwareWarningAlerts: core.Int64Ptr(1),
+		},
+	}}}, nil, nil
+}
+
+func (fakeSreClient) GetClustersInfoWithContext(ctx context.Context, options *backuprecoveryv1.GetClustersInfoOptions) (*backuprecoveryv1.ClusterDetails, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ClusterDetails{CohesityClusters: []backuprecoveryv1.ClusterInfo{{
+		ClusterID:      core.Int64Ptr(7),
+		ClusterName:    core.StringPtr("prod"),
+		CurrentVersion: core.StringPtr("7.2"),
+		NumberOfNodes:  core.Int64Ptr(4),
+	}}}, nil, nil
+}
+
+func group(name string, status string) backuprecoveryv1.ProtectionGroupResponse {
+	return backuprecoveryv1.ProtectionGroupResponse{
+		Name:        core.StringPtr(name),
+		ClusterID:   core.StringPtr("7"),
+		Environment: core.StringPtr("kPhysical"),
+		LastRun: &backuprecoveryv1.ProtectionGroupRun{LocalBackupInfo: &backuprecoveryv1.BackupRunSummary{
+			Status:             core.StringPtr(status),
+			StartTimeUsecs:     core.Int64Ptr(base.UnixMicro()),
+			EndTimeUsecs:       core.Int64Ptr(base.Add(90 * time.Second).UnixMicro()),
+			LocalSnapshotStats: &backuprecoveryv1.BackupDataStats{LogicalSizeBytes: core.Int64Ptr(1024)},
+		}},
+	}
+}
+
+func TestExporterServesOpenMetrics(t *testing.T) {
+	client := &fakeClient{groups: []backuprecoveryv1.ProtectionGroupResponse{
+		group("files", "Succeeded"),
+		group("db", "Failed"),
+		group("vm", "Failed"),
+	}}
+	exporter := NewExporter(client, fakeSreClient{}, "tenant/")
+	exporter.Now = func() time.Time { return base }
+	exporter.MaxGroups = 2
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	require.Nil(t, exporter.Refresh(context.Background()))
+	recorder = httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+
+	for _, line := range []string{
+		`# TYPE brs_protection_group_last_run_duration_seconds gauge`,
+		`# UNIT brs_protection_group_last_run_duration_seconds seconds`,
+		`brs_protection_group_last_run_duration_seconds{cluster="prod",environment="kPhysical",group="db",tenant="tenant/"} 90`,
+		`brs_protection_group_last_run_success{cluster="prod",environment="kPhysical",group="files",tenant="tenant/"} 1`,
+		`brs_protection_group_last_run_logical_bytes{cluster="prod",environment="kPhysical",group="db",tenant="tenant/"} 1024`,
+		`brs_protection_groups{cluster="prod",environment="kPhysical",status="Failed",tenant="tenant/"} 2`,
+		`brs_exporter_dropped_groups{tenant="tenant/"} 1`,
+		`brs_cluster_info{cluster="prod",health="",status="",tenant="tenant/",version="7.2"} 1`,
+		`brs_active_alerts{cluster="prod",severity="critical",tenant="tenant/"} 2`,
+		`brs_active_alerts{cluster="prod",severity="warning",tenant="tenant/"} 1`,
+		`brs_active_alerts_by_category{category="hardware",cluster="prod",tenant="tenant/"} 3`,
+		`brs_agent_upgrade_task_agents{status="Running",tenant="tenant/"} 2`,
+		`brs_exporter_collector_success{collector="agent_upgrades",tenant="tenant/"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	// Groups beyond MaxGroups, in name order, get no series of their own.
+	assert.NotContains(t, body, `group="vm"`)
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+}
+
+func TestCollectReportsFailedCollectors(t *testing.T) {
+	client := &fakeClient{tasksErr: errors.New("unavailable")}
+	exporter := NewExporter(client, nil, "tenant")
+
+	families, err := exporter.Collect(context.Background())
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "collecting agent_upgrades")
+
+	var body strings.Builder
+	require.Nil(t, WriteOpenMetrics(&body, families))
+	assert.Contains(t, body.String(), `brs_exporter_collector_success{collector="agent_upgrades",tenant="tenant"} 0`)
+	assert.Contains(t, body.String(), `brs_exporter_collector_success{collector="protection_groups",tenant="tenant"} 1`)
+	assert.NotContains(t, body.String(), `collector="alerts"`)
+}
+
+func TestWriteOpenMetricsEscapes(t *testing.T) {
+	var body strings.Builder
+	family := &Family{Name: "test", Help: "a \"quoted\"\nhelp", Type: Gauge}
+	family.Add(0.5, Labels{"name": "a\\b\"c\nd"})
+	require.Nil(t, WriteOpenMetrics(&body, []*Family{family}))
+	assert.Equal(t, "# TYPE test gauge\n# HELP test a \\\"quoted\\\"\\nhelp\ntest{name=\"a\\\\b\\\"c\\nd\"} 0.5\n# EOF\n", body.String())
+}
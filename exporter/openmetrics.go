@@ -0,0 +1,128 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the OpenMetrics text exposition format.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Type is the OpenMetrics type of a metric family.
+type Type string
+
+const (
+	// Gauge is a value that can go up and down.
+	Gauge Type = "gauge"
+	// Info is a set of labels with the constant value 1.
+	Info Type = "info"
+)
+
+// Labels are the label names and values of one sample.
+type Labels map[string]string
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// Family is a named group of samples of the same type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Unit    string
+	Samples []Sample
+}
+
+// Add appends a sample to the family.
+func (family *Family) Add(value float64, labels Labels) {
+	family.Samples = append(family.Samples, Sample{Labels: labels, Value: value})
+}
+
+// WriteOpenMetrics writes the families in the OpenMetrics text format, terminated by the mandatory EOF marker.
+// Families and label names are written in a stable order.
+func WriteOpenMetrics(w io.Writer, families []*Family) error {
+	buffered := bufio.NewWriter(w)
+	for _, family := range families {
+		fmt.Fprintf(buffered, "# TYPE %s %s\n", family.Name, family.Type)
+		if family.Unit != "" {
+			fmt.Fprintf(buffered, "# UNIT %s %s\n", family.Name, family.Unit)
+		}
+		if family.Help != "" {
+			fmt.Fprintf(buffered, "# HELP %s %s\n", family.Name, escape(family.Help))
+		}
+		name := family.Name
+		if family.Type == Info {
+			name += "_info"
+		}
+		for _, sample := range family.Samples {
+			buffered.WriteString(name)
+			writeLabels(buffered, sample.Labels)
+			buffered.WriteByte(' ')
+			buffered.WriteString(formatValue(sample.Value))
+			buffered.WriteByte('\n')
+		}
+	}
+	buffered.WriteString("# EOF\n")
+	return buffered.Flush()
+}
+
+func writeLabels(w *bufio.Writer, labels Labels) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		fmt.Fprintf(w, "%s=\"%s\"", name, escape(labels[name]))
+	}
+	w.WriteByte('}')
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
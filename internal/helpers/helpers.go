@@ -0,0 +1,105 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package helpers holds small utilities shared by the packages of this module.
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Deref returns the value a pointer of a response model points to, or the zero value when it is nil.
+func Deref[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
+// WriteFile replaces the file at path atomically. The content is written by write to a hidden temporary file in the
+// same directory, which is synced and renamed into place, so readers never see a partial file. The temporary file
+// is removed when write fails.
+func WriteFile(path string, write func(io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// SaveJSON replaces the file at path atomically with value encoded as indented JSON.
+func SaveJSON(path string, value any) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+}
+
+// LoadJSON decodes the file at path into value. It reports false when the file does not exist.
+func LoadJSON(path string, value any) (bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		return false, fmt.Errorf("decoding '%s': %w", path, err)
+	}
+	return true, nil
+}
+
+// JSONFile keeps a value in a JSON file that is replaced atomically on every save.
+type JSONFile[T any] struct {
+	Path string
+}
+
+// Load returns the saved value, or nil when none was saved yet.
+func (file JSONFile[T]) Load() (*T, error) {
+	value := new(T)
+	if found, err := LoadJSON(file.Path, value); !found || err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Save writes the value.
+func (file JSONFile[T]) Save(value *T) error {
+	return SaveJSON(file.Path, value)
+}
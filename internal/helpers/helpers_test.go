@@ -0,0 +1,70 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helpers
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeref(t *testing.T) {
+	name, count, enabled := "prod", int64(3), true
+	assert.Equal(t, "prod", Deref(&name))
+	assert.Equal(t, int64(3), Deref(&count))
+	assert.True(t, Deref(&enabled))
+	assert.Equal(t, "", Deref[string](nil))
+	assert.Equal(t, int64(0), Deref[int64](nil))
+}
+
+func TestJSONFile(t *testing.T) {
+	type state struct {
+		Waves int `json:"waves"`
+	}
+	dir := t.TempDir()
+	file := JSONFile[state]{Path: filepath.Join(dir, "state.json")}
+	loaded, err := file.Load()
+	require.Nil(t, err)
+	assert.Nil(t, loaded)
+
+	require.Nil(t, file.Save(&state{Waves: 2}))
+	loaded, err = file.Load()
+	require.Nil(t, err)
+	assert.Equal(t, &state{Waves: 2}, loaded)
+
+	// A failed write leaves the previous file and no temporary file behind.
+	err = WriteFile(file.Path, func(w io.Writer) error {
+		w.Write([]byte("{"))
+		return errors.New("interrupted")
+	})
+	assert.ErrorContains(t, err, "interrupted")
+	loaded, err = file.Load()
+	require.Nil(t, err)
+	assert.Equal(t, &state{Waves: 2}, loaded)
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	require.Nil(t, os.WriteFile(file.Path, []byte("{"), 0o644))
+	_, err = file.Load()
+	assert.ErrorContains(t, err, "decoding")
+}
@@ -0,0 +1,90 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package legalhold
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcome is the result recorded in an audit entry.
+type Outcome string
+
+const (
+	// OutcomeRequested is recorded before a change is sent to the cluster.
+	OutcomeRequested Outcome = "requested"
+	// OutcomeFailed is recorded when the cluster rejected the change.
+	OutcomeFailed Outcome = "failed"
+	// OutcomeVerified is recorded when the run reports the requested legal hold state.
+	OutcomeVerified Outcome = "verified"
+	// OutcomeMismatch is recorded when the run does not report the requested legal hold state after the change.
+	OutcomeMismatch Outcome = "mismatch"
+)
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Action  Action    `json:"action"`
+	Reason  string    `json:"reason"`
+	GroupID string    `json:"groupId"`
+	RunID   string    `json:"runId"`
+	Copies  []string  `json:"copies,omitempty"`
+	Outcome Outcome   `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// AuditLog writes entries as JSON lines. Entries are only ever appended.
+type AuditLog struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewAuditLog returns an audit log writing to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{encoder: json.NewEncoder(w)}
+}
+
+// OpenAuditLog opens, or creates, the audit log file at path in append-only mode.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	log := NewAuditLog(file)
+	log.closer = file
+	return log, nil
+}
+
+// Record appends one entry.
+func (log *AuditLog) Record(entry Entry) error {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	return log.encoder.Encode(entry)
+}
+
+// Close closes the underlying file of a log opened with OpenAuditLog.
+func (log *AuditLog) Close() error {
+	if log.closer == nil {
+		return nil
+	}
+	return log.closer.Close()
+}
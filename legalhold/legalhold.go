@@ -0,0 +1,498 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package legalhold places the snapshots of protection group runs on legal hold, and releases them, in bulk while
+// keeping an audit trail of every change.
+package legalhold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/retention"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+	GetObjectSnapshotsWithContext(ctx context.Context, getObjectSnapshotsOptions *backuprecoveryv1.GetObjectSnapshotsOptions) (result *backuprecoveryv1.GetObjectSnapshotsResponse, response *core.DetailedResponse, err error)
+	UpdateProtectionGroupRunWithContext(ctx context.Context, updateProtectionGroupRunOptions *backuprecoveryv1.UpdateProtectionGroupRunOptions) (result *backuprecoveryv1.UpdateProtectionGroupRunResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Action is the legal hold change applied to runs.
+type Action string
+
+const (
+	// ActionHold places runs on legal hold.
+	ActionHold Action = "hold"
+	// ActionRelease releases runs from legal hold.
+	ActionRelease Action = "release"
+)
+
+// Selector chooses the runs to act on: every run of the given groups and every run holding a snapshot of the given
+// objects that started within [From, To]. Zero times leave the range open.
+type Selector struct {
+	GroupIDs  []string
+	ObjectIDs []int64
+	From      time.Time
+	To        time.Time
+}
+
+// Copy is a replication or archival copy of a run. The local snapshot is always included and not listed.
+type Copy struct {
+	Kind               retention.CopyKind `json:"kind"`
+	ID                 int64              `json:"id"`
+	Name               string             `json:"name,omitempty"`
+	ArchivalTargetType string             `json:"archivalTargetType,omitempty"`
+}
+
+// Run is a protection group run selected for a legal hold change.
+type Run struct {
+	GroupID     string    `json:"groupId"`
+	GroupName   string    `json:"groupName,omitempty"`
+	RunID       string    `json:"runId"`
+	StartTime   time.Time `json:"startTime"`
+	OnLegalHold bool      `json:"onLegalHold"`
+	Copies      []Copy    `json:"copies,omitempty"`
+}
+
+// RunResult is the outcome of a legal hold change on one run. Unchanged runs were already in the requested state.
+type RunResult struct {
+	Run       Run     `json:"run"`
+	Outcome   Outcome `json:"outcome"`
+	Unchanged bool    `json:"unchanged,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Result is the outcome of a bulk legal hold change.
+type Result struct {
+	Action Action      `json:"action"`
+	Runs   []RunResult `json:"runs"`
+}
+
+// Failed returns the runs that were not verified in the requested state.
+func (result *Result) Failed() []RunResult {
+	var failed []RunResult
+	for _, run := range result.Runs {
+		if run.Outcome != OutcomeVerified {
+			failed = append(failed, run)
+		}
+	}
+	return failed
+}
+
+// Manager applies legal hold changes in batches.
+type Manager struct {
+	client   Client
+	tenantID string
+	audit    *AuditLog
+
+	// Actor identifies who requested the changes in the audit log.
+	Actor string
+
+	// BatchSize is the number of runs of one group updated per request. Defaults to 25.
+	BatchSize int
+
+	// Concurrency is the number of update requests in flight. Defaults to 4.
+	Concurrency int
+
+	// NumRuns is the number of runs fetched per request when selecting by group. Longer histories are fetched in several
+	// requests. Defaults to 1000.
+	NumRuns int64
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewManager : Instantiate Manager
+func NewManager(client Client, tenantID string, audit *AuditLog, actor string) *Manager {
+	return &Manager{
+		client:      client,
+		tenantID:    tenantID,
+		audit:       audit,
+		Actor:       actor,
+		BatchSize:   25,
+		Concurrency: 4,
+		NumRuns:     1000,
+		Now:         time.Now,
+	}
+}
+
+// Select resolves the selector to runs, ordered by group and start time.
+func (manager *Manager) Select(ctx context.Context, selector Selector) ([]Run, error) {
+	runs := map[string]Run{}
+	for _, groupID := range selector.GroupIDs {
+		options := &backuprecoveryv1.GetProtectionGroupRunsOptions{
+			ID:           core.StringPtr(groupID),
+			XIBMTenantID: core.StringPtr(manager.tenantID),
+		}
+		if !selector.From.IsZero() {
+			options.StartTimeUsecs = core.Int64Ptr(selector.From.UnixMicro())
+		}
+		if !selector.To.IsZero() {
+			options.EndTimeUsecs = core.Int64Ptr(selector.To.UnixMicro())
+		}
+		found, err := manager.runs(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range found {
+			runs[run.GroupID+"/"+run.RunID] = run
+		}
+	}
+
+	for _, objectID := range selector.ObjectIDs {
+		options := &backuprecoveryv1.GetObjectSnapshotsOptions{
+			ID:           core.Int64Ptr(objectID),
+			XIBMTenantID: core.StringPtr(manager.tenantID),
+		}
+		if !selector.From.IsZero() {
+			options.FromTimeUsecs = core.Int64Ptr(selector.From.UnixMicro())
+		}
+		if !selector.To.IsZero() {
+			options.ToTimeUsecs = core.Int64Ptr(selector.To.UnixMicro())
+		}
+		snapshots, _, err := manager.client.GetObjectSnapshotsWithContext(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("listing snapshots of object %d: %w", objectID, err)
+		}
+		if snapshots == nil {
+			continue
+		}
+		for _, snapshot := range snapshots.Snapshots {
+			if snapshot.ProtectionGroupID == nil || snapshot.ProtectionGroupRunID == nil {
+				continue
+			}
+			key := *snapshot.ProtectionGroupID + "/" + *snapshot.ProtectionGroupRunID
+			if _, ok := runs[key]; ok {
+				continue
+			}
+			found, err := manager.runs(ctx, &backuprecoveryv1.GetProtectionGroupRunsOptions{
+				ID:           snapshot.ProtectionGroupID,
+				XIBMTenantID: core.StringPtr(manager.tenantID),
+				RunID:        snapshot.ProtectionGroupRunID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, run := range found {
+				runs[run.GroupID+"/"+run.RunID] = run
+			}
+		}
+	}
+
+	selected := make([]Run, 0, len(runs))
+	for _, run := range runs {
+		selected = append(selected, run)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].GroupID != selected[j].GroupID {
+			return selected[i].GroupID < selected[j].GroupID
+		}
+		if !selected[i].StartTime.Equal(selected[j].StartTime) {
+			return selected[i].StartTime.Before(selected[j].StartTime)
+		}
+		return selected[i].RunID < selected[j].RunID
+	})
+	return selected, nil
+}
+
+func (manager *Manager) runs(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) ([]Run, error) {
+	options.NumRuns = core.Int64Ptr(manager.NumRuns)
+	found, err := retention.ListRuns(ctx, manager.client, *options)
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	for i := range found {
+		if found[i].ID != nil {
+			runs = append(runs, newRun(*options.ID, &found[i]))
+		}
+	}
+	return runs, nil
+}
+
+func newRun(groupID string, source *backuprecoveryv1.ProtectionGroupRun) Run {
+	run := Run{
+		GroupID:     groupID,
+		GroupName:   helpers.Deref(source.ProtectionGroupName),
+		RunID:       *source.ID,
+		OnLegalHold: source.OnLegalHold != nil && *source.OnLegalHold,
+	}
+	if snapshotTime, ok := retention.SnapshotTime(source); ok {
+		run.StartTime = snapshotTime
+	}
+	if source.ReplicationInfo != nil {
+		for _, target := range source.ReplicationInfo.ReplicationTargetResults {
+			if target.ClusterID != nil {
+				run.Copies = append(run.Copies, Copy{Kind: retention.CopyReplication, ID: *target.ClusterID, Name: helpers.Deref(target.ClusterName)})
+			}
+		}
+	}
+	if source.ArchivalInfo != nil {
+		for _, target := range source.ArchivalInfo.ArchivalTargetResults {
+			if target.TargetID != nil {
+				run.Copies = append(run.Copies, Copy{
+					Kind:               retention.CopyArchival,
+					ID:                 *target.TargetID,
+					Name:               helpers.Deref(target.TargetName),
+					ArchivalTargetType: helpers.Deref(target.TargetType),
+				})
+			}
+		}
+	}
+	return run
+}
+
+// Hold places the runs on legal hold.
+func (manager *Manager) Hold(ctx context.Context, runs []Run, reason string) (*Result, error) {
+	return manager.apply(ctx, ActionHold, runs, reason)
+}
+
+// Release releases the runs from legal hold.
+func (manager *Manager) Release(ctx context.Context, runs []Run, reason string) (*Result, error) {
+	return manager.apply(ctx, ActionRelease, runs, reason)
+}
+
+// apply sends the change in batches of runs of the same group, verifies every run afterwards and audits each step.
+// No change is sent unless its request was written to the audit log; an audit failure stops further batches and is
+// returned together with the partial result.
+func (manager *Manager) apply(ctx context.Context, action Action, runs []Run, reason string) (*Result, error) {
+	if reason == "" {
+		return nil, errors.New("a reason is required for legal hold changes")
+	}
+	if manager.audit == nil {
+		return nil, errors.New("an audit log is required for legal hold changes")
+	}
+	enable := action == ActionHold
+
+	result := &Result{Action: action, Runs: make([]RunResult, len(runs))}
+	var batches [][]int
+	pending := map[string][]int{}
+	var groups []string
+	for i, run := range runs {
+		result.Runs[i].Run = run
+		if run.OnLegalHold == enable {
+			result.Runs[i].Outcome = OutcomeVerified
+			result.Runs[i].Unchanged = true
+			continue
+		}
+		if pending[run.GroupID] == nil {
+			groups = append(groups, run.GroupID)
+		}
+		pending[run.GroupID] = append(pending[run.GroupID], i)
+	}
+	for _, groupID := range groups {
+		indexes := pending[groupID]
+		for len(indexes) > 0 {
+			size := min(manager.BatchSize, len(indexes))
+			batches = append(batches, indexes[:size])
+			indexes = indexes[size:]
+		}
+	}
+
+	var auditErr error
+	var mutex sync.Mutex
+	record := func(i int, outcome Outcome, message string) bool {
+		run := result.Runs[i].Run
+		entry := Entry{
+			Time:    manager.Now().UTC(),
+			Actor:   manager.Actor,
+			Action:  action,
+			Reason:  reason,
+			GroupID: run.GroupID,
+			RunID:   run.RunID,
+			Outcome: outcome,
+			Error:   message,
+		}
+		for _, target := range run.Copies {
+			entry.Copies = append(entry.Copies, fmt.Sprintf("%s:%d", target.Kind, target.ID))
+		}
+		if err := manager.audit.Record(entry); err != nil {
+			mutex.Lock()
+			if auditErr == nil {
+				auditErr = fmt.Errorf("writing audit log: %w", err)
+			}
+			mutex.Unlock()
+			return false
+		}
+		return true
+	}
+	failed := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return auditErr != nil
+	}
+
+	semaphore := make(chan struct{}, max(1, manager.Concurrency))
+	var wait sync.WaitGroup
+	for _, batch := range batches {
+		if failed() || ctx.Err() != nil {
+			break
+		}
+		semaphore <- struct{}{}
+		wait.Add(1)
+		go func(batch []int) {
+			defer wait.Done()
+			defer func() { <-semaphore }()
+			manager.batch(ctx, enable, result, batch, record)
+		}(batch)
+	}
+	wait.Wait()
+
+	if auditErr != nil {
+		return result, auditErr
+	}
+	return result, ctx.Err()
+}
+
+// batch updates and verifies the runs at the given indexes, which all belong to the same group.
+func (manager *Manager) batch(ctx context.Context, enable bool, result *Result, batch []int, record func(int, Outcome, string) bool) {
+	groupID := result.Runs[batch[0]].Run.GroupID
+	var params []backuprecoveryv1.UpdateProtectionGroupRunParams
+	var sent []int
+	for _, i := range batch {
+		if !record(i, OutcomeRequested, "") {
+			result.Runs[i].Error = "not sent: audit log unavailable"
+			continue
+		}
+		params = append(params, updateParams(result.Runs[i].Run, enable))
+		sent = append(sent, i)
+	}
+	if len(sent) == 0 {
+		return
+	}
+
+	response, _, err := manager.client.UpdateProtectionGroupRunWithContext(ctx, &backuprecoveryv1.UpdateProtectionGroupRunOptions{
+		ID:                             core.StringPtr(groupID),
+		XIBMTenantID:                   core.StringPtr(manager.tenantID),
+		UpdateProtectionGroupRunParams: params,
+	})
+	rejected := map[string]string{}
+	if response != nil {
+		for _, failure := range response.FailedRuns {
+			rejected[helpers.Deref(failure.RunID)] = helpers.Deref(failure.ErrorMessage)
+		}
+	}
+
+	for _, i := range sent {
+		runResult := &result.Runs[i]
+		message, isRejected := rejected[runResult.Run.RunID]
+		switch {
+		case err != nil:
+			message = err.Error()
+		case isRejected && message == "":
+			message = "update rejected"
+		case !isRejected:
+			message = manager.verify(ctx, runResult.Run, enable)
+			if message == "" {
+				runResult.Outcome = OutcomeVerified
+				record(i, OutcomeVerified, "")
+				continue
+			}
+			runResult.Outcome = OutcomeMismatch
+			runResult.Error = message
+			record(i, OutcomeMismatch, message)
+			continue
+		}
+		runResult.Outcome = OutcomeFailed
+		runResult.Error = message
+		record(i, OutcomeFailed, message)
+	}
+}
+
+// verify re-reads the run and returns a description of any copy not in the requested legal hold state.
+func (manager *Manager) verify(ctx context.Context, run Run, enable bool) string {
+	result, _, err := manager.client.GetProtectionGroupRunsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupRunsOptions{
+		ID:           core.StringPtr(run.GroupID),
+		XIBMTenantID: core.StringPtr(manager.tenantID),
+		RunID:        core.StringPtr(run.RunID),
+	})
+	if err != nil {
+		return fmt.Sprintf("verifying run: %s", err)
+	}
+	if result == nil || len(result.Runs) == 0 {
+		return "verifying run: run not found"
+	}
+	current := &result.Runs[0]
+	if current.OnLegalHold == nil || *current.OnLegalHold != enable {
+		return fmt.Sprintf("run reports onLegalHold=%t", !enable)
+	}
+	if current.ReplicationInfo != nil {
+		for _, target := range current.ReplicationInfo.ReplicationTargetResults {
+			if target.OnLegalHold != nil && *target.OnLegalHold != enable {
+				return fmt.Sprintf("replication copy on cluster %d reports onLegalHold=%t", helpers.Deref(target.ClusterID), !enable)
+			}
+		}
+	}
+	if current.ArchivalInfo != nil {
+		for _, target := range current.ArchivalInfo.ArchivalTargetResults {
+			if target.OnLegalHold != nil && *target.OnLegalHold != enable {
+				return fmt.Sprintf("archival copy on target %d reports onLegalHold=%t", helpers.Deref(target.TargetID), !enable)
+			}
+		}
+	}
+	return ""
+}
+
+func updateParams(run Run, enable bool) backuprecoveryv1.UpdateProtectionGroupRunParams {
+	params := backuprecoveryv1.UpdateProtectionGroupRunParams{
+		RunID:               core.StringPtr(run.RunID),
+		LocalSnapshotConfig: &backuprecoveryv1.UpdateLocalSnapshotConfig{EnableLegalHold: core.BoolPtr(enable)},
+	}
+	for _, target := range run.Copies {
+		switch target.Kind {
+		case retention.CopyReplication:
+			if params.ReplicationSnapshotConfig == nil {
+				params.ReplicationSnapshotConfig = &backuprecoveryv1.UpdateReplicationSnapshotConfig{}
+			}
+			params.ReplicationSnapshotConfig.UpdateExistingSnapshotConfig = append(params.ReplicationSnapshotConfig.UpdateExistingSnapshotConfig,
+				backuprecoveryv1.UpdateExistingReplicationSnapshotConfig{
+					ID:              core.Int64Ptr(target.ID),
+					Name:            optional(target.Name),
+					EnableLegalHold: core.BoolPtr(enable),
+				})
+		case retention.CopyArchival:
+			if params.ArchivalSnapshotConfig == nil {
+				params.ArchivalSnapshotConfig = &backuprecoveryv1.UpdateArchivalSnapshotConfig{}
+			}
+			params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig = append(params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig,
+				backuprecoveryv1.UpdateExistingArchivalSnapshotConfig{
+					ID:                 core.Int64Ptr(target.ID),
+					Name:               optional(target.Name),
+					ArchivalTargetType: core.StringPtr(target.ArchivalTargetType),
+					EnableLegalHold:    core.BoolPtr(enable),
+				})
+		}
+	}
+	return params
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return core.StringPtr(value)
+}
@@ -0,0 +1,243 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package legalhold
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	mutex    sync.Mutex
+	runs     map[string][]backuprecoveryv1.ProtectionGroupRun
+	rejected map[string]bool
+	stuck    map[string]bool
+	updates  []*backuprecoveryv1.UpdateProtectionGroupRunOptions
+	inFlight int
+	peak     int
+}
+
+func newFakeClient() *fakeClient {
+	fake := &fakeClient{runs: map[string][]backuprecoveryv1.ProtectionGroupRun{}, rejected: map[string]bool{}, stuck: map[string]bool{}}
+	for i := 0; i < 5; i++ {
+		run := backuprecoveryv1.ProtectionGroupRun{
+			ID:                  core.StringPtr(fmt.Sprintf("a-%d", i)),
+			ProtectionGroupName: core.StringPtr("files"),
+			OnLegalHold:         core.BoolPtr(false),
+			LocalBackupInfo:     &backuprecoveryv1.BackupRunSummary{StartTimeUsecs: core.Int64Ptr(base.Add(time.Duration(i) * time.Hour).UnixMicro())},
+		}
+		if i == 0 {
+			run.ArchivalInfo = &backuprecoveryv1.ArchivalRunSummary{ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{{
+				TargetID: core.Int64Ptr(100), TargetName: core.StringPtr("vault"), TargetType: core.StringPtr("Cloud"), OnLegalHold: core.BoolPtr(false),
+			}}}
+			run.ReplicationInfo = &backuprecoveryv1.ReplicationRunSummary{ReplicationTargetResults: []backuprecoveryv1.ReplicationTargetResult{{
+				ClusterID: core.Int64Ptr(5), ClusterName: core.StringPtr("dr"),
+			}}}
+		}
+		fake.runs["pg-a"] = append(fake.runs["pg-a"], run)
+	}
+	fake.runs["pg-b"] = []backuprecoveryv1.ProtectionGroupRun{{
+		ID:              core.StringPtr("b-0"),
+		OnLegalHold:     core.BoolPtr(true),
+		LocalBackupInfo: &backuprecoveryv1.BackupRunSummary{StartTimeUsecs: core.Int64Ptr(base.UnixMicro())},
+	}}
+	return fake
+}
+
+func (fake *fakeClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	// Runs are returned newest first, at most NumRuns at a time.
+	var runs []backuprecoveryv1.ProtectionGroupRun
+	all := fake.runs[*options.ID]
+	for i := len(all) - 1; i >= 0 && (options.NumRuns == nil || int64(len(runs)) < *options.NumRuns); i-- {
+		run := all[i]
+		if options.RunID != nil && *options.RunID != *run.ID {
+			continue
+		}
+		if options.EndTimeUsecs != nil && *run.LocalBackupInfo.StartTimeUsecs > *options.EndTimeUsecs {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: runs}, nil, nil
+}
+
+func (fake *fakeClient) GetObjectSnapshotsWithContext(ctx context.Context, options *backuprecoveryv1.GetObjectSnapshotsOptions) (*backuprecoveryv1.GetObjectSnapshotsResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.GetObjectSnapshotsResponse{Snapshots: []backuprecoveryv1.ObjectSnapshot{
+		{ProtectionGroupID: core.StringPtr("pg-b"), ProtectionGroupRunID: core.StringPtr("b-0")},
+		{ProtectionGroupID: core.StringPtr("pg-a"), ProtectionGroupRunID: core.StringPtr("a-1")},
+	}}, nil, nil
+}
+
+func (fake *fakeClient) UpdateProtectionGroupRunWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionGroupRunOptions) (*backuprecoveryv1.UpdateProtectionGroupRunResponse, *core.DetailedResponse, error) {
+	fake.mutex.Lock()
+	fake.updates = append(fake.updates, options)
+	fake.inFlight++
+	fake.peak = max(fake.peak, fake.inFlight)
+	fake.mutex.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.inFlight--
+	response := &backuprecoveryv1.UpdateProtectionGroupRunResponse{}
+	for _, params := range options.UpdateProtectionGroupRunParams {
+		if fake.rejected[*params.RunID] {
+			response.FailedRuns = append(response.FailedRuns, backuprecoveryv1.FailedRunDetails{RunID: params.RunID, ErrorMessage: core.StringPtr("run is locked")})
+			continue
+		}
+		for i, run := range fake.runs[*options.ID] {
+			if *run.ID == *params.RunID {
+				fake.runs[*options.ID][i].OnLegalHold = params.LocalSnapshotConfig.EnableLegalHold
+				if run.ArchivalInfo != nil && params.ArchivalSnapshotConfig != nil && !fake.stuck[*run.ID] {
+					run.ArchivalInfo.ArchivalTargetResults[0].OnLegalHold = params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig[0].EnableLegalHold
+				}
+			}
+		}
+		response.SuccessfulRunIds = append(response.SuccessfulRunIds, *params.RunID)
+	}
+	return response, nil, nil
+}
+
+func entries(t *testing.T, log *bytes.Buffer) []Entry {
+	var all []Entry
+	scanner := bufio.NewScanner(log)
+	for scanner.Scan() {
+		var entry Entry
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &entry))
+		all = append(all, entry)
+	}
+	return all
+}
+
+func TestSelect(t *testing.T) {
+	manager := NewManager(newFakeClient(), "tenant", NewAuditLog(&bytes.Buffer{}), "legal")
+	manager.NumRuns = 2
+
+	runs, err := manager.Select(context.Background(), Selector{GroupIDs: []string{"pg-a"}, ObjectIDs: []int64{42}})
+	require.Nil(t, err)
+	require.Len(t, runs, 6)
+	assert.Equal(t, "a-0", runs[0].RunID)
+	assert.Equal(t, base, runs[0].StartTime)
+	assert.Equal(t, []Copy{
+		{Kind: retention.CopyReplication, ID: 5, Name: "dr"},
+		{Kind: retention.CopyArchival, ID: 100, Name: "vault", ArchivalTargetType: "Cloud"},
+	}, runs[0].Copies)
+	assert.Equal(t, "b-0", runs[5].RunID)
+	assert.True(t, runs[5].OnLegalHold)
+}
+
+func TestHold(t *testing.T) {
+	client := newFakeClient()
+	client.rejected["a-3"] = true
+	var log bytes.Buffer
+	manager := NewManager(client, "tenant", NewAuditLog(&log), "legal")
+	manager.BatchSize = 2
+	manager.Concurrency = 2
+	manager.Now = func() time.Time { return base }
+
+	runs, err := manager.Select(context.Background(), Selector{GroupIDs: []string{"pg-a", "pg-b"}})
+	require.Nil(t, err)
+
+	_, err = manager.Hold(context.Background(), runs, "")
+	assert.NotNil(t, err)
+
+	result, err := manager.Hold(context.Background(), runs, "case 2026-17")
+	require.Nil(t, err)
+	require.Len(t, result.Runs, 6)
+
+	// Five runs of pg-a in batches of two; b-0 is already on hold.
+	assert.Len(t, client.updates, 3)
+	assert.LessOrEqual(t, client.peak, 2)
+	var params backuprecoveryv1.UpdateProtectionGroupRunParams
+	for _, update := range client.updates {
+		if *update.UpdateProtectionGroupRunParams[0].RunID == "a-0" {
+			params = update.UpdateProtectionGroupRunParams[0]
+		}
+	}
+	require.NotNil(t, params.ReplicationSnapshotConfig)
+	assert.True(t, *params.ReplicationSnapshotConfig.UpdateExistingSnapshotConfig[0].EnableLegalHold)
+	assert.Equal(t, "Cloud", *params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig[0].ArchivalTargetType)
+
+	failed := result.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "a-3", failed[0].Run.RunID)
+	assert.Equal(t, OutcomeFailed, failed[0].Outcome)
+	assert.Equal(t, "run is locked", failed[0].Error)
+	assert.True(t, result.Runs[5].Unchanged)
+
+	outcomes := map[Outcome]int{}
+	for _, entry := range entries(t, &log) {
+		assert.Equal(t, "legal", entry.Actor)
+		assert.Equal(t, "case 2026-17", entry.Reason)
+		assert.Equal(t, ActionHold, entry.Action)
+		outcomes[entry.Outcome]++
+	}
+	assert.Equal(t, map[Outcome]int{OutcomeRequested: 5, OutcomeVerified: 4, OutcomeFailed: 1}, outcomes)
+}
+
+func TestReleaseDetectsMismatch(t *testing.T) {
+	client := newFakeClient()
+	// The archival copy keeps its hold although the run is released.
+	client.runs["pg-a"][0].OnLegalHold = core.BoolPtr(true)
+	client.runs["pg-a"][0].ArchivalInfo.ArchivalTargetResults[0].OnLegalHold = core.BoolPtr(true)
+	client.stuck["a-0"] = true
+	var log bytes.Buffer
+	manager := NewManager(client, "tenant", NewAuditLog(&log), "legal")
+
+	runs, err := manager.Select(context.Background(), Selector{GroupIDs: []string{"pg-a"}})
+	require.Nil(t, err)
+	result, err := manager.Release(context.Background(), runs[:1], "case closed")
+	require.Nil(t, err)
+	assert.Equal(t, OutcomeMismatch, result.Runs[0].Outcome)
+	assert.Equal(t, "archival copy on target 100 reports onLegalHold=true", result.Runs[0].Error)
+	assert.False(t, *client.updates[0].UpdateProtectionGroupRunParams[0].LocalSnapshotConfig.EnableLegalHold)
+}
+
+func TestOpenAuditLogAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		log, err := OpenAuditLog(path)
+		require.Nil(t, err)
+		require.Nil(t, log.Record(Entry{RunID: fmt.Sprint(i), Outcome: OutcomeRequested}))
+		require.Nil(t, log.Close())
+	}
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	all := entries(t, bytes.NewBuffer(content))
+	require.Len(t, all, 2)
+	assert.Equal(t, "0", all[0].RunID)
+	assert.Equal(t, "1", all[1].RunID)
+}
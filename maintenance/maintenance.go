@@ -0,0 +1,506 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package maintenance quiesces protection for a planned maintenance window and restores it afterwards.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupByIDWithContext(ctx context.Context, getProtectionGroupByIdOptions *backuprecoveryv1.GetProtectionGroupByIdOptions) (result *backuprecoveryv1.ProtectionGroupResponse, response *core.DetailedResponse, err error)
+	UpdateProtectionGroupWithContext(ctx context.Context, updateProtectionGroupOptions *backuprecoveryv1.UpdateProtectionGroupOptions) (result *backuprecoveryv1.ProtectionGroupResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+	PerformActionOnProtectionGroupRunWithContext(ctx context.Context, performActionOnProtectionGroupRunOptions *backuprecoveryv1.PerformActionOnProtectionGroupRunOptions) (result *backuprecoveryv1.PerformRunActionResponse, response *core.DetailedResponse, err error)
+	CreateProtectionGroupRunWithContext(ctx context.Context, createProtectionGroupRunOptions *backuprecoveryv1.CreateProtectionGroupRunOptions) (result *backuprecoveryv1.CreateProtectionGroupRunResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// RunAction is what happens to runs that are in flight when the window opens.
+type RunAction string
+
+const (
+	// RunActionWait lets in-flight runs finish.
+	RunActionWait RunAction = "wait"
+	// RunActionPause pauses in-flight runs; they are resumed when the window closes.
+	RunActionPause RunAction = "pause"
+	// RunActionCancel cancels in-flight runs.
+	RunActionCancel RunAction = "cancel"
+)
+
+// Phase is the progress of a maintenance window.
+type Phase string
+
+const (
+	PhaseQuiescing Phase = "quiescing"
+	PhaseQuiesced  Phase = "quiesced"
+	PhaseResuming  Phase = "resuming"
+	PhaseResumed   Phase = "resumed"
+)
+
+// GroupState records what the coordinator did to one protection group.
+type GroupState struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Pausing is set before the group is paused, so that a group paused by a coordinator that crashed before saving
+	// Paused is still resumed.
+	Pausing bool `json:"pausing,omitempty"`
+	Paused  bool `json:"paused,omitempty"`
+	Resumed bool `json:"resumed,omitempty"`
+
+	PausedRuns    []string `json:"pausedRuns,omitempty"`
+	CancelledRuns []string `json:"cancelledRuns,omitempty"`
+	CatchUpRun    bool     `json:"catchUpRun,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+func (group *GroupState) owned() bool {
+	return (group.Pausing || group.Paused) && !group.Resumed
+}
+
+// State is the persisted record of a maintenance window.
+type State struct {
+	Reason     string        `json:"reason,omitempty"`
+	Phase      Phase         `json:"phase"`
+	RunAction  RunAction     `json:"runAction"`
+	OpenedAt   time.Time     `json:"openedAt"`
+	QuiescedAt time.Time     `json:"quiescedAt,omitempty"`
+	ClosedAt   time.Time     `json:"closedAt,omitempty"`
+	Groups     []*GroupState `json:"groups"`
+}
+
+// Open reports whether protection is, or may partially be, quiesced by the window.
+func (state *State) Open() bool {
+	return state.Phase != PhaseResumed
+}
+
+// QuiesceOptions configures the opening of a window.
+type QuiesceOptions struct {
+	// Reason is recorded in the state.
+	Reason string
+
+	// GroupIDs restricts the window to these groups. Every active group is quiesced when empty.
+	GroupIDs []string
+
+	// RunAction is applied to in-flight runs. Defaults to RunActionWait.
+	RunAction RunAction
+}
+
+// ResumeOptions configures the closing of a window.
+type ResumeOptions struct {
+	// CatchUp starts a run of every resumed group, so that the backups missed during the window are not delayed
+	// until the next scheduled run.
+	CatchUp bool
+
+	// CatchUpRunType is the type of the catch-up runs. Defaults to kRegular.
+	CatchUpRunType string
+}
+
+// ErrWindowOpen is returned by Quiesce when the state file records a window that was not resumed.
+var ErrWindowOpen = errors.New("maintenance window already open")
+
+// Coordinator opens and closes maintenance windows.
+type Coordinator struct {
+	client   Client
+	tenantID string
+	file     helpers.JSONFile[State]
+
+	// PollInterval is the delay between checks for running runs. Defaults to 30 seconds.
+	PollInterval time.Duration
+
+	// IdleTimeout bounds the wait for in-flight runs in Quiesce. Defaults to two hours.
+	IdleTimeout time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewCoordinator : Instantiate Coordinator. The state of the window is kept in the file at statePath.
+func NewCoordinator(client Client, tenantID string, statePath string) *Coordinator {
+	return &Coordinator{
+		client:       client,
+		tenantID:     tenantID,
+		file:         helpers.JSONFile[State]{Path: statePath},
+		PollInterval: 30 * time.Second,
+		IdleTimeout:  2 * time.Hour,
+		Now:          time.Now,
+	}
+}
+
+// Load returns the saved state, or nil when there is none.
+func (coordinator *Coordinator) Load() (*State, error) {
+	return coordinator.file.Load()
+}
+
+// Quiesce pauses the active groups, applies the run action to their in-flight runs and waits until none of them is
+// running. Groups that are already paused are left alone and will not be resumed. Calling Quiesce again after a
+// crash continues the interrupted window; calling it while a completed window is still open returns ErrWindowOpen.
+func (coordinator *Coordinator) Quiesce(ctx context.Context, options QuiesceOptions) (*State, error) {
+	state, err := coordinator.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Open() && state.Phase != PhaseQuiescing {
+		return state, ErrWindowOpen
+	}
+	if state == nil || !state.Open() {
+		if options.RunAction == "" {
+			options.RunAction = RunActionWait
+		}
+		state = &State{Reason: options.Reason, Phase: PhaseQuiescing, RunAction: options.RunAction, OpenedAt: coordinator.Now().UTC()}
+		groups, err := coordinator.activeGroups(ctx, options.GroupIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			state.Groups = append(state.Groups, &GroupState{ID: helpers.Deref(group.ID), Name: helpers.Deref(group.Name), Pausing: !helpers.Deref(group.IsPaused)})
+		}
+		if err := coordinator.save(state); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, group := range state.Groups {
+		if !group.Pausing || group.Paused {
+			continue
+		}
+		if err := coordinator.setPaused(ctx, group.ID, true); err != nil {
+			group.Error = err.Error()
+			errs = append(errs, err)
+		} else {
+			group.Paused, group.Error = true, ""
+		}
+		if err := coordinator.save(state); err != nil {
+			return state, err
+		}
+	}
+	if len(errs) > 0 {
+		return state, errors.Join(errs...)
+	}
+
+	if state.RunAction != RunActionWait {
+		if err := coordinator.stopRuns(ctx, state); err != nil {
+			return state, err
+		}
+	}
+	if err := coordinator.waitIdle(ctx, state); err != nil {
+		return state, err
+	}
+	state.Phase, state.QuiescedAt = PhaseQuiesced, coordinator.Now().UTC()
+	return state, coordinator.save(state)
+}
+
+// Resume restores the state from before the window: it resumes exactly the groups the coordinator paused, resumes
+// the runs it paused and optionally starts catch-up runs. Resume can be called at any phase, including after a
+// crash during Quiesce, and can be repeated until it succeeds.
+func (coordinator *Coordinator) Resume(ctx context.Context, options ResumeOptions) (*State, error) {
+	state, err := coordinator.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no maintenance window recorded in '%s'", coordinator.file.Path)
+	}
+	if !state.Open() {
+		return state, nil
+	}
+	if options.CatchUpRunType == "" {
+		options.CatchUpRunType = backuprecoveryv1.CreateProtectionGroupRunOptions_RunType_Kregular
+	}
+	state.Phase = PhaseResuming
+	if err := coordinator.save(state); err != nil {
+		return state, err
+	}
+
+	var errs []error
+	for _, group := range state.Groups {
+		if len(group.PausedRuns) > 0 {
+			if err := coordinator.resumeRuns(ctx, group); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if group.owned() {
+			if err := coordinator.setPaused(ctx, group.ID, false); err != nil {
+				group.Error = err.Error()
+				errs = append(errs, err)
+			} else {
+				group.Resumed, group.Error = true, ""
+			}
+		}
+		// Checked apart from the resume, so that a catch-up run that failed to start is retried by the next Resume.
+		if options.CatchUp && group.Resumed && !group.CatchUpRun {
+			group.CatchUpRun = true
+			if _, _, err := coordinator.client.CreateProtectionGroupRunWithContext(ctx, &backuprecoveryv1.CreateProtectionGroupRunOptions{
+				ID:           core.StringPtr(group.ID),
+				XIBMTenantID: core.StringPtr(coordinator.tenantID),
+				RunType:      core.StringPtr(options.CatchUpRunType),
+			}); err != nil {
+				group.CatchUpRun = false
+				errs = append(errs, fmt.Errorf("starting catch-up run of group '%s': %w", group.ID, err))
+			}
+		}
+		if err := coordinator.save(state); err != nil {
+			return state, err
+		}
+	}
+	if len(errs) > 0 {
+		return state, errors.Join(errs...)
+	}
+	state.Phase, state.ClosedAt = PhaseResumed, coordinator.Now().UTC()
+	return state, coordinator.save(state)
+}
+
+func (coordinator *Coordinator) activeGroups(ctx context.Context, ids []string) ([]backuprecoveryv1.ProtectionGroupResponse, error) {
+	options := &backuprecoveryv1.GetProtectionGroupsOptions{
+		XIBMTenantID: core.StringPtr(coordinator.tenantID),
+		IsActive:     core.BoolPtr(true),
+		IsDeleted:    core.BoolPtr(false),
+	}
+	if len(ids) > 0 {
+		options.Ids = ids
+	}
+	result, _, err := coordinator.client.GetProtectionGroupsWithContext(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("listing protection groups: %w", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	groups := result.ProtectionGroups
+	sort.SliceStable(groups, func(i, j int) bool { return helpers.Deref(groups[i].ID) < helpers.Deref(groups[j].ID) })
+	return groups, nil
+}
+
+// setPaused updates the pause flag of a group. The update replaces the whole group, so it is built from the group
+// as currently stored.
+func (coordinator *Coordinator) setPaused(ctx context.Context, id string, paused bool) error {
+	group, _, err := coordinator.client.GetProtectionGroupByIDWithContext(ctx, &backuprecoveryv1.GetProtectionGroupByIdOptions{
+		ID:           core.StringPtr(id),
+		XIBMTenantID: core.StringPtr(coordinator.tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("getting protection group '%s': %w", id, err)
+	}
+	if helpers.Deref(group.IsPaused) == paused {
+		return nil
+	}
+	options := updateOptions(group, coordinator.tenantID)
+	options.IsPaused = core.BoolPtr(paused)
+	if _, _, err := coordinator.client.UpdateProtectionGroupWithContext(ctx, options); err != nil {
+		return fmt.Errorf("updating protection group '%s': %w", id, err)
+	}
+	return nil
+}
+
+func updateOptions(group *backuprecoveryv1.ProtectionGroupResponse, tenantID string) *backuprecoveryv1.UpdateProtectionGroupOptions {
+	return &backuprecoveryv1.UpdateProtectionGroupOptions{
+		ID:                         group.ID,
+		XIBMTenantID:               core.StringPtr(tenantID),
+		Name:                       group.Name,
+		PolicyID:                   group.PolicyID,
+		Environment:                group.Environment,
+		Priority:                   group.Priority,
+		Description:                group.Description,
+		StartTime:                  group.StartTime,
+		EndTimeUsecs:               group.EndTimeUsecs,
+		LastModifiedTimestampUsecs: group.LastModifiedTimestampUsecs,
+		AlertPolicy:                group.AlertPolicy,
+		Sla:                        group.Sla,
+		QosPolicy:                  group.QosPolicy,
+		AbortInBlackouts:           group.AbortInBlackouts,
+		PauseInBlackouts:           group.PauseInBlackouts,
+		IsPaused:                   group.IsPaused,
+		AdvancedConfigs:            group.AdvancedConfigs,
+		PhysicalParams:             group.PhysicalParams,
+		MssqlParams:                group.MssqlParams,
+		KubernetesParams:           group.KubernetesParams,
+	}
+}
+
+// inFlight are the run statuses that keep a group busy.
+var inFlight = []string{
+	backuprecoveryv1.GetProtectionGroupRunsOptions_LocalBackupRunStatus_Accepted,
+	backuprecoveryv1.GetProtectionGroupRunsOptions_LocalBackupRunStatus_Running,
+	backuprecoveryv1.GetProtectionGroupRunsOptions_LocalBackupRunStatus_Finalizing,
+	backuprecoveryv1.GetProtectionGroupRunsOptions_LocalBackupRunStatus_Canceling,
+}
+
+func (coordinator *Coordinator) runningRuns(ctx context.Context, groupID string) ([]string, error) {
+	result, _, err := coordinator.client.GetProtectionGroupRunsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupRunsOptions{
+		ID:                   core.StringPtr(groupID),
+		XIBMTenantID:         core.StringPtr(coordinator.tenantID),
+		LocalBackupRunStatus: inFlight,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing runs of group '%s': %w", groupID, err)
+	}
+	var ids []string
+	if result != nil {
+		for _, run := range result.Runs {
+			ids = append(ids, helpers.Deref(run.ID))
+		}
+	}
+	return ids, nil
+}
+
+func (coordinator *Coordinator) stopRuns(ctx context.Context, state *State) error {
+	var errs []error
+	for _, group := range state.Groups {
+		running, err := coordinator.runningRuns(ctx, group.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done := map[string]bool{}
+		for _, id := range append(group.PausedRuns, group.CancelledRuns...) {
+			done[id] = true
+		}
+		options := &backuprecoveryv1.PerformActionOnProtectionGroupRunOptions{
+			ID:           core.StringPtr(group.ID),
+			XIBMTenantID: core.StringPtr(coordinator.tenantID),
+		}
+		var ids []string
+		for _, id := range running {
+			if done[id] {
+				continue
+			}
+			ids = append(ids, id)
+			if state.RunAction == RunActionPause {
+				options.PauseParams = append(options.PauseParams, backuprecoveryv1.PauseProtectionRunActionParams{RunID: core.StringPtr(id)})
+			} else {
+				options.CancelParams = append(options.CancelParams, backuprecoveryv1.CancelProtectionGroupRunRequest{RunID: core.StringPtr(id)})
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if state.RunAction == RunActionPause {
+			options.Action = core.StringPtr(backuprecoveryv1.PerformActionOnProtectionGroupRunOptions_Action_Pause)
+		} else {
+			options.Action = core.StringPtr(backuprecoveryv1.PerformActionOnProtectionGroupRunOptions_Action_Cancel)
+		}
+		result, _, err := coordinator.client.PerformActionOnProtectionGroupRunWithContext(ctx, options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("stopping runs of group '%s': %w", group.ID, err))
+			continue
+		}
+		failed := map[string]string{}
+		if result != nil {
+			for _, params := range result.PauseParams {
+				if params.Error != nil && *params.Error != "" {
+					failed[helpers.Deref(params.RunID)] = *params.Error
+				}
+			}
+		}
+		for _, id := range ids {
+			if message, ok := failed[id]; ok {
+				errs = append(errs, fmt.Errorf("pausing run '%s' of group '%s': %s", id, group.ID, message))
+			} else if state.RunAction == RunActionPause {
+				group.PausedRuns = append(group.PausedRuns, id)
+			} else {
+				group.CancelledRuns = append(group.CancelledRuns, id)
+			}
+		}
+		if err := coordinator.save(state); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (coordinator *Coordinator) resumeRuns(ctx context.Context, group *GroupState) error {
+	options := &backuprecoveryv1.PerformActionOnProtectionGroupRunOptions{
+		ID:           core.StringPtr(group.ID),
+		XIBMTenantID: core.StringPtr(coordinator.tenantID),
+		Action:       core.StringPtr(backuprecoveryv1.PerformActionOnProtectionGroupRunOptions_Action_Resume),
+	}
+	for _, id := range group.PausedRuns {
+		options.ResumeParams = append(options.ResumeParams, backuprecoveryv1.ResumeProtectionRunActionParams{RunID: core.StringPtr(id)})
+	}
+	result, _, err := coordinator.client.PerformActionOnProtectionGroupRunWithContext(ctx, options)
+	if err != nil {
+		return fmt.Errorf("resuming runs of group '%s': %w", group.ID, err)
+	}
+	failed := map[string]string{}
+	if result != nil {
+		for _, params := range result.ResumeParams {
+			if params.Error != nil && *params.Error != "" {
+				failed[helpers.Deref(params.RunID)] = *params.Error
+			}
+		}
+	}
+	var remaining []string
+	var errs []error
+	for _, id := range group.PausedRuns {
+		if message, ok := failed[id]; ok {
+			remaining = append(remaining, id)
+			errs = append(errs, fmt.Errorf("resuming run '%s' of group '%s': %s", id, group.ID, message))
+		}
+	}
+	group.PausedRuns = remaining
+	return errors.Join(errs...)
+}
+
+// waitIdle polls the runs of the window's groups until none is in flight.
+func (coordinator *Coordinator) waitIdle(ctx context.Context, state *State) error {
+	deadline := coordinator.Now().Add(coordinator.IdleTimeout)
+	for {
+		var busy []string
+		for _, group := range state.Groups {
+			running, err := coordinator.runningRuns(ctx, group.ID)
+			if err != nil {
+				return err
+			}
+			if len(running) > 0 {
+				busy = append(busy, group.ID)
+			}
+		}
+		if len(busy) == 0 {
+			return nil
+		}
+		if !coordinator.Now().Before(deadline) {
+			return fmt.Errorf("groups %v still running after %s", busy, coordinator.IdleTimeout)
+		}
+		timer := time.NewTimer(coordinator.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (coordinator *Coordinator) save(state *State) error {
+	if err := coordinator.file.Save(state); err != nil {
+		return fmt.Errorf("saving state '%s': %w", coordinator.file.Path, err)
+	}
+	return nil
+}
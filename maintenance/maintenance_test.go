@@ -0,0 +1,230 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	groups map[string]*backuprecoveryv1.ProtectionGroupResponse
+	// running lists the in-flight runs of each group.
+	running map[string][]string
+	// finishAfter empties the running runs of a group after that many listings.
+	finishAfter map[string]int
+	rejected    map[string]bool
+	actions     []string
+	catchUps    []string
+	// failRuns fails the next that many catch-up runs.
+	failRuns int
+}
+
+func newFakeClient() *fakeClient {
+	fake := &fakeClient{groups: map[string]*backuprecoveryv1.ProtectionGroupResponse{}, running: map[string][]string{}, finishAfter: map[string]int{}, rejected: map[string]bool{}}
+	for _, id := range []string{"g1", "g2", "g3"} {
+		fake.groups[id] = &backuprecoveryv1.ProtectionGroupResponse{
+			ID:          core.StringPtr(id),
+			Name:        core.StringPtr(id),
+			PolicyID:    core.StringPtr("policy"),
+			Environment: core.StringPtr("kPhysical"),
+			IsActive:    core.BoolPtr(true),
+			IsPaused:    core.BoolPtr(id == "g2"),
+		}
+	}
+	return fake
+}
+
+func (fake *fakeClient) paused(id string) bool {
+	return *fake.groups[id].IsPaused
+}
+
+func (fake *fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	result := &backuprecoveryv1.ProtectionGroupsResponse{}
+	for _, group := range fake.groups {
+		result.ProtectionGroups = append(result.ProtectionGroups, *group)
+	}
+	return result, nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupByIDWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupByIdOptions) (*backuprecoveryv1.ProtectionGroupResponse, *core.DetailedResponse, error) {
+	group := *fake.groups[*options.ID]
+	return &group, nil, nil
+}
+
+func (fake *fakeClient) UpdateProtectionGroupWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionGroupOptions) (*backuprecoveryv1.ProtectionGroupResponse, *core.DetailedResponse, error) {
+	if fake.rejected[*options.ID] {
+		return nil, nil, errors.New("rejected")
+	}
+	if *options.PolicyID != "policy" || *options.Environment != "kPhysical" {
+		return nil, nil, errors.New("incomplete update")
+	}
+	fake.groups[*options.ID].IsPaused = options.IsPaused
+	return fake.groups[*options.ID], nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	id := *options.ID
+	if remaining, ok := fake.finishAfter[id]; ok {
+		if remaining == 0 {
+			fake.running[id] = nil
+		}
+		fake.finishAfter[id] = remaining - 1
+	}
+	result := &backuprecoveryv1.ProtectionGroupRunsResponse{}
+	for _, run := range fake.running[id] {
+		result.Runs = append(result.Runs, backuprecoveryv1.ProtectionGroupRun{ID: core.StringPtr(run)})
+	}
+	return result, nil, nil
+}
+
+func (fake *fakeClient) PerformActionOnProtectionGroupRunWithContext(ctx context.Context, options *backuprecoveryv1.PerformActionOnProtectionGroupRunOptions) (*backuprecoveryv1.PerformRunActionResponse, *core.DetailedResponse, error) {
+	for _, params := range options.PauseParams {
+		fake.actions = append(fake.actions, "Pause "+*params.RunID)
+	}
+	for _, params := range options.CancelParams {
+		fake.actions = append(fake.actions, "Cancel "+*params.RunID)
+	}
+	for _, params := range options.ResumeParams {
+		fake.actions = append(fake.actions, "Resume "+*params.RunID)
+	}
+	if *options.Action != backuprecoveryv1.PerformActionOnProtectionGroupRunOptions_Action_Resume {
+		fake.running[*options.ID] = nil
+	}
+	return &backuprecoveryv1.PerformRunActionResponse{}, nil, nil
+}
+
+func (fake *fakeClient) CreateProtectionGroupRunWithContext(ctx context.Context, options *backuprecoveryv1.CreateProtectionGroupRunOptions) (*backuprecoveryv1.CreateProtectionGroupRunResponse, *core.DetailedResponse, error) {
+	if fake.failRuns > 0 {
+		fake.failRuns--
+		return nil, nil, errors.New("unavailable")
+	}
+	fake.catchUps = append(fake.catchUps, *options.ID+":"+*options.RunType)
+	return &backuprecoveryv1.CreateProtectionGroupRunResponse{ProtectionGroupID: options.ID}, nil, nil
+}
+
+func newCoordinator(t *testing.T, client *fakeClient, path string) *Coordinator {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "window.json")
+	}
+	coordinator := NewCoordinator(client, "tenant", path)
+	coordinator.PollInterval = time.Millisecond
+	coordinator.Now = func() time.Time { return base }
+	return coordinator
+}
+
+func TestQuiesceAndResume(t *testing.T) {
+	client := newFakeClient()
+	client.running["g1"] = []string{"r1"}
+	coordinator := newCoordinator(t, client, "")
+
+	state, err := coordinator.Quiesce(context.Background(), QuiesceOptions{Reason: "storage upgrade", RunAction: RunActionPause})
+	require.Nil(t, err)
+	assert.Equal(t, PhaseQuiesced, state.Phase)
+	assert.True(t, client.paused("g1"))
+	assert.True(t, client.paused("g3"))
+	assert.Equal(t, []string{"Pause r1"}, client.actions)
+
+	_, err = coordinator.Quiesce(context.Background(), QuiesceOptions{})
+	assert.Equal(t, ErrWindowOpen, err)
+
+	state, err = coordinator.Resume(context.Background(), ResumeOptions{CatchUp: true})
+	require.Nil(t, err)
+	assert.Equal(t, PhaseResumed, state.Phase)
+	assert.False(t, client.paused("g1"))
+	assert.False(t, client.paused("g3"))
+	// g2 was paused before the window and stays paused.
+	assert.True(t, client.paused("g2"))
+	assert.Equal(t, []string{"Pause r1", "Resume r1"}, client.actions)
+	sort.Strings(client.catchUps)
+	assert.Equal(t, []string{"g1:kRegular", "g3:kRegular"}, client.catchUps)
+
+	// A resumed window can be followed by a new one.
+	_, err = coordinator.Quiesce(context.Background(), QuiesceOptions{GroupIDs: []string{"g1"}})
+	require.Nil(t, err)
+}
+
+func TestResumeAfterCrash(t *testing.T) {
+	client := newFakeClient()
+	client.rejected["g3"] = true
+	path := filepath.Join(t.TempDir(), "window.json")
+
+	_, err := newCoordinator(t, client, path).Quiesce(context.Background(), QuiesceOptions{})
+	require.NotNil(t, err)
+	assert.True(t, client.paused("g1"))
+	assert.False(t, client.paused("g3"))
+
+	// A new coordinator restores the original state from the state file alone.
+	state, err := newCoordinator(t, client, path).Resume(context.Background(), ResumeOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, PhaseResumed, state.Phase)
+	assert.False(t, client.paused("g1"))
+	assert.True(t, client.paused("g2"))
+	assert.False(t, client.paused("g3"))
+	assert.Empty(t, client.catchUps)
+}
+
+func TestResumeRetriesCatchUpRun(t *testing.T) {
+	client := newFakeClient()
+	coordinator := newCoordinator(t, client, "")
+	_, err := coordinator.Quiesce(context.Background(), QuiesceOptions{})
+	require.Nil(t, err)
+
+	// The catch-up run of g1 fails; g3 gets its run and is not started again.
+	client.failRuns = 1
+	state, err := coordinator.Resume(context.Background(), ResumeOptions{CatchUp: true})
+	assert.ErrorContains(t, err, "starting catch-up run of group 'g1'")
+	assert.Equal(t, PhaseResuming, state.Phase)
+	assert.False(t, client.paused("g1"))
+
+	state, err = coordinator.Resume(context.Background(), ResumeOptions{CatchUp: true})
+	require.Nil(t, err)
+	assert.Equal(t, PhaseResumed, state.Phase)
+	assert.Equal(t, []string{"g3:kRegular", "g1:kRegular"}, client.catchUps)
+}
+
+func TestQuiesceWaitsForRunningRuns(t *testing.T) {
+	client := newFakeClient()
+	client.running["g3"] = []string{"r3"}
+	client.finishAfter["g3"] = 3
+	coordinator := newCoordinator(t, client, "")
+
+	state, err := coordinator.Quiesce(context.Background(), QuiesceOptions{})
+	require.Nil(t, err)
+	assert.Equal(t, PhaseQuiesced, state.Phase)
+	assert.Empty(t, client.actions)
+	assert.Empty(t, client.running["g3"])
+
+	client.running["g1"] = []string{"r1"}
+	coordinator.IdleTimeout = 0
+	_, err = coordinator.Resume(context.Background(), ResumeOptions{})
+	require.Nil(t, err)
+	_, err = coordinator.Quiesce(context.Background(), QuiesceOptions{})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "still running")
+}
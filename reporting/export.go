@@ -0,0 +1,309 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+)
+
+// ExportClient is the subset of the management reporting API used to export reports.
+type ExportClient interface {
+	ExportReportWithContext(ctx context.Context, exportReportOptions *backuprecoveryv1.ExportReportOptions) (result io.ReadCloser, response *core.DetailedResponse, err error)
+}
+
+var _ ExportClient = (*backuprecoveryv1.BackupRecoveryManagementReportingApiV1)(nil)
+
+// The formats of exports. The export API only declares CSV and XLS; PDF and XLSX, like any other format, are passed
+// on to the service as is.
+const (
+	FormatCSV  = backuprecoveryv1.ExportReportOptions_ReportFormat_Csv
+	FormatXLS  = backuprecoveryv1.ExportReportOptions_ReportFormat_Xls
+	FormatXLSX = "XLSX"
+	FormatPDF  = "PDF"
+)
+
+// extensions are the file extensions of the known formats.
+var extensions = map[string]string{
+	FormatCSV:  ".csv",
+	FormatXLS:  ".xls",
+	FormatXLSX: ".xlsx",
+	FormatPDF:  ".pdf",
+}
+
+// ExportRequest describes a report export.
+type ExportRequest struct {
+	ReportID string `json:"reportId"`
+
+	// Format is the format of the artifact. Defaults to FormatCSV.
+	Format string `json:"format,omitempty"`
+
+	Layout string `json:"layout,omitempty"`
+
+	// Timezone is an IANA location name such as 'America/Los_Angeles'. The service defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	Filters []backuprecoveryv1.AttributeFilter `json:"filters,omitempty"`
+}
+
+// IdempotencyKeyHeader is the header that carries the key shared by the requests of an ExportJob.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+func (request ExportRequest) options(key string) *backuprecoveryv1.ExportReportOptions {
+	options := &backuprecoveryv1.ExportReportOptions{
+		ID:           core.StringPtr(request.ReportID),
+		Async:        core.BoolPtr(true),
+		Filters:      request.Filters,
+		ReportFormat: core.StringPtr(request.format()),
+		Headers:      map[string]string{IdempotencyKeyHeader: key},
+	}
+	if request.Layout != "" {
+		options.Layout = core.StringPtr(request.Layout)
+	}
+	if request.Timezone != "" {
+		options.Timezone = core.StringPtr(request.Timezone)
+	}
+	return options
+}
+
+func (request ExportRequest) format() string {
+	if request.Format == "" {
+		return FormatCSV
+	}
+	return request.Format
+}
+
+// ExportResult describes an exported artifact.
+type ExportResult struct {
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType,omitempty"`
+	Size        int64     `json:"size"`
+	Polls       int       `json:"polls"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+}
+
+// ErrExportTimeout is returned by Wait when the artifact was not ready within the Timeout of the job.
+var ErrExportTimeout = errors.New("report export timed out")
+
+// ExportJob exports a report and waits until the service returned the artifact.
+//
+// The export API offers nothing to track an export by: it has no status endpoint, and a 202 Accepted answer carries
+// no job ID or location. The job therefore requests the export with Async set, and requests it again every
+// PollInterval while the service answers 202 Accepted, or 429 or 503, until the artifact is returned. All requests
+// of a job carry the same IdempotencyKeyHeader, so that the service can answer them from the export the first one
+// started rather than generating the report again; a service that ignores the key generates it for every request.
+type ExportJob struct {
+	client  ExportClient
+	request ExportRequest
+
+	// PollInterval is the delay between requests while the artifact is generated. A Retry-After header in seconds
+	// overrides it. Defaults to 15 seconds.
+	PollInterval time.Duration
+
+	// Timeout bounds the wait for the artifact. Defaults to one hour.
+	Timeout time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	key        string
+	startedAt  time.Time
+	polls      int
+	retryAfter time.Duration
+	body       io.ReadCloser
+	response   *core.DetailedResponse
+}
+
+// NewExportJob : Instantiate ExportJob
+func NewExportJob(client ExportClient, request ExportRequest) *ExportJob {
+	return &ExportJob{
+		client:       client,
+		request:      request,
+		key:          rand.Text(),
+		PollInterval: 15 * time.Second,
+		Timeout:      time.Hour,
+		Now:          time.Now,
+	}
+}
+
+// Start requests the export. The artifact may be ready right away.
+func (job *ExportJob) Start(ctx context.Context) error {
+	if job.request.ReportID == "" {
+		return errors.New("report ID is required")
+	}
+	job.startedAt = job.Now()
+	return job.poll(ctx)
+}
+
+// Ready reports whether the artifact is ready to be written.
+func (job *ExportJob) Ready() bool {
+	return job.body != nil
+}
+
+// Wait polls until the artifact is ready. It starts the job when needed.
+func (job *ExportJob) Wait(ctx context.Context) error {
+	if job.startedAt.IsZero() {
+		if err := job.Start(ctx); err != nil {
+			return err
+		}
+	}
+	deadline := job.startedAt.Add(job.Timeout)
+	for !job.Ready() {
+		if !job.Now().Before(deadline) {
+			return fmt.Errorf("exporting report '%s': %w after %s", job.request.ReportID, ErrExportTimeout, job.Timeout)
+		}
+		delay := job.PollInterval
+		if job.retryAfter > 0 {
+			delay = job.retryAfter
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if err := job.poll(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (job *ExportJob) poll(ctx context.Context) error {
+	job.polls++
+	body, response, err := job.client.ExportReportWithContext(ctx, job.request.options(job.key))
+	job.retryAfter = 0
+	if response != nil && response.Headers != nil {
+		if seconds, parseErr := strconv.Atoi(response.Headers.Get("Retry-After")); parseErr == nil && seconds > 0 {
+			job.retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	status := 0
+	if response != nil {
+		status = response.StatusCode
+	}
+	pending := status == http.StatusAccepted || status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+	if err != nil && !pending {
+		return fmt.Errorf("exporting report '%s': %w", job.request.ReportID, err)
+	}
+	if pending || body == nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil
+	}
+	job.body, job.response = body, response
+	return nil
+}
+
+// Stream streams the ready artifact to writer and releases it.
+func (job *ExportJob) Stream(writer io.Writer) (*ExportResult, error) {
+	if !job.Ready() {
+		return nil, errors.New("export is not ready")
+	}
+	defer func() {
+		job.body.Close()
+		job.body = nil
+	}()
+	size, err := io.Copy(writer, job.body)
+	if err != nil {
+		return nil, fmt.Errorf("streaming export of report '%s': %w", job.request.ReportID, err)
+	}
+	result := &ExportResult{
+		FileName:   job.FileName(),
+		Size:       size,
+		Polls:      job.polls,
+		StartedAt:  job.startedAt,
+		FinishedAt: job.Now(),
+	}
+	if job.response.Headers != nil {
+		result.ContentType = job.response.Headers.Get("Content-Type")
+	}
+	return result, nil
+}
+
+// Run starts the job, waits for the artifact and streams it to writer.
+func (job *ExportJob) Run(ctx context.Context, writer io.Writer) (*ExportResult, error) {
+	if err := job.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return job.Stream(writer)
+}
+
+// SaveTo runs the job and writes the artifact to dir under the file name sent by the service. The file only appears
+// once it is complete. It returns the path of the file.
+func (job *ExportJob) SaveTo(ctx context.Context, dir string) (string, *ExportResult, error) {
+	if err := job.Wait(ctx); err != nil {
+		return "", nil, err
+	}
+	temp, err := os.CreateTemp(dir, ".export-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(temp.Name())
+	result, err := job.Stream(temp)
+	if err != nil {
+		temp.Close()
+		return "", nil, err
+	}
+	if err := temp.Close(); err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, result.FileName)
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return "", nil, err
+	}
+	return path, result, nil
+}
+
+// FileName returns the file name from the Content-Disposition header of the ready artifact, or one derived from the
+// request. A sent name that is not a plain local file name, such as '..' or a hidden name, is ignored. The extension
+// of a derived name follows the format, or the Content-Type of the artifact for formats without a known extension.
+func (job *ExportJob) FileName() string {
+	extension := extensions[strings.ToUpper(job.request.format())]
+	if job.response != nil && job.response.Headers != nil {
+		if _, params, err := mime.ParseMediaType(job.response.Headers.Get("Content-Disposition")); err == nil {
+			if name := filepath.Base(params["filename"]); filepath.IsLocal(name) && !strings.HasPrefix(name, ".") {
+				return name
+			}
+		}
+		if contentType, _, err := mime.ParseMediaType(job.response.Headers.Get("Content-Type")); err == nil && extension == "" {
+			if known, err := mime.ExtensionsByType(contentType); err == nil && len(known) > 0 {
+				extension = known[0]
+			}
+		}
+	}
+	if extension == "" {
+		extension = ".bin"
+	}
+	return fmt.Sprintf("report-%s-%s%s", job.request.ReportID, job.startedAt.UTC().Format("20060102T150405Z"), extension)
+}
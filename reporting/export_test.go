@@ -0,0 +1,176 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExportClient struct {
+	// pending is the number of requests answered with 202 before the artifact is returned.
+	pending  int
+	requests []*backuprecoveryv1.ExportReportOptions
+	headers  http.Header
+	err      error
+}
+
+func (client *fakeExportClient) ExportReportWithContext(ctx context.Context, options *backuprecoveryv1.ExportReportOptions) (io.ReadCloser, *core.DetailedResponse, error) {
+	client.requests = append(client.requests, options)
+	if client.err != nil {
+		return nil, &core.DetailedResponse{StatusCode: 400}, client.err
+	}
+	if len(client.requests) <= client.pending {
+		return io.NopCloser(strings.NewReader("")), &core.DetailedResponse{StatusCode: 202, Headers: http.Header{}}, nil
+	}
+	return io.NopCloser(strings.NewReader("cluster,runs\nprod,12\n")), &core.DetailedResponse{StatusCode: 200, Headers: client.headers}, nil
+}
+
+func newExportJob(client ExportClient, request ExportRequest) *ExportJob {
+	job := NewExportJob(client, request)
+	job.PollInterval = time.Millisecond
+	job.Now = func() time.Time { return base }
+	return job
+}
+
+func TestExportJobRun(t *testing.T) {
+	client := &fakeExportClient{pending: 2, headers: http.Header{
+		"Content-Disposition": []string{`attachment; filename="Monthly runs.csv"`},
+		"Content-Type":        []string{"text/csv"},
+	}}
+	filters, err := Build(Where("environment").In("kVMware"))
+	require.Nil(t, err)
+	job := newExportJob(client, ExportRequest{ReportID: "r1", Timezone: "Europe/Berlin", Filters: filters})
+
+	var output bytes.Buffer
+	result, err := job.Run(context.Background(), &output)
+	require.Nil(t, err)
+	assert.Equal(t, "cluster,runs\nprod,12\n", output.String())
+	assert.Equal(t, &ExportResult{FileName: "Monthly runs.csv", ContentType: "text/csv", Size: 21, Polls: 3, StartedAt: base, FinishedAt: base}, result)
+	require.Len(t, client.requests, 3)
+	options := client.requests[0]
+	assert.True(t, *options.Async)
+	// The polls repeat the first request under the same key.
+	key := options.Headers[IdempotencyKeyHeader]
+	assert.NotEmpty(t, key)
+	for _, request := range client.requests[1:] {
+		assert.Equal(t, key, request.Headers[IdempotencyKeyHeader])
+	}
+	assert.NotEqual(t, key, newExportJob(client, ExportRequest{ReportID: "r1"}).key)
+	assert.Equal(t, "CSV", *options.ReportFormat)
+	assert.Equal(t, "Europe/Berlin", *options.Timezone)
+	assert.Equal(t, filters, options.Filters)
+	assert.False(t, job.Ready())
+}
+
+func TestExportJobSaveTo(t *testing.T) {
+	client := &fakeExportClient{}
+	job := newExportJob(client, ExportRequest{ReportID: "r1", Format: FormatXLS})
+
+	require.Nil(t, job.Start(context.Background()))
+	assert.True(t, job.Ready())
+	dir := t.TempDir()
+	path, result, err := job.SaveTo(context.Background(), dir)
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "report-r1-20260601T120000Z.xls"), path)
+	assert.Equal(t, int64(21), result.Size)
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, "cluster,runs\nprod,12\n", string(content))
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestExportJobFileName(t *testing.T) {
+	cases := []struct {
+		format      string
+		contentType string
+		name        string
+	}{
+		{FormatCSV, "", "report-r1-20260601T120000Z.csv"},
+		{FormatXLSX, "", "report-r1-20260601T120000Z.xlsx"},
+		{FormatPDF, "application/octet-stream", "report-r1-20260601T120000Z.pdf"},
+		{"pdf", "", "report-r1-20260601T120000Z.pdf"},
+		{"JSON", "application/json; charset=utf-8", "report-r1-20260601T120000Z.json"},
+		{"HTML/ZIP", "", "report-r1-20260601T120000Z.bin"},
+	}
+	for _, c := range cases {
+		headers := http.Header{}
+		if c.contentType != "" {
+			headers.Set("Content-Type", c.contentType)
+		}
+		client := &fakeExportClient{headers: headers}
+		job := newExportJob(client, ExportRequest{ReportID: "r1", Format: c.format})
+		require.Nil(t, job.Start(context.Background()))
+		assert.Equal(t, c.name, job.FileName(), c.format)
+		assert.Equal(t, c.format, *client.requests[0].ReportFormat)
+	}
+}
+
+func TestExportJobFileNameFromDisposition(t *testing.T) {
+	cases := map[string]string{
+		`attachment; filename="runs.csv"`:         "runs.csv",
+		`attachment; filename="../../etc/cron.d"`: "cron.d",
+		`attachment; filename=".."`:               "report-r1-20260601T120000Z.csv",
+		`attachment; filename="/"`:                "report-r1-20260601T120000Z.csv",
+		`attachment; filename=".export-1"`:        "report-r1-20260601T120000Z.csv",
+		`attachment`:                              "report-r1-20260601T120000Z.csv",
+	}
+	for disposition, name := range cases {
+		client := &fakeExportClient{headers: http.Header{"Content-Disposition": []string{disposition}}}
+		job := newExportJob(client, ExportRequest{ReportID: "r1"})
+		require.Nil(t, job.Start(context.Background()))
+		assert.Equal(t, name, job.FileName(), disposition)
+	}
+}
+
+func TestExportJobTimeout(t *testing.T) {
+	client := &fakeExportClient{pending: 1000}
+	job := newExportJob(client, ExportRequest{ReportID: "r1"})
+	now := base
+	job.Now = func() time.Time {
+		now = now.Add(10 * time.Minute)
+		return now
+	}
+
+	_, err := job.Run(context.Background(), io.Discard)
+	assert.True(t, errors.Is(err, ErrExportTimeout))
+	assert.Len(t, client.requests, 6)
+}
+
+func TestExportJobFailure(t *testing.T) {
+	client := &fakeExportClient{err: errors.New("report not found")}
+	_, err := newExportJob(client, ExportRequest{ReportID: "r1"}).Run(context.Background(), io.Discard)
+	assert.EqualError(t, err, "exporting report 'r1': report not found")
+
+	_, err = newExportJob(client, ExportRequest{}).Run(context.Background(), io.Discard)
+	assert.EqualError(t, err, "report ID is required")
+}
@@ -0,0 +1,437 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package reporting builds, runs and decodes reports of the management reporting API.
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// SchemaClient is the subset of the management reporting API used to load schemas.
+type SchemaClient interface {
+	GetReportTypeWithContext(ctx context.Context, getReportTypeOptions *backuprecoveryv1.GetReportTypeOptions) (result *backuprecoveryv1.ReportTypeAttributes, response *core.DetailedResponse, err error)
+}
+
+var _ SchemaClient = (*backuprecoveryv1.BackupRecoveryManagementReportingApiV1)(nil)
+
+// MaxTimeRange is the longest time range the service accepts in a filter.
+const MaxTimeRange = 60 * 24 * time.Hour
+
+// Filter is a filter on one attribute of a report, built with Where.
+type Filter struct {
+	attribute  string
+	filterType string
+	values     []any
+	labels     []string
+	lower      *int64
+	upper      *int64
+	dateRange  string
+	duration   int64
+	systemIDs  []string
+	kind       Kind
+	names      []string
+	err        error
+}
+
+// Attribute returns the name of the filtered attribute.
+func (filter Filter) Attribute() string {
+	return filter.attribute
+}
+
+// WithLabels sets the labels of the values of an In filter, or the names of the systems of a Systems filter.
+func (filter Filter) WithLabels(labels ...string) Filter {
+	filter.labels = labels
+	return filter
+}
+
+// Condition starts a filter on an attribute.
+type Condition struct {
+	attribute string
+}
+
+// Where starts a filter on an attribute, e.g. Where("environment").In("kVMware", "kPhysical").
+func Where(attribute string) Condition {
+	return Condition{attribute: attribute}
+}
+
+// In keeps the rows whose attribute is one of values. Values are strings, bools or integers.
+func (condition Condition) In(values ...any) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_In, values: values}
+	if len(values) == 0 {
+		filter.err = errors.New("no values")
+	}
+	return filter
+}
+
+// Range keeps the rows whose attribute is between lower and upper.
+func (condition Condition) Range(lower int64, upper int64) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Range, lower: &lower, upper: &upper}
+	if lower > upper {
+		filter.err = fmt.Errorf("lower bound %d is above upper bound %d", lower, upper)
+	}
+	return filter
+}
+
+// AtLeast keeps the rows whose attribute is at least lower.
+func (condition Condition) AtLeast(lower int64) Filter {
+	return Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Range, lower: &lower}
+}
+
+// AtMost keeps the rows whose attribute is at most upper.
+func (condition Condition) AtMost(upper int64) Filter {
+	return Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Range, upper: &upper}
+}
+
+// Between keeps the rows whose time attribute is between from and to.
+func (condition Condition) Between(from time.Time, to time.Time) Filter {
+	lower, upper := from.UnixMicro(), to.UnixMicro()
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Timerange, lower: &lower, upper: &upper}
+	switch {
+	case to.Before(from):
+		filter.err = fmt.Errorf("time range ends at %s before it starts at %s", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	case to.Sub(from) > MaxTimeRange:
+		filter.err = fmt.Errorf("time range of %s exceeds the maximum of %s", to.Sub(from), MaxTimeRange)
+	}
+	return filter
+}
+
+// Last keeps the rows whose time attribute is within duration before now. The duration is rounded up to hours.
+func (condition Condition) Last(duration time.Duration) Filter {
+	hours := int64((duration + time.Hour - 1) / time.Hour)
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Timerange, duration: hours}
+	switch {
+	case hours <= 0:
+		filter.err = fmt.Errorf("duration %s is not positive", duration)
+	case time.Duration(hours)*time.Hour > MaxTimeRange:
+		filter.err = fmt.Errorf("duration %s exceeds the maximum of %s", duration, MaxTimeRange)
+	}
+	return filter
+}
+
+// During keeps the rows whose time attribute is within a named date range, e.g.
+// backuprecoveryv1.TimeRangeFilterParams_DateRange_Last7days.
+func (condition Condition) During(dateRange string) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Timerange, dateRange: dateRange}
+	if dateRange == "" {
+		filter.err = errors.New("no date range")
+	}
+	return filter
+}
+
+// Systems keeps the rows of the systems with the given IDs.
+func (condition Condition) Systems(ids ...string) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Systems, systemIDs: ids}
+	if len(ids) == 0 {
+		filter.err = errors.New("no system IDs")
+	}
+	return filter
+}
+
+// SystemsNamed keeps the rows of the systems with the given names. The names are resolved to IDs by Resolver.Build.
+func (condition Condition) SystemsNamed(names ...string) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_Systems, kind: KindSystem, names: names}
+	if len(names) == 0 {
+		filter.err = errors.New("no system names")
+	}
+	return filter
+}
+
+// InNamed keeps the rows whose attribute is the ID of one of the named resources of a kind, e.g.
+// Where("groupId").InNamed(KindProtectionGroup, "Daily VMs"). The names are resolved to IDs by Resolver.Build.
+func (condition Condition) InNamed(kind Kind, names ...string) Filter {
+	filter := Filter{attribute: condition.attribute, filterType: backuprecoveryv1.AttributeFilter_FilterType_In, kind: kind, names: names}
+	if len(names) == 0 {
+		filter.err = errors.New("no names")
+	}
+	return filter
+}
+
+// FilterError reports an invalid filter.
+type FilterError struct {
+	Attribute string
+	Err       error
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("filter on '%s': %v", e.Attribute, e.Err)
+}
+
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// Build converts filters without validating them against a schema. The data type of In filters is inferred from
+// their values.
+func Build(filters ...Filter) ([]backuprecoveryv1.AttributeFilter, error) {
+	return build(nil, filters)
+}
+
+// Schema holds the attributes of a report type and their data types.
+type Schema struct {
+	ReportType string
+	attributes map[string]string
+}
+
+// NewSchema : Instantiate Schema
+func NewSchema(reportType string, attributes []backuprecoveryv1.ReportTypeAttribute) *Schema {
+	schema := &Schema{ReportType: reportType, attributes: map[string]string{}}
+	for _, attribute := range attributes {
+		if attribute.Name != nil {
+			schema.attributes[*attribute.Name] = helpers.Deref(attribute.DataType)
+		}
+	}
+	return schema
+}
+
+// LoadSchema reads the attributes of a report type, e.g. backuprecoveryv1.GetReportTypeOptions_ReportType_Protectionruns.
+func LoadSchema(ctx context.Context, client SchemaClient, reportType string) (*Schema, error) {
+	result, _, err := client.GetReportTypeWithContext(ctx, &backuprecoveryv1.GetReportTypeOptions{ReportType: core.StringPtr(reportType)})
+	if err != nil {
+		return nil, fmt.Errorf("getting report type '%s': %w", reportType, err)
+	}
+	var attributes []backuprecoveryv1.ReportTypeAttribute
+	if result != nil {
+		attributes = result.Attributes
+	}
+	return NewSchema(reportType, attributes), nil
+}
+
+// DataType returns the data type of an attribute, and whether the report type has it.
+func (schema *Schema) DataType(attribute string) (string, bool) {
+	dataType, ok := schema.attributes[attribute]
+	return dataType, ok
+}
+
+// Attributes returns the sorted names of the attributes.
+func (schema *Schema) Attributes() []string {
+	names := make([]string, 0, len(schema.attributes))
+	for name := range schema.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build validates filters against the schema and converts them. Unknown attributes, filters that do not apply to the
+// data type of their attribute, and values of the wrong type are reported as a *FilterError.
+func (schema *Schema) Build(filters ...Filter) ([]backuprecoveryv1.AttributeFilter, error) {
+	return build(schema, filters)
+}
+
+func build(schema *Schema, filters []Filter) ([]backuprecoveryv1.AttributeFilter, error) {
+	result := make([]backuprecoveryv1.AttributeFilter, 0, len(filters))
+	var failures []error
+	for _, filter := range filters {
+		converted, err := filter.convert(schema)
+		if err != nil {
+			failures = append(failures, &FilterError{Attribute: filter.attribute, Err: err})
+			continue
+		}
+		result = append(result, converted)
+	}
+	if len(failures) > 0 {
+		return nil, errors.Join(failures...)
+	}
+	return result, nil
+}
+
+func (filter Filter) convert(schema *Schema) (backuprecoveryv1.AttributeFilter, error) {
+	converted := backuprecoveryv1.AttributeFilter{
+		Attribute:  core.StringPtr(filter.attribute),
+		FilterType: core.StringPtr(filter.filterType),
+	}
+	if filter.attribute == "" {
+		return converted, errors.New("no attribute")
+	}
+	if filter.err != nil {
+		return converted, filter.err
+	}
+	if len(filter.names) > 0 {
+		return converted, fmt.Errorf("%s names are not resolved, build the filter with a Resolver", filter.kind)
+	}
+	dataType := ""
+	if schema != nil {
+		var ok bool
+		if dataType, ok = schema.attributes[filter.attribute]; !ok {
+			message := fmt.Sprintf("report type '%s' has no such attribute", schema.ReportType)
+			if suggestion := closest(filter.attribute, schema.Attributes()); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			}
+			return converted, errors.New(message)
+		}
+		if allowed := allowedDataTypes[filter.filterType]; !slices.Contains(allowed, dataType) {
+			return converted, fmt.Errorf("%s filter does not apply to %s attributes", filter.filterType, dataType)
+		}
+	}
+
+	switch filter.filterType {
+	case backuprecoveryv1.AttributeFilter_FilterType_In:
+		params, err := inParams(dataType, filter.values)
+		if err != nil {
+			return converted, err
+		}
+		params.AttributeLabels = filter.labels
+		converted.InFilterParams = params
+	case backuprecoveryv1.AttributeFilter_FilterType_Range:
+		converted.RangeFilterParams = &backuprecoveryv1.RangeFilterParams{LowerBound: filter.lower, UpperBound: filter.upper}
+	case backuprecoveryv1.AttributeFilter_FilterType_Timerange:
+		params := &backuprecoveryv1.TimeRangeFilterParams{LowerBound: filter.lower, UpperBound: filter.upper}
+		if filter.dateRange != "" {
+			params.DateRange = core.StringPtr(filter.dateRange)
+		}
+		if filter.duration > 0 {
+			params.DurationHours = core.Int64Ptr(filter.duration)
+		}
+		converted.TimeRangeFilterParams = params
+	case backuprecoveryv1.AttributeFilter_FilterType_Systems:
+		converted.SystemsFilterParams = &backuprecoveryv1.SystemsFilterParams{SystemIds: filter.systemIDs, SystemNames: filter.labels}
+	}
+	return converted, nil
+}
+
+// allowedDataTypes lists the data types of the attributes each filter type applies to.
+var allowedDataTypes = map[string][]string{
+	backuprecoveryv1.AttributeFilter_FilterType_In: {
+		backuprecoveryv1.ReportTypeAttribute_DataType_Bool,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int32,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int64,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int64array,
+		backuprecoveryv1.ReportTypeAttribute_DataType_String,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Stringarray,
+	},
+	backuprecoveryv1.AttributeFilter_FilterType_Range: {
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int32,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int64,
+		backuprecoveryv1.ReportTypeAttribute_DataType_Float64,
+	},
+	backuprecoveryv1.AttributeFilter_FilterType_Timerange: {
+		backuprecoveryv1.ReportTypeAttribute_DataType_Int64,
+	},
+	backuprecoveryv1.AttributeFilter_FilterType_Systems: {
+		backuprecoveryv1.ReportTypeAttribute_DataType_String,
+	},
+}
+
+// inParams converts the values of an In filter to the slice of dataType, or infers the data type from the values
+// when dataType is empty.
+func inParams(dataType string, values []any) (*backuprecoveryv1.InFilterParams, error) {
+	if dataType == "" {
+		switch values[0].(type) {
+		case string:
+			dataType = backuprecoveryv1.InFilterParams_AttributeDataType_String
+		case bool:
+			dataType = backuprecoveryv1.InFilterParams_AttributeDataType_Bool
+		default:
+			dataType = backuprecoveryv1.InFilterParams_AttributeDataType_Int64
+		}
+	}
+	params := &backuprecoveryv1.InFilterParams{AttributeDataType: core.StringPtr(dataType)}
+	for _, value := range values {
+		var ok bool
+		switch dataType {
+		case backuprecoveryv1.InFilterParams_AttributeDataType_String, backuprecoveryv1.InFilterParams_AttributeDataType_Stringarray:
+			var converted string
+			if converted, ok = value.(string); ok {
+				params.StringFilterValues = append(params.StringFilterValues, converted)
+			}
+		case backuprecoveryv1.InFilterParams_AttributeDataType_Bool:
+			var converted bool
+			if converted, ok = value.(bool); ok {
+				params.BoolFilterValues = append(params.BoolFilterValues, converted)
+			}
+		case backuprecoveryv1.InFilterParams_AttributeDataType_Int32:
+			var converted int64
+			if converted, ok = toInt64(value); ok && converted == int64(int32(converted)) {
+				params.Int32FilterValues = append(params.Int32FilterValues, converted)
+			} else {
+				ok = false
+			}
+		case backuprecoveryv1.InFilterParams_AttributeDataType_Int64, backuprecoveryv1.InFilterParams_AttributeDataType_Int64array:
+			var converted int64
+			if converted, ok = toInt64(value); ok {
+				params.Int64FilterValues = append(params.Int64FilterValues, converted)
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("value %v (%T) is not a valid %s", value, value, dataType)
+		}
+	}
+	return params, nil
+}
+
+func toInt64(value any) (int64, bool) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), true
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	case uint8:
+		return int64(value), true
+	case uint16:
+		return int64(value), true
+	case uint32:
+		return int64(value), true
+	}
+	return 0, false
+}
+
+// closest returns the candidate nearest to name, ignoring case, or empty when none is close.
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,126 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeSchemaClient struct{}
+
+func (client *fakeSchemaClient) GetReportTypeWithContext(ctx context.Context, options *backuprecoveryv1.GetReportTypeOptions) (*backuprecoveryv1.ReportTypeAttributes, *core.DetailedResponse, error) {
+	if *options.ReportType != backuprecoveryv1.GetReportTypeOptions_ReportType_Protectionruns {
+		return nil, &core.DetailedResponse{StatusCode: 404}, errors.New("not found")
+	}
+	attribute := func(name string, dataType string) backuprecoveryv1.ReportTypeAttribute {
+		return backuprecoveryv1.ReportTypeAttribute{Name: core.StringPtr(name), DataType: core.StringPtr(dataType)}
+	}
+	return &backuprecoveryv1.ReportTypeAttributes{Attributes: []backuprecoveryv1.ReportTypeAttribute{
+		attribute("environment", backuprecoveryv1.ReportTypeAttribute_DataType_String),
+		attribute("runStartTimeUsecs", backuprecoveryv1.ReportTypeAttribute_DataType_Int64),
+		attribute("systemId", backuprecoveryv1.ReportTypeAttribute_DataType_String),
+		attribute("snapshotsCount", backuprecoveryv1.ReportTypeAttribute_DataType_Int32),
+		attribute("isSlaViolated", backuprecoveryv1.ReportTypeAttribute_DataType_Bool),
+		attribute("dataReadBytes", backuprecoveryv1.ReportTypeAttribute_DataType_Float64),
+	}}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func loadTestSchema(t *testing.T) *Schema {
+	schema, err := LoadSchema(context.Background(), &fakeSchemaClient{}, backuprecoveryv1.GetReportTypeOptions_ReportType_Protectionruns)
+	require.Nil(t, err)
+	return schema
+}
+
+func TestSchemaBuild(t *testing.T) {
+	schema := loadTestSchema(t)
+	filters, err := schema.Build(
+		Where("environment").In("kVMware", "kPhysical"),
+		Where("runStartTimeUsecs").Between(base.Add(-7*24*time.Hour), base),
+		Where("systemId").Systems("1:2", "3:4").WithLabels("prod", "dr"),
+		Where("snapshotsCount").In(1, int64(2)),
+		Where("isSlaViolated").In(true),
+		Where("dataReadBytes").AtLeast(1024),
+		Where("runStartTimeUsecs").Last(90*time.Minute),
+	)
+	require.Nil(t, err)
+	require.Len(t, filters, 7)
+
+	assert.Equal(t, "In", *filters[0].FilterType)
+	assert.Equal(t, "String", *filters[0].InFilterParams.AttributeDataType)
+	assert.Equal(t, []string{"kVMware", "kPhysical"}, filters[0].InFilterParams.StringFilterValues)
+	assert.Equal(t, base.UnixMicro(), *filters[1].TimeRangeFilterParams.UpperBound)
+	assert.Equal(t, []string{"1:2", "3:4"}, filters[2].SystemsFilterParams.SystemIds)
+	assert.Equal(t, []string{"prod", "dr"}, filters[2].SystemsFilterParams.SystemNames)
+	assert.Equal(t, []int64{1, 2}, filters[3].InFilterParams.Int32FilterValues)
+	assert.Equal(t, []bool{true}, filters[4].InFilterParams.BoolFilterValues)
+	assert.Nil(t, filters[5].RangeFilterParams.UpperBound)
+	assert.Equal(t, int64(2), *filters[6].TimeRangeFilterParams.DurationHours)
+}
+
+func TestSchemaBuildRejectsInvalidFilters(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	_, err := schema.Build(Where("enviroment").In("kVMware"))
+	var filterErr *FilterError
+	require.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "enviroment", filterErr.Attribute)
+	assert.EqualError(t, err, "filter on 'enviroment': report type 'ProtectionRuns' has no such attribute (did you mean 'environment'?)")
+
+	_, err = schema.Build(
+		Where("environment").Between(base, base.Add(time.Hour)),
+		Where("snapshotsCount").In("one"),
+		Where("snapshotsCount").In(int64(1)<<40),
+		Where("runStartTimeUsecs").Between(base.Add(-90*24*time.Hour), base),
+		Where("isSlaViolated").Range(1, 0),
+		Where("systemId").Systems(),
+	)
+	assert.ErrorContains(t, err, "filter on 'environment': TimeRange filter does not apply to String attributes")
+	assert.ErrorContains(t, err, "value one (string) is not a valid Int32")
+	assert.ErrorContains(t, err, "value 1099511627776 (int64) is not a valid Int32")
+	assert.ErrorContains(t, err, "exceeds the maximum of 1440h0m0s")
+	assert.ErrorContains(t, err, "lower bound 1 is above upper bound 0")
+	assert.ErrorContains(t, err, "no system IDs")
+
+	_, err = LoadSchema(context.Background(), &fakeSchemaClient{}, "Unknown")
+	assert.ErrorContains(t, err, "getting report type 'Unknown'")
+}
+
+func TestBuildInfersTypes(t *testing.T) {
+	filters, err := Build(
+		Where("environment").In("kVMware").WithLabels("VMware"),
+		Where("clusterId").In(1, 2),
+		Where("date").During(backuprecoveryv1.TimeRangeFilterParams_DateRange_Last7days),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"VMware"}, filters[0].InFilterParams.AttributeLabels)
+	assert.Equal(t, "Int64", *filters[1].InFilterParams.AttributeDataType)
+	assert.Equal(t, []int64{1, 2}, filters[1].InFilterParams.Int64FilterValues)
+	assert.Equal(t, "Last7Days", *filters[2].TimeRangeFilterParams.DateRange)
+
+	_, err = Build(Where("clusterId").In(1, "2"))
+	assert.ErrorContains(t, err, "value 2 (string) is not a valid Int64")
+}
@@ -0,0 +1,583 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// PreviewClient is the subset of the management reporting API used to preview reports.
+type PreviewClient interface {
+	GetReportPreviewWithContext(ctx context.Context, getReportPreviewOptions *backuprecoveryv1.GetReportPreviewOptions) (result *backuprecoveryv1.ReportPreview, response *core.DetailedResponse, err error)
+	GetComponentPreviewWithContext(ctx context.Context, getComponentPreviewOptions *backuprecoveryv1.GetComponentPreviewOptions) (result *backuprecoveryv1.ComponentPreview, response *core.DetailedResponse, err error)
+}
+
+var _ PreviewClient = (*backuprecoveryv1.BackupRecoveryManagementReportingApiV1)(nil)
+
+// PreviewComponent previews one component and decodes its rows into dst, a pointer to a slice of structs. See
+// DecodeRows for the mapping of rows to structs.
+func PreviewComponent(ctx context.Context, client PreviewClient, options *backuprecoveryv1.GetComponentPreviewOptions, dst any) error {
+	preview, _, err := client.GetComponentPreviewWithContext(ctx, options)
+	if err != nil {
+		return fmt.Errorf("previewing component '%s': %w", helpers.Deref(options.ID), err)
+	}
+	if preview == nil || preview.Component == nil {
+		return DecodeRows(nil, dst)
+	}
+	return DecodeRows(preview.Component.Data, dst)
+}
+
+// PreviewReport previews a report and returns the table of every component, keyed by component ID.
+func PreviewReport(ctx context.Context, client PreviewClient, options *backuprecoveryv1.GetReportPreviewOptions) (map[string]*Table, error) {
+	preview, _, err := client.GetReportPreviewWithContext(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("previewing report '%s': %w", helpers.Deref(options.ID), err)
+	}
+	tables := map[string]*Table{}
+	if preview != nil {
+		for i := range preview.Components {
+			tables[helpers.Deref(preview.Components[i].ID)] = TableFromComponent(&preview.Components[i])
+		}
+	}
+	return tables, nil
+}
+
+// DecodeError reports a value of a row that could not be decoded.
+type DecodeError struct {
+	Row       int
+	Attribute string
+	Err       error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("row %d, attribute '%s': %v", e.Row, e.Attribute, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DecodeRows decodes the data rows of a report component into dst, a pointer to a slice of structs or of pointers
+// to structs. The rows are appended to the slice.
+//
+// Exported fields are mapped to the attribute named by their `report` tag, or else to the attribute whose name
+// equals the field name ignoring case. A tag of "-" skips the field. Values are coerced to the type of the field:
+// numbers and numeric strings to integers and floats, numbers to bools, and any scalar to strings. Integer values
+// decode into time.Time fields as microseconds since the epoch, the unit the service uses for timestamps; the
+// ",msecs" and ",secs" tag options select other units. Duration fields take microseconds likewise. Missing and null
+// attributes leave the field at its zero value, or nil for pointers.
+func DecodeRows(rows []map[string]any, dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("decoding rows into %T: not a pointer to a slice", dst)
+	}
+	slice := target.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("decoding rows into %T: elements are not structs", dst)
+	}
+	fields := structFields(structType)
+
+	var failures []error
+	for i, row := range rows {
+		value := reflect.New(structType).Elem()
+		lowered := map[string]any{}
+		for key, cell := range row {
+			lowered[strings.ToLower(key)] = cell
+		}
+		for _, field := range fields {
+			cell, ok := row[field.attribute]
+			if !ok && !field.tagged {
+				cell, ok = lowered[strings.ToLower(field.attribute)]
+			}
+			if !ok {
+				continue
+			}
+			if err := coerce(value.Field(field.index), cell, field.unit); err != nil {
+				failures = append(failures, &DecodeError{Row: i, Attribute: field.attribute, Err: err})
+			}
+		}
+		if elemType.Kind() == reflect.Pointer {
+			value = value.Addr()
+		}
+		slice = reflect.Append(slice, value)
+	}
+	target.Elem().Set(slice)
+	return errors.Join(failures...)
+}
+
+type structField struct {
+	index     int
+	attribute string
+	tagged    bool
+	unit      time.Duration
+}
+
+func structFields(structType reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, tagged := field.Tag.Lookup("report")
+		if tag == "-" {
+			continue
+		}
+		name, option, _ := strings.Cut(tag, ",")
+		mapped := structField{index: i, attribute: field.Name, tagged: tagged && name != "", unit: time.Microsecond}
+		if mapped.tagged {
+			mapped.attribute = name
+		}
+		switch option {
+		case "msecs":
+			mapped.unit = time.Millisecond
+		case "secs":
+			mapped.unit = time.Second
+		}
+		fields = append(fields, mapped)
+	}
+	return fields
+}
+
+// coerce sets field from a decoded JSON value.
+func coerce(field reflect.Value, cell any, unit time.Duration) error {
+	if cell == nil {
+		field.SetZero()
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := coerce(value.Elem(), cell, unit); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	switch {
+	case field.Type() == timeType:
+		if text, ok := cell.(string); ok {
+			if parsed, err := time.Parse(time.RFC3339Nano, text); err == nil {
+				field.Set(reflect.ValueOf(parsed.UTC()))
+				return nil
+			}
+		}
+		number, err := toInteger(cell)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(time.Unix(0, 0).Add(time.Duration(number) * unit).UTC()))
+		return nil
+	case field.Type() == reflect.TypeOf(time.Duration(0)):
+		number, err := toInteger(cell)
+		if err != nil {
+			return err
+		}
+		field.SetInt(number * int64(unit))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch value := cell.(type) {
+		case string:
+			field.SetString(value)
+		case float64:
+			field.SetString(strconv.FormatFloat(value, 'f', -1, 64))
+		case json.Number:
+			field.SetString(value.String())
+		case bool:
+			field.SetString(strconv.FormatBool(value))
+		default:
+			return fmt.Errorf("cannot decode %T into a string", cell)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := toInteger(cell)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(number) {
+			return fmt.Errorf("%d overflows %s", number, field.Type())
+		}
+		field.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, err := toInteger(cell)
+		if err != nil {
+			return err
+		}
+		if number < 0 || field.OverflowUint(uint64(number)) {
+			return fmt.Errorf("%d overflows %s", number, field.Type())
+		}
+		field.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		number, err := toFloat(cell)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(number)
+	case reflect.Bool:
+		switch value := cell.(type) {
+		case bool:
+			field.SetBool(value)
+		case string:
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("cannot decode %q into a bool", value)
+			}
+			field.SetBool(parsed)
+		default:
+			number, err := toFloat(cell)
+			if err != nil {
+				return err
+			}
+			field.SetBool(number != 0)
+		}
+	case reflect.Slice:
+		items, ok := cell.([]any)
+		if !ok {
+			return fmt.Errorf("cannot decode %T into %s", cell, field.Type())
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := coerce(slice.Index(i), item, unit); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		field.Set(slice)
+	case reflect.Interface:
+		if !reflect.TypeOf(cell).AssignableTo(field.Type()) {
+			return fmt.Errorf("cannot decode %T into %s", cell, field.Type())
+		}
+		field.Set(reflect.ValueOf(cell))
+	default:
+		// Maps and structs take the JSON form of the value.
+		content, err := json.Marshal(cell)
+		if err != nil {
+			return err
+		}
+		pointer := reflect.New(field.Type())
+		if err := json.Unmarshal(content, pointer.Interface()); err != nil {
+			return fmt.Errorf("cannot decode %T into %s: %w", cell, field.Type(), err)
+		}
+		field.Set(pointer.Elem())
+	}
+	return nil
+}
+
+func toFloat(cell any) (float64, error) {
+	switch value := cell.(type) {
+	case float64:
+		return value, nil
+	case json.Number:
+		return value.Float64()
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot decode %q into a number", value)
+		}
+		return parsed, nil
+	case int64:
+		return float64(value), nil
+	case int:
+		return float64(value), nil
+	}
+	return 0, fmt.Errorf("cannot decode %T into a number", cell)
+}
+
+func toInteger(cell any) (int64, error) {
+	switch value := cell.(type) {
+	case int64:
+		return value, nil
+	case int:
+		return int64(value), nil
+	case json.Number:
+		if parsed, err := value.Int64(); err == nil {
+			return parsed, nil
+		}
+	case string:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed, nil
+		}
+	}
+	number, err := toFloat(cell)
+	if err != nil {
+		return 0, err
+	}
+	if number != math.Trunc(number) || number >= 1<<63 || number < -1<<63 {
+		return 0, fmt.Errorf("%v is not an integer", cell)
+	}
+	return int64(number), nil
+}
+
+// ColumnType is the type of the cells of a column.
+type ColumnType string
+
+// The values of ColumnType. Cells are nil, or of the Go type in parentheses.
+const (
+	ColumnString ColumnType = "string" // (string)
+	ColumnInt    ColumnType = "int"    // (int64)
+	ColumnFloat  ColumnType = "float"  // (float64)
+	ColumnBool   ColumnType = "bool"   // (bool)
+	ColumnTime   ColumnType = "time"   // (time.Time)
+	ColumnJSON   ColumnType = "json"   // (any decoded JSON value)
+)
+
+// Column is a column of a Table.
+type Column struct {
+	Name  string     `json:"name"`
+	Label string     `json:"label,omitempty"`
+	Type  ColumnType `json:"type"`
+}
+
+// Table is the data of a report component as typed columns and cells.
+type Table struct {
+	Columns []Column
+	Rows    [][]any
+}
+
+// NewTable builds a table from data rows. The columns listed come first and in order, followed by the other
+// attributes sorted by name. Column types are inferred from the values: attributes named like *Usecs or *Msecs with
+// integer values are times.
+func NewTable(rows []map[string]any, columns ...string) *Table {
+	table := &Table{}
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range columns {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	var others []string
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				others = append(others, name)
+			}
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	for _, name := range names {
+		column := Column{Name: name, Type: inferType(rows, name)}
+		table.Columns = append(table.Columns, column)
+	}
+	for _, row := range rows {
+		cells := make([]any, len(table.Columns))
+		for i, column := range table.Columns {
+			cells[i] = cell(column, row[column.Name])
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+// TableFromComponent builds a table from the data of a component. Columns follow the grouped attributes, the
+// aggregated attributes and the configured columns of the component, and configured labels are used.
+func TableFromComponent(component *backuprecoveryv1.Component) *Table {
+	var preferred []string
+	labels := map[string]string{}
+	if component.Aggs != nil {
+		preferred = append(preferred, component.Aggs.GroupedAttributes...)
+		for _, aggregated := range component.Aggs.AggregatedAttributes {
+			if aggregated.Label != nil {
+				preferred = append(preferred, *aggregated.Label)
+			} else if aggregated.Attribute != nil {
+				preferred = append(preferred, *aggregated.Attribute)
+			}
+		}
+	}
+	if component.Config != nil && component.Config.XlsxParams != nil {
+		for _, config := range component.Config.XlsxParams.AttributeConfig {
+			preferred = append(preferred, helpers.Deref(config.AttributeName))
+			if config.CustomLabel != nil {
+				labels[helpers.Deref(config.AttributeName)] = *config.CustomLabel
+			}
+		}
+	}
+	present := map[string]bool{}
+	for _, row := range component.Data {
+		for name := range row {
+			present[name] = true
+		}
+	}
+	var columns []string
+	for _, name := range preferred {
+		if present[name] {
+			columns = append(columns, name)
+		}
+	}
+	table := NewTable(component.Data, columns...)
+	for i := range table.Columns {
+		table.Columns[i].Label = labels[table.Columns[i].Name]
+	}
+	return table
+}
+
+func inferType(rows []map[string]any, name string) ColumnType {
+	var inferred ColumnType
+	for _, row := range rows {
+		var current ColumnType
+		switch value := row[name].(type) {
+		case nil:
+			continue
+		case string:
+			current = ColumnString
+		case bool:
+			current = ColumnBool
+		case float64, json.Number, int, int64:
+			current = ColumnFloat
+			if _, err := toInteger(value); err == nil {
+				current = ColumnInt
+			}
+		default:
+			current = ColumnJSON
+		}
+		switch {
+		case inferred == "" || inferred == current:
+			inferred = current
+		case (inferred == ColumnInt && current == ColumnFloat) || (inferred == ColumnFloat && current == ColumnInt):
+			inferred = ColumnFloat
+		default:
+			return ColumnJSON
+		}
+	}
+	if inferred == "" {
+		return ColumnString
+	}
+	if inferred == ColumnInt && timeUnit(name) != 0 {
+		return ColumnTime
+	}
+	return inferred
+}
+
+// timeUnit returns the unit of a timestamp attribute, or zero when the name does not denote one.
+func timeUnit(name string) time.Duration {
+	switch {
+	case strings.HasSuffix(name, "Usecs"):
+		return time.Microsecond
+	case strings.HasSuffix(name, "Msecs"):
+		return time.Millisecond
+	}
+	return 0
+}
+
+func cell(column Column, value any) any {
+	if value == nil {
+		return nil
+	}
+	switch column.Type {
+	case ColumnInt:
+		number, _ := toInteger(value)
+		return number
+	case ColumnFloat:
+		number, _ := toFloat(value)
+		return number
+	case ColumnTime:
+		number, _ := toInteger(value)
+		return time.Unix(0, 0).Add(time.Duration(number) * timeUnit(column.Name)).UTC()
+	}
+	return value
+}
+
+// WriteCSV writes the table as CSV with a header of column labels, or names when unlabeled. Times are written in
+// RFC 3339, null cells as empty fields, and JSON cells in their JSON form.
+func (table *Table) WriteCSV(writer io.Writer) error {
+	output := csv.NewWriter(writer)
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = column.Name
+		if column.Label != "" {
+			header[i] = column.Label
+		}
+	}
+	if err := output.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			text, err := formatCell(value)
+			if err != nil {
+				return fmt.Errorf("column '%s': %w", table.Columns[i].Name, err)
+			}
+			record[i] = text
+		}
+		if err := output.Write(record); err != nil {
+			return err
+		}
+	}
+	output.Flush()
+	return output.Error()
+}
+
+// WriteJSONLines writes one JSON object per row, keyed by column name. Times are written in RFC 3339.
+func (table *Table) WriteJSONLines(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	for _, row := range table.Rows {
+		object := make(map[string]any, len(row))
+		for i, value := range row {
+			object[table.Columns[i].Name] = value
+		}
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatCell(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	}
+	content, err := json.Marshal(value)
+	return string(content), err
+}
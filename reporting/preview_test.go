@@ -0,0 +1,173 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// previewData is decoded from JSON, so numbers are float64 as they are in responses.
+func previewData(t *testing.T) []map[string]any {
+	var rows []map[string]any
+	require.Nil(t, json.Unmarshal([]byte(`[
+		{"system": "prod", "runs": 12, "dataReadBytes": 1.5e9, "startTimeUsecs": 1780315200000000, "isSlaViolated": false, "tags": ["a", "b"]},
+		{"system": "dr", "runs": "7", "dataReadBytes": 20, "startTimeUsecs": null, "isSlaViolated": 1, "tags": []}
+	]`), &rows))
+	return rows
+}
+
+type runRow struct {
+	System    string
+	Runs      int
+	Bytes     uint64     `report:"dataReadBytes"`
+	Start     time.Time  `report:"startTimeUsecs"`
+	StartPtr  *time.Time `report:"startTimeUsecs"`
+	Violated  bool       `report:"isSlaViolated"`
+	Tags      []string
+	Ignored   string `report:"-"`
+	unmatched string
+}
+
+func TestDecodeRows(t *testing.T) {
+	var rows []runRow
+	require.Nil(t, DecodeRows(previewData(t), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "prod", rows[0].System)
+	assert.Equal(t, 12, rows[0].Runs)
+	assert.Equal(t, uint64(1500000000), rows[0].Bytes)
+	assert.Equal(t, base, rows[0].Start)
+	assert.Equal(t, base, *rows[0].StartPtr)
+	assert.Equal(t, []string{"a", "b"}, rows[0].Tags)
+	assert.Equal(t, 7, rows[1].Runs)
+	assert.True(t, rows[1].Start.IsZero())
+	assert.Nil(t, rows[1].StartPtr)
+	assert.True(t, rows[1].Violated)
+
+	type msecsRow struct {
+		Start *time.Time `report:"startMsecs,msecs"`
+	}
+	var pointers []*msecsRow
+	require.Nil(t, DecodeRows([]map[string]any{{"startMsecs": float64(base.UnixMilli())}}, &pointers))
+	assert.Equal(t, base, *pointers[0].Start)
+}
+
+func TestDecodeRowsErrors(t *testing.T) {
+	type badRow struct {
+		Runs int8   `report:"runs"`
+		Name string `report:"name"`
+	}
+	var rows []badRow
+	err := DecodeRows([]map[string]any{{"runs": 1000.0, "name": "x"}, {"runs": 1.5, "name": map[string]any{}}}, &rows)
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, 0, decodeErr.Row)
+	assert.ErrorContains(t, err, "row 0, attribute 'runs': 1000 overflows int8")
+	assert.ErrorContains(t, err, "row 1, attribute 'runs': 1.5 is not an integer")
+	assert.ErrorContains(t, err, "row 1, attribute 'name': cannot decode map[string]interface {} into a string")
+	assert.Len(t, rows, 2)
+
+	assert.NotNil(t, DecodeRows(nil, rows))
+	assert.NotNil(t, DecodeRows(nil, &[]int{}))
+
+	type edgeRow struct {
+		Count  int64        `report:"count"`
+		Stamp  fmt.Stringer `report:"stamp"`
+		Detail any          `report:"detail"`
+	}
+	var edges []edgeRow
+	err = DecodeRows([]map[string]any{{"count": float64(1 << 63), "stamp": "x", "detail": "y"}}, &edges)
+	assert.ErrorContains(t, err, "attribute 'count': 9.223372036854776e+18 is not an integer")
+	assert.ErrorContains(t, err, "attribute 'stamp': cannot decode string into fmt.Stringer")
+	assert.Equal(t, "y", edges[0].Detail)
+}
+
+func TestTable(t *testing.T) {
+	table := NewTable(previewData(t), "system")
+	assert.Equal(t, []Column{
+		{Name: "system", Type: ColumnString},
+		{Name: "dataReadBytes", Type: ColumnInt},
+		{Name: "isSlaViolated", Type: ColumnJSON},
+		{Name: "runs", Type: ColumnJSON},
+		{Name: "startTimeUsecs", Type: ColumnTime},
+		{Name: "tags", Type: ColumnJSON},
+	}, table.Columns)
+	assert.Equal(t, base, table.Rows[0][4])
+	assert.Nil(t, table.Rows[1][4])
+
+	var output bytes.Buffer
+	require.Nil(t, table.WriteCSV(&output))
+	assert.Equal(t, "system,dataReadBytes,isSlaViolated,runs,startTimeUsecs,tags\n"+
+		"prod,1500000000,false,12,2026-06-01T12:00:00Z,\"[\"\"a\"\",\"\"b\"\"]\"\n"+
+		"dr,20,1,7,,[]\n", output.String())
+
+	output.Reset()
+	require.Nil(t, table.WriteJSONLines(&output))
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"system":"prod","dataReadBytes":1500000000,"isSlaViolated":false,"runs":12,"startTimeUsecs":"2026-06-01T12:00:00Z","tags":["a","b"]}`, string(lines[0]))
+}
+
+type fakePreviewClient struct{}
+
+func (fakePreviewClient) GetReportPreviewWithContext(ctx context.Context, options *backuprecoveryv1.GetReportPreviewOptions) (*backuprecoveryv1.ReportPreview, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ReportPreview{Components: []backuprecoveryv1.Component{{
+		ID: core.StringPtr("c1"),
+		Aggs: &backuprecoveryv1.AttributeAggregations{
+			GroupedAttributes:    []string{"system"},
+			AggregatedAttributes: []backuprecoveryv1.AggregatedAttributesParams{{Attribute: core.StringPtr("runs"), Label: core.StringPtr("runCount")}},
+		},
+		Config: &backuprecoveryv1.CustomConfigParams{XlsxParams: &backuprecoveryv1.XlsxCustomConfigParams{
+			AttributeConfig: []backuprecoveryv1.XlsxAttributeCustomConfigParams{{AttributeName: core.StringPtr("system"), CustomLabel: core.StringPtr("System")}},
+		}},
+		Data: []map[string]any{{"runCount": 3.0, "system": "prod", "other": "x"}},
+	}}}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (fakePreviewClient) GetComponentPreviewWithContext(ctx context.Context, options *backuprecoveryv1.GetComponentPreviewOptions) (*backuprecoveryv1.ComponentPreview, *core.DetailedResponse, error) {
+	preview, _, err := fakePreviewClient{}.GetReportPreviewWithContext(ctx, nil)
+	return &backuprecoveryv1.ComponentPreview{Component: &preview.Components[0]}, &core.DetailedResponse{StatusCode: 200}, err
+}
+
+func TestPreview(t *testing.T) {
+	tables, err := PreviewReport(context.Background(), fakePreviewClient{}, &backuprecoveryv1.GetReportPreviewOptions{ID: core.StringPtr("r1")})
+	require.Nil(t, err)
+	table := tables["c1"]
+	require.NotNil(t, table)
+	assert.Equal(t, []Column{
+		{Name: "system", Label: "System", Type: ColumnString},
+		{Name: "runCount", Type: ColumnInt},
+		{Name: "other", Type: ColumnString},
+	}, table.Columns)
+
+	var rows []struct {
+		System string
+		Runs   int `report:"runCount"`
+	}
+	require.Nil(t, PreviewComponent(context.Background(), fakePreviewClient{}, &backuprecoveryv1.GetComponentPreviewOptions{ID: core.StringPtr("c1")}, &rows))
+	assert.Equal(t, 3, rows[0].Runs)
+}
@@ -0,0 +1,405 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// ResourceClient is the subset of the management reporting API used to discover resources.
+type ResourceClient interface {
+	GetResourcesWithContext(ctx context.Context, getResourcesOptions *backuprecoveryv1.GetResourcesOptions) (result *backuprecoveryv1.Resources, response *core.DetailedResponse, err error)
+	GetProviderInstancesWithContext(ctx context.Context, getProviderInstancesOptions *backuprecoveryv1.GetProviderInstancesOptions) (result *backuprecoveryv1.ProviderInstancesList, response *core.DetailedResponse, err error)
+}
+
+var _ ResourceClient = (*backuprecoveryv1.BackupRecoveryManagementReportingApiV1)(nil)
+
+// ClusterClient is the subset of the management SRE API used to discover systems.
+type ClusterClient interface {
+	GetClustersInfoWithContext(ctx context.Context, getClustersInfoOptions *backuprecoveryv1.GetClustersInfoOptions) (result *backuprecoveryv1.ClusterDetails, response *core.DetailedResponse, err error)
+}
+
+var _ ClusterClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// Kind is the kind of a resource in a directory.
+type Kind string
+
+// The values of Kind.
+const (
+	// KindSystem is a cluster. Its ID has the format clusterid:clusterincarnationid used by systems filters.
+	KindSystem           Kind = "system"
+	KindProviderInstance Kind = "providerInstance"
+	KindProtectionGroup  Kind = "protectionGroup"
+	KindPolicy           Kind = "policy"
+	KindSource           Kind = "source"
+	KindExternalTarget   Kind = "externalTarget"
+	KindTenant           Kind = "tenant"
+)
+
+// Entry is a named resource of a directory. SystemID and SystemName are set for resources that belong to a system.
+type Entry struct {
+	Kind       Kind   `json:"kind"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SystemID   string `json:"systemId,omitempty"`
+	SystemName string `json:"systemName,omitempty"`
+}
+
+// Directory maps the names of resources to their IDs and back.
+type Directory struct {
+	Entries  []Entry   `json:"entries"`
+	LoadedAt time.Time `json:"loadedAt"`
+
+	byName map[Kind]map[string][]int
+	byID   map[Kind]map[string]int
+}
+
+// NewDirectory : Instantiate Directory. Entries with the same kind and ID are merged, the first name winning.
+func NewDirectory(entries []Entry, loadedAt time.Time) *Directory {
+	directory := &Directory{
+		LoadedAt: loadedAt,
+		byName:   map[Kind]map[string][]int{},
+		byID:     map[Kind]map[string]int{},
+	}
+	for _, entry := range entries {
+		if entry.ID == "" {
+			continue
+		}
+		if directory.byID[entry.Kind] == nil {
+			directory.byID[entry.Kind] = map[string]int{}
+			directory.byName[entry.Kind] = map[string][]int{}
+		}
+		if i, ok := directory.byID[entry.Kind][entry.ID]; ok {
+			existing := &directory.Entries[i]
+			if existing.Name == "" && entry.Name != "" {
+				existing.Name = entry.Name
+				directory.byName[entry.Kind][strings.ToLower(entry.Name)] = append(directory.byName[entry.Kind][strings.ToLower(entry.Name)], i)
+			}
+			if existing.SystemID == "" {
+				existing.SystemID, existing.SystemName = entry.SystemID, entry.SystemName
+			}
+			continue
+		}
+		i := len(directory.Entries)
+		directory.Entries = append(directory.Entries, entry)
+		directory.byID[entry.Kind][entry.ID] = i
+		if entry.Name != "" {
+			key := strings.ToLower(entry.Name)
+			directory.byName[entry.Kind][key] = append(directory.byName[entry.Kind][key], i)
+		}
+	}
+	return directory
+}
+
+// Lookup returns the resources of a kind whose name is name, ignoring case. A name that is the ID of a resource
+// returns that resource.
+func (directory *Directory) Lookup(kind Kind, name string) []Entry {
+	if i, ok := directory.byID[kind][name]; ok {
+		return []Entry{directory.Entries[i]}
+	}
+	var entries []Entry
+	for _, i := range directory.byName[kind][strings.ToLower(name)] {
+		entries = append(entries, directory.Entries[i])
+	}
+	return entries
+}
+
+// Name returns the name of the resource of a kind with the given ID.
+func (directory *Directory) Name(kind Kind, id string) (string, bool) {
+	i, ok := directory.byID[kind][id]
+	if !ok {
+		return "", false
+	}
+	return directory.Entries[i].Name, true
+}
+
+// Names returns the names of the resources of a kind, sorted ignoring case.
+func (directory *Directory) Names(kind Kind) []string {
+	names := make([]string, 0, len(directory.byName[kind]))
+	for _, indexes := range directory.byName[kind] {
+		names = append(names, directory.Entries[indexes[0]].Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	return names
+}
+
+// Resolve returns the IDs and names of the named resources of a kind. It fails with a ResolveError for every name
+// that matches no resource or more than one.
+func (directory *Directory) Resolve(kind Kind, names ...string) ([]string, []string, error) {
+	ids := make([]string, 0, len(names))
+	resolved := make([]string, 0, len(names))
+	var failures []error
+	for _, name := range names {
+		entries := directory.Lookup(kind, name)
+		switch len(entries) {
+		case 1:
+			ids = append(ids, entries[0].ID)
+			resolved = append(resolved, entries[0].Name)
+		case 0:
+			failures = append(failures, &ResolveError{Kind: kind, Name: name, Err: ErrNotFound, Suggestion: closest(name, directory.Names(kind))})
+		default:
+			failures = append(failures, &ResolveError{Kind: kind, Name: name, Err: ErrAmbiguous, Candidates: entries})
+		}
+	}
+	if len(failures) > 0 {
+		return nil, nil, errors.Join(failures...)
+	}
+	return ids, resolved, nil
+}
+
+// The errors wrapped by ResolveError.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrAmbiguous = errors.New("ambiguous")
+)
+
+// ResolveError reports a name that could not be resolved. Candidates lists the matches of an ambiguous name and
+// Suggestion the closest name to one that was not found.
+type ResolveError struct {
+	Kind       Kind
+	Name       string
+	Err        error
+	Candidates []Entry
+	Suggestion string
+}
+
+func (e *ResolveError) Error() string {
+	message := fmt.Sprintf("%s '%s': %v", e.Kind, e.Name, e.Err)
+	if e.Suggestion != "" {
+		message += fmt.Sprintf(" (did you mean '%s'?)", e.Suggestion)
+	}
+	if len(e.Candidates) > 0 {
+		ids := make([]string, len(e.Candidates))
+		for i, candidate := range e.Candidates {
+			ids[i] = candidate.ID
+			if candidate.SystemName != "" {
+				ids[i] += " on " + candidate.SystemName
+			}
+		}
+		message += fmt.Sprintf(", use one of the IDs %s", strings.Join(ids, ", "))
+	}
+	return message
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// Resolver resolves the names of resources to the IDs used by report filters. It keeps a directory of the resources
+// of the reporting API and, when a cluster client is given, of the clusters of the SRE API, and reloads it once it
+// is older than TTL.
+type Resolver struct {
+	client   ResourceClient
+	clusters ClusterClient
+
+	// TTL is the age after which the directory is reloaded. Defaults to 15 minutes.
+	TTL time.Duration
+
+	// ResourceTypes are the resource types loaded with GetResources. Defaults to policies, protection groups and
+	// registered sources.
+	ResourceTypes []string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mutex     sync.Mutex
+	directory *Directory
+}
+
+// NewResolver : Instantiate Resolver. The cluster client may be nil, in which case systems are only known by the
+// resources that belong to them.
+func NewResolver(client ResourceClient, clusters ClusterClient) *Resolver {
+	return &Resolver{
+		client:   client,
+		clusters: clusters,
+		TTL:      15 * time.Minute,
+		ResourceTypes: []string{
+			backuprecoveryv1.GetResourcesOptions_ResourceType_Policies,
+			backuprecoveryv1.GetResourcesOptions_ResourceType_Protectiongroups,
+			backuprecoveryv1.GetResourcesOptions_ResourceType_Registeredsources,
+		},
+		Now: time.Now,
+	}
+}
+
+// Directory returns the cached directory, loading it when it is missing or older than TTL.
+func (resolver *Resolver) Directory(ctx context.Context) (*Directory, error) {
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+	if resolver.directory != nil && resolver.Now().Sub(resolver.directory.LoadedAt) < resolver.TTL {
+		return resolver.directory, nil
+	}
+	directory, err := resolver.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolver.directory = directory
+	return directory, nil
+}
+
+// Invalidate drops the cached directory so that the next lookup reloads it.
+func (resolver *Resolver) Invalidate() {
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+	resolver.directory = nil
+}
+
+// Resolve returns the IDs of the named resources of a kind. See Directory.Resolve.
+func (resolver *Resolver) Resolve(ctx context.Context, kind Kind, names ...string) ([]string, error) {
+	directory, err := resolver.Directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, _, err := directory.Resolve(kind, names...)
+	return ids, err
+}
+
+// Build resolves the names of filters built with SystemsNamed and InNamed and converts the filters, validating them
+// against schema when it is not nil. The resolved names become the labels of the filters.
+func (resolver *Resolver) Build(ctx context.Context, schema *Schema, filters ...Filter) ([]backuprecoveryv1.AttributeFilter, error) {
+	resolved := make([]Filter, len(filters))
+	var failures []error
+	for i, filter := range filters {
+		resolved[i] = filter
+		if len(filter.names) == 0 || filter.err != nil {
+			continue
+		}
+		directory, err := resolver.Directory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ids, names, err := directory.Resolve(filter.kind, filter.names...)
+		if err != nil {
+			failures = append(failures, &FilterError{Attribute: filter.attribute, Err: err})
+			continue
+		}
+		resolved[i].names = nil
+		resolved[i].labels = names
+		if filter.filterType == backuprecoveryv1.AttributeFilter_FilterType_Systems {
+			resolved[i].systemIDs = ids
+		} else {
+			resolved[i].values = make([]any, len(ids))
+			for j, id := range ids {
+				resolved[i].values[j] = id
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return nil, errors.Join(failures...)
+	}
+	return build(schema, resolved)
+}
+
+func (resolver *Resolver) load(ctx context.Context) (*Directory, error) {
+	var entries []Entry
+	if resolver.clusters != nil {
+		details, _, err := resolver.clusters.GetClustersInfoWithContext(ctx, &backuprecoveryv1.GetClustersInfoOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("getting clusters: %w", err)
+		}
+		if details != nil {
+			for _, cluster := range details.CohesityClusters {
+				entries = append(entries, Entry{Kind: KindSystem, ID: systemID(cluster.ClusterID, cluster.ClusterIncarnationID), Name: helpers.Deref(cluster.ClusterName)})
+			}
+			for _, cluster := range details.SpClusters {
+				entries = append(entries, Entry{Kind: KindSystem, ID: systemID(cluster.ClusterID, cluster.ClusterIncarnationID), Name: helpers.Deref(cluster.ClusterName)})
+			}
+		}
+	}
+
+	instances, _, err := resolver.client.GetProviderInstancesWithContext(ctx, &backuprecoveryv1.GetProviderInstancesOptions{IncludeServiceInstanceStatus: core.BoolPtr(true)})
+	if err != nil {
+		return nil, fmt.Errorf("getting provider instances: %w", err)
+	}
+	if instances != nil {
+		for _, instance := range instances.IbmServiceInstances {
+			entry := Entry{Kind: KindProviderInstance, ID: helpers.Deref(instance.InstanceID), Name: helpers.Deref(instance.Name)}
+			if instance.ClusterID != nil && instance.ClusterIncarnationID != nil {
+				entry.SystemID = numeric(*instance.ClusterID) + ":" + numeric(*instance.ClusterIncarnationID)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, resourceType := range resolver.ResourceTypes {
+		resources, _, err := resolver.client.GetResourcesWithContext(ctx, &backuprecoveryv1.GetResourcesOptions{ResourceType: core.StringPtr(resourceType)})
+		if err != nil {
+			return nil, fmt.Errorf("getting resources '%s': %w", resourceType, err)
+		}
+		if resources == nil {
+			continue
+		}
+		for _, policy := range resources.Policies {
+			entries = append(entries, Entry{Kind: KindPolicy, ID: helpers.Deref(policy.ID), Name: helpers.Deref(policy.Name), SystemID: helpers.Deref(policy.SystemID), SystemName: helpers.Deref(policy.SystemName)})
+		}
+		for _, group := range resources.ProtectionGroups {
+			entries = append(entries, Entry{Kind: KindProtectionGroup, ID: helpers.Deref(group.ID), Name: helpers.Deref(group.Name), SystemID: helpers.Deref(group.SystemID), SystemName: helpers.Deref(group.SystemName)})
+		}
+		for _, target := range resources.ExternalTargets {
+			entries = append(entries, Entry{Kind: KindExternalTarget, ID: helpers.Deref(target.ID), Name: helpers.Deref(target.Name), SystemID: helpers.Deref(target.SystemID), SystemName: helpers.Deref(target.SystemName)})
+		}
+		for _, source := range resources.Sources {
+			entries = append(entries, Entry{Kind: KindSource, ID: helpers.Deref(source.UUID), Name: helpers.Deref(source.Name)})
+		}
+		for _, tenant := range resources.Tenants {
+			entries = append(entries, Entry{Kind: KindTenant, ID: helpers.Deref(tenant.ID), Name: helpers.Deref(tenant.Name)})
+		}
+	}
+
+	// Systems are also known by the resources that belong to them, which covers systems the SRE API does not list.
+	for _, entry := range entries {
+		if entry.SystemID != "" && entry.SystemName != "" {
+			entries = append(entries, Entry{Kind: KindSystem, ID: entry.SystemID, Name: entry.SystemName})
+		}
+	}
+	return NewDirectory(entries, resolver.Now()), nil
+}
+
+func systemID(clusterID *int64, incarnationID *int64) string {
+	if clusterID == nil || incarnationID == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", *clusterID, *incarnationID)
+}
+
+// numeric formats a number decoded into an interface without an exponent.
+func numeric(value any) string {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	case string:
+		return value
+	}
+	if number, ok := toInt64(value); ok {
+		return strconv.FormatInt(number, 10)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,155 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reporting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResourceClient struct {
+	loads int
+}
+
+func (client *fakeResourceClient) GetResourcesWithContext(ctx context.Context, options *backuprecoveryv1.GetResourcesOptions) (*backuprecoveryv1.Resources, *core.DetailedResponse, error) {
+	resources := &backuprecoveryv1.Resources{ResourceType: options.ResourceType}
+	switch *options.ResourceType {
+	case backuprecoveryv1.GetResourcesOptions_ResourceType_Protectiongroups:
+		resources.ProtectionGroups = []backuprecoveryv1.ProtectionGroup{
+			{ID: core.StringPtr("11:1:5"), Name: core.StringPtr("Daily VMs"), SystemID: core.StringPtr("11:1"), SystemName: core.StringPtr("prod")},
+			{ID: core.StringPtr("22:2:7"), Name: core.StringPtr("Daily VMs"), SystemID: core.StringPtr("22:2"), SystemName: core.StringPtr("dr")},
+			{ID: core.StringPtr("33:3:1"), Name: core.StringPtr("Databases"), SystemID: core.StringPtr("33:3"), SystemName: core.StringPtr("edge")},
+		}
+	case backuprecoveryv1.GetResourcesOptions_ResourceType_Registeredsources:
+		resources.Sources = []backuprecoveryv1.RegisteredSource{{UUID: core.StringPtr("u-1"), Name: core.StringPtr("vcenter.example.com")}}
+	}
+	return resources, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeResourceClient) GetProviderInstancesWithContext(ctx context.Context, options *backuprecoveryv1.GetProviderInstancesOptions) (*backuprecoveryv1.ProviderInstancesList, *core.DetailedResponse, error) {
+	client.loads++
+	var clusterID, incarnationID any = 11.0, 1.0
+	return &backuprecoveryv1.ProviderInstancesList{IbmServiceInstances: []backuprecoveryv1.IbmServiceInstance{
+		{InstanceID: core.StringPtr("i-1"), Name: core.StringPtr("backup-us-south"), ClusterID: &clusterID, ClusterIncarnationID: &incarnationID},
+	}}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+type fakeClusterClient struct {
+	err error
+}
+
+func (client fakeClusterClient) GetClustersInfoWithContext(ctx context.Context, options *backuprecoveryv1.GetClustersInfoOptions) (*backuprecoveryv1.ClusterDetails, *core.DetailedResponse, error) {
+	if client.err != nil {
+		return nil, nil, client.err
+	}
+	return &backuprecoveryv1.ClusterDetails{
+		CohesityClusters: []backuprecoveryv1.ClusterInfo{
+			{ClusterID: core.Int64Ptr(11), ClusterIncarnationID: core.Int64Ptr(1), ClusterName: core.StringPtr("Prod")},
+			{ClusterID: core.Int64Ptr(22), ClusterIncarnationID: core.Int64Ptr(2), ClusterName: core.StringPtr("dr")},
+		},
+		SpClusters: []backuprecoveryv1.SPClusterInfo{{ClusterID: core.Int64Ptr(44), ClusterIncarnationID: core.Int64Ptr(4), ClusterName: core.StringPtr("sp")}},
+	}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func newResolver(client ResourceClient, clusters ClusterClient) *Resolver {
+	resolver := NewResolver(client, clusters)
+	resolver.Now = func() time.Time { return base }
+	return resolver
+}
+
+func TestResolverDirectory(t *testing.T) {
+	client := &fakeResourceClient{}
+	resolver := newResolver(client, fakeClusterClient{})
+	now := base
+	resolver.Now = func() time.Time { return now }
+
+	directory, err := resolver.Directory(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"dr", "edge", "Prod", "sp"}, directory.Names(KindSystem))
+	name, ok := directory.Name(KindSystem, "11:1")
+	assert.True(t, ok)
+	assert.Equal(t, "Prod", name)
+	assert.Equal(t, "11:1", directory.Lookup(KindProviderInstance, "backup-us-south")[0].SystemID)
+	assert.Equal(t, "u-1", directory.Lookup(KindSource, "VCENTER.example.com")[0].ID)
+	assert.Len(t, directory.Lookup(KindProtectionGroup, "daily vms"), 2)
+
+	ids, err := resolver.Resolve(context.Background(), KindSystem, "prod", "edge", "44:4")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"11:1", "33:3", "44:4"}, ids)
+
+	now = now.Add(10 * time.Minute)
+	_, err = resolver.Directory(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, 1, client.loads)
+	now = now.Add(10 * time.Minute)
+	_, err = resolver.Directory(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, 2, client.loads)
+	resolver.Invalidate()
+	_, err = resolver.Directory(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, 3, client.loads)
+}
+
+func TestResolverErrors(t *testing.T) {
+	resolver := newResolver(&fakeResourceClient{}, nil)
+
+	_, err := resolver.Resolve(context.Background(), KindSystem, "prdo")
+	var resolveErr *ResolveError
+	require.ErrorAs(t, err, &resolveErr)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "prod", resolveErr.Suggestion)
+
+	_, err = resolver.Resolve(context.Background(), KindProtectionGroup, "Daily VMs")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+	assert.Contains(t, err.Error(), "11:1:5 on prod")
+
+	_, err = newResolver(&fakeResourceClient{}, fakeClusterClient{err: errors.New("forbidden")}).Directory(context.Background())
+	assert.ErrorContains(t, err, "forbidden")
+}
+
+func TestResolverBuild(t *testing.T) {
+	resolver := newResolver(&fakeResourceClient{}, fakeClusterClient{})
+
+	filters, err := resolver.Build(context.Background(), nil,
+		Where("system").SystemsNamed("prod", "DR"),
+		Where("groupId").InNamed(KindProtectionGroup, "Databases"),
+		Where("status").In("kSuccess"),
+	)
+	require.Nil(t, err)
+	require.Len(t, filters, 3)
+	assert.Equal(t, []string{"11:1", "22:2"}, filters[0].SystemsFilterParams.SystemIds)
+	assert.Equal(t, []string{"Prod", "dr"}, filters[0].SystemsFilterParams.SystemNames)
+	assert.Equal(t, []string{"33:3:1"}, filters[1].InFilterParams.StringFilterValues)
+	assert.Equal(t, []string{"Databases"}, filters[1].InFilterParams.AttributeLabels)
+
+	_, err = resolver.Build(context.Background(), nil, Where("system").SystemsNamed("nowhere"))
+	var filterErr *FilterError
+	require.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, "system", filterErr.Attribute)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = Build(Where("system").SystemsNamed("prod"))
+	assert.ErrorContains(t, err, "not resolved")
+}
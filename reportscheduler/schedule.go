@@ -0,0 +1,181 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reportscheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	expression string
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	// anyDay and anyWeekday record unrestricted fields. When both the day of month and the day of week are restricted,
+	// a time matches either, as in cron.
+	anyDay     bool
+	anyWeekday bool
+	location   *time.Location
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+var weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// ParseSchedule parses a cron expression of five fields (minute, hour, day of month, month, day of week) or one of
+// the macros @yearly, @monthly, @weekly, @daily and @hourly. Fields take *, values, ranges, lists and steps, and
+// months and days of week also take their three letter English names. Times are evaluated in location, or in UTC
+// when it is nil.
+func ParseSchedule(expression string, location *time.Location) (*Schedule, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	fields := strings.Fields(expression)
+	if len(fields) == 1 {
+		if expanded, ok := macros[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(expanded)
+		}
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule '%s': want 5 fields, found %d", expression, len(fields))
+	}
+	schedule := &Schedule{expression: expression, location: location}
+	var err error
+	parse := func(field string, low int, high int, names []string, target *uint64) {
+		if err == nil {
+			*target, err = parseField(field, low, high, names)
+		}
+	}
+	parse(fields[0], 0, 59, nil, &schedule.minutes)
+	parse(fields[1], 0, 23, nil, &schedule.hours)
+	parse(fields[2], 1, 31, nil, &schedule.days)
+	parse(fields[3], 1, 12, monthNames, &schedule.months)
+	parse(fields[4], 0, 7, weekdayNames, &schedule.weekdays)
+	if err != nil {
+		return nil, fmt.Errorf("schedule '%s': %w", expression, err)
+	}
+	// Sunday is both 0 and 7.
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+	schedule.anyDay = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	schedule.anyWeekday = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+	return schedule, nil
+}
+
+func (schedule *Schedule) String() string {
+	return schedule.expression
+}
+
+// parseField returns the bit set of the values of a field.
+func parseField(field string, low int, high int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s'", stepPart)
+			}
+		}
+		first, last := low, high
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if first, err = fieldValue(from, low, high, names); err != nil {
+				return 0, err
+			}
+			last = first
+			if isRange {
+				if last, err = fieldValue(to, low, high, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				last = high
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		}
+		for value := first; value <= last; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func fieldValue(text string, low int, high int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(text, name) {
+			// Month names start at 1, day names at 0.
+			return i + low, nil
+		}
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < low || value > high {
+		return 0, fmt.Errorf("value '%s' is not between %d and %d", text, low, high)
+	}
+	return value, nil
+}
+
+// Next returns the first time after after that matches the schedule, or the zero time when there is none within
+// five years.
+func (schedule *Schedule) Next(after time.Time) time.Time {
+	t := after.In(schedule.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case schedule.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, schedule.location)
+		case !schedule.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, schedule.location)
+		case schedule.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, schedule.location)
+		case schedule.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (schedule *Schedule) dayMatches(t time.Time) bool {
+	day := schedule.days&(1<<uint(t.Day())) != 0
+	weekday := schedule.weekdays&(1<<uint(t.Weekday())) != 0
+	if !schedule.anyDay && !schedule.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
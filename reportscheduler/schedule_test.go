@@ -0,0 +1,60 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reportscheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func TestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.Nil(t, err)
+	cases := []struct {
+		expression string
+		location   *time.Location
+		after      time.Time
+		next       time.Time
+	}{
+		{"*/15 * * * *", time.UTC, base, base.Add(15 * time.Minute)},
+		{"0 6 * * mon-fri", time.UTC, base, time.Date(2026, time.June, 2, 6, 0, 0, 0, time.UTC)},
+		{"@daily", time.UTC, base, time.Date(2026, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{"30 2 1 jan *", time.UTC, base, time.Date(2027, time.January, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 8 * * *", berlin, base, time.Date(2026, time.June, 2, 6, 0, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted: June 5th 2026 is a Friday, June 7th a Sunday.
+		{"0 0 5 * 0", time.UTC, base, time.Date(2026, time.June, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.UTC, base, time.Date(2026, time.June, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.UTC, base, time.Time{}},
+	}
+	for _, c := range cases {
+		schedule, err := ParseSchedule(c.expression, c.location)
+		require.Nil(t, err, c.expression)
+		assert.True(t, c.next.Equal(schedule.Next(c.after)), "%s: %s", c.expression, schedule.Next(c.after))
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * * foo *", "*/0 * * * *", "5-1 * * * *", "@often"} {
+		_, err := ParseSchedule(expression, time.UTC)
+		assert.NotNil(t, err, expression)
+	}
+}
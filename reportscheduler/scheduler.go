@@ -0,0 +1,613 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package reportscheduler runs report definitions on cron schedules and keeps versioned outputs and a run history.
+package reportscheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/reporting"
+	"sigs.k8s.io/yaml"
+)
+
+// Client is the subset of the management reporting API used by this package.
+type Client interface {
+	reporting.PreviewClient
+	reporting.ExportClient
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryManagementReportingApiV1)(nil)
+
+// The modes of a definition.
+const (
+	// ModeExport exports the report with ExportReport.
+	ModeExport = "export"
+	// ModePreview previews the report with GetReportPreview.
+	ModePreview = "preview"
+)
+
+// The formats of previews.
+const (
+	// PreviewJSON writes the whole preview as one JSON file.
+	PreviewJSON = "json"
+	// PreviewCSV writes one CSV file per component.
+	PreviewCSV = "csv"
+	// PreviewJSONLines writes one JSON Lines file per component.
+	PreviewJSONLines = "jsonl"
+)
+
+// Definition describes a scheduled report.
+type Definition struct {
+	// Name identifies the definition. It names the directory of its outputs, so it is limited to letters, digits,
+	// dots, dashes and underscores.
+	Name string `json:"name"`
+
+	// Schedule is a cron expression, see ParseSchedule. It is evaluated in Timezone.
+	Schedule string `json:"schedule"`
+
+	ReportID string `json:"reportId"`
+
+	// Mode is ModeExport or ModePreview. Defaults to ModeExport.
+	Mode string `json:"mode,omitempty"`
+
+	// ComponentIDs restricts a preview to some components.
+	ComponentIDs []string `json:"componentIds,omitempty"`
+
+	Filters []backuprecoveryv1.AttributeFilter `json:"filters,omitempty"`
+
+	// Timezone is an IANA location name such as 'Europe/Berlin'. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Format is the report format of exports, e.g. reporting.FormatCSV, or one of PreviewJSON, PreviewCSV and
+	// PreviewJSONLines for previews. Defaults to CSV for exports and JSON for previews.
+	Format string `json:"format,omitempty"`
+
+	// Layout is the layout of exports.
+	Layout string `json:"layout,omitempty"`
+
+	// Retention is the number of past runs whose outputs are kept. All are kept when zero.
+	Retention int `json:"retention,omitempty"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Validate checks the definition and returns its parsed schedule.
+func (definition Definition) Validate() (*Schedule, error) {
+	if !namePattern.MatchString(definition.Name) || strings.Trim(definition.Name, ".") == "" {
+		return nil, fmt.Errorf("definition name '%s' is not valid", definition.Name)
+	}
+	if definition.ReportID == "" {
+		return nil, fmt.Errorf("definition '%s': report ID is required", definition.Name)
+	}
+	switch definition.mode() {
+	case ModeExport:
+		if len(definition.ComponentIDs) > 0 {
+			return nil, fmt.Errorf("definition '%s': component IDs only apply to previews", definition.Name)
+		}
+	case ModePreview:
+		switch definition.format() {
+		case PreviewJSON, PreviewCSV, PreviewJSONLines:
+		default:
+			return nil, fmt.Errorf("definition '%s': unknown preview format '%s'", definition.Name, definition.Format)
+		}
+	default:
+		return nil, fmt.Errorf("definition '%s': unknown mode '%s'", definition.Name, definition.Mode)
+	}
+	location := time.UTC
+	if definition.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(definition.Timezone); err != nil {
+			return nil, fmt.Errorf("definition '%s': %w", definition.Name, err)
+		}
+	}
+	schedule, err := ParseSchedule(definition.Schedule, location)
+	if err != nil {
+		return nil, fmt.Errorf("definition '%s': %w", definition.Name, err)
+	}
+	return schedule, nil
+}
+
+func (definition Definition) mode() string {
+	if definition.Mode == "" {
+		return ModeExport
+	}
+	return definition.Mode
+}
+
+func (definition Definition) format() string {
+	switch {
+	case definition.Format != "":
+		return definition.Format
+	case definition.mode() == ModePreview:
+		return PreviewJSON
+	}
+	return reporting.FormatCSV
+}
+
+// Config is the file of definitions read by the report scheduler.
+type Config struct {
+	Definitions []Definition `json:"definitions"`
+}
+
+// ParseConfig decodes and validates a configuration in YAML or JSON. Unknown fields are rejected.
+func ParseConfig(content []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(content, config); err != nil {
+		return nil, fmt.Errorf("decoding configuration: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, definition := range config.Definitions {
+		if _, err := definition.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[definition.Name] {
+			return nil, fmt.Errorf("definition '%s' is listed twice", definition.Name)
+		}
+		seen[definition.Name] = true
+	}
+	return config, nil
+}
+
+// LoadConfig reads the configuration in the file at path.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(content)
+	if err != nil {
+		return nil, fmt.Errorf("loading '%s': %w", path, err)
+	}
+	return config, nil
+}
+
+// RunStatus is the outcome of a run.
+type RunStatus string
+
+// The values of RunStatus.
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// Run is the record of one run of a definition.
+type Run struct {
+	Definition string `json:"definition"`
+
+	// Version is the scheduled time to the second followed by a random suffix, e.g. 20260601T120000Z-k3vq7m2a.
+	// Runs scheduled for the same second, e.g. by two schedulers sharing a sink, thus never share the files of a
+	// version, and a failed run only discards its own.
+	Version     string    `json:"version"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+	Attempts    int       `json:"attempts"`
+	Status      RunStatus `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Outputs     []Output  `json:"outputs,omitempty"`
+}
+
+// History is the record of past runs, oldest first.
+type History struct {
+	Runs []Run `json:"runs"`
+}
+
+// Scheduler runs report definitions on their schedules.
+type Scheduler struct {
+	client      Client
+	sink        Sink
+	file        helpers.JSONFile[History]
+	definitions map[string]Definition
+	schedules   map[string]*Schedule
+
+	// MaxAttempts is the number of attempts of a run before it fails. Defaults to 3.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the second attempt of a run. It doubles for every further attempt. Defaults to
+	// one minute.
+	RetryDelay time.Duration
+
+	// ExportTimeout bounds the wait for the artifact of an export. Defaults to one hour.
+	ExportTimeout time.Duration
+
+	// ExportPollInterval is the delay between the requests of an export while the artifact is generated. Defaults to
+	// 15 seconds.
+	ExportPollInterval time.Duration
+
+	// HistoryLimit is the number of runs kept in the history per definition. Defaults to 100.
+	HistoryLimit int
+
+	// OnRun, when set, is called after every run.
+	OnRun func(run Run)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mutex   sync.Mutex
+	running map[string]bool
+}
+
+// NewScheduler : Instantiate Scheduler. Outputs are stored in sink and the history in the file at historyPath. It
+// fails when a definition is not valid.
+func NewScheduler(client Client, sink Sink, historyPath string, definitions []Definition) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		client:             client,
+		sink:               sink,
+		file:               helpers.JSONFile[History]{Path: historyPath},
+		definitions:        map[string]Definition{},
+		schedules:          map[string]*Schedule{},
+		MaxAttempts:        3,
+		RetryDelay:         time.Minute,
+		ExportTimeout:      time.Hour,
+		ExportPollInterval: 15 * time.Second,
+		HistoryLimit:       100,
+		Now:                time.Now,
+		running:            map[string]bool{},
+	}
+	for _, definition := range definitions {
+		schedule, err := definition.Validate()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := scheduler.definitions[definition.Name]; ok {
+			return nil, fmt.Errorf("definition '%s' is listed twice", definition.Name)
+		}
+		scheduler.definitions[definition.Name] = definition
+		scheduler.schedules[definition.Name] = schedule
+	}
+	return scheduler, nil
+}
+
+// History returns the recorded runs of a definition, oldest first, or of all definitions when name is empty.
+func (scheduler *Scheduler) History(name string) ([]Run, error) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	history, err := scheduler.file.Load()
+	if err != nil || history == nil {
+		return nil, err
+	}
+	var runs []Run
+	for _, run := range history.Runs {
+		if name == "" || run.Definition == name {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// Next returns the next scheduled time of every definition after after.
+func (scheduler *Scheduler) Next(after time.Time) map[string]time.Time {
+	next := map[string]time.Time{}
+	for name, schedule := range scheduler.schedules {
+		next[name] = schedule.Next(after)
+	}
+	return next
+}
+
+// Run runs the definitions on their schedules until the context is cancelled, then waits for the runs in progress.
+// A run that is still in progress when its definition is due again is not started twice; runs missed while the
+// scheduler was not running are not caught up.
+func (scheduler *Scheduler) Run(ctx context.Context) error {
+	var group sync.WaitGroup
+	defer group.Wait()
+	next := scheduler.Next(scheduler.Now())
+	for {
+		var earliest time.Time
+		for _, due := range next {
+			if !due.IsZero() && (earliest.IsZero() || due.Before(earliest)) {
+				earliest = due
+			}
+		}
+		if earliest.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		timer := time.NewTimer(earliest.Sub(scheduler.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		now := scheduler.Now()
+		names := make([]string, 0, len(next))
+		for name := range next {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			due := next[name]
+			if due.IsZero() || due.After(now) {
+				continue
+			}
+			next[name] = scheduler.schedules[name].Next(now)
+			group.Add(1)
+			go func() {
+				defer group.Done()
+				scheduler.run(ctx, name, due)
+			}()
+		}
+	}
+}
+
+// RunNow runs a definition once, right away.
+func (scheduler *Scheduler) RunNow(ctx context.Context, name string) (*Run, error) {
+	if _, ok := scheduler.definitions[name]; !ok {
+		return nil, fmt.Errorf("no definition named '%s'", name)
+	}
+	run := scheduler.run(ctx, name, scheduler.Now())
+	if run == nil {
+		return nil, fmt.Errorf("definition '%s' is already running", name)
+	}
+	if run.Status == RunFailed {
+		return run, errors.New(run.Error)
+	}
+	return run, nil
+}
+
+// run runs a definition with retries, records the run and applies the retention. It returns nil when the definition
+// is already running.
+func (scheduler *Scheduler) run(ctx context.Context, name string, scheduledAt time.Time) *Run {
+	scheduler.mutex.Lock()
+	if scheduler.running[name] {
+		scheduler.mutex.Unlock()
+		return nil
+	}
+	scheduler.running[name] = true
+	scheduler.mutex.Unlock()
+	defer func() {
+		scheduler.mutex.Lock()
+		delete(scheduler.running, name)
+		scheduler.mutex.Unlock()
+	}()
+
+	definition := scheduler.definitions[name]
+	run := &Run{
+		Definition:  name,
+		Version:     version(scheduledAt),
+		ScheduledAt: scheduledAt,
+		StartedAt:   scheduler.Now(),
+	}
+	attempts := max(1, scheduler.MaxAttempts)
+	delay := scheduler.RetryDelay
+	var err error
+	for run.Attempts < attempts {
+		run.Attempts++
+		var outputs []Output
+		outputs, err = scheduler.generate(ctx, definition, run.Version)
+		if err == nil {
+			run.Outputs = outputs
+			break
+		}
+		scheduler.discard(ctx, outputs)
+		if run.Attempts == attempts || ctx.Err() != nil {
+			break
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+	run.FinishedAt = scheduler.Now()
+	run.Status = RunSucceeded
+	if err != nil {
+		run.Status, run.Error = RunFailed, err.Error()
+	} else if retentionErr := scheduler.applyRetention(ctx, definition); retentionErr != nil {
+		run.Error = retentionErr.Error()
+	}
+	if err := scheduler.record(*run); err != nil && run.Error == "" {
+		run.Error = err.Error()
+	}
+	if scheduler.OnRun != nil {
+		scheduler.OnRun(*run)
+	}
+	return run
+}
+
+// generate produces the outputs of one attempt. The outputs stored before a failure are returned with the error.
+func (scheduler *Scheduler) generate(ctx context.Context, definition Definition, version string) ([]Output, error) {
+	if definition.mode() == ModeExport {
+		output, err := scheduler.export(ctx, definition, version)
+		if output == nil {
+			return nil, err
+		}
+		return []Output{*output}, err
+	}
+
+	options := &backuprecoveryv1.GetReportPreviewOptions{
+		ID:           core.StringPtr(definition.ReportID),
+		ComponentIds: definition.ComponentIDs,
+		Filters:      definition.Filters,
+	}
+	if definition.Timezone != "" {
+		options.Timezone = core.StringPtr(definition.Timezone)
+	}
+	preview, _, err := scheduler.client.GetReportPreviewWithContext(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("previewing report '%s': %w", definition.ReportID, err)
+	}
+	if preview == nil {
+		return nil, fmt.Errorf("previewing report '%s': empty response", definition.ReportID)
+	}
+
+	var outputs []Output
+	put := func(fileName string, content []byte) error {
+		output, err := scheduler.sink.Put(ctx, Output{Definition: definition.Name, Version: version, FileName: fileName}, bytes.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("storing '%s': %w", fileName, err)
+		}
+		outputs = append(outputs, output)
+		return nil
+	}
+	if definition.format() == PreviewJSON {
+		content, err := json.MarshalIndent(preview, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return outputs, put(definition.ReportID+".json", content)
+	}
+	for i := range preview.Components {
+		component := &preview.Components[i]
+		table := reporting.TableFromComponent(component)
+		var content bytes.Buffer
+		write := table.WriteCSV
+		if definition.format() == PreviewJSONLines {
+			write = table.WriteJSONLines
+		}
+		if err := write(&content); err != nil {
+			return outputs, fmt.Errorf("writing component '%s': %w", helpers.Deref(component.ID), err)
+		}
+		name := helpers.Deref(component.ID)
+		if name == "" {
+			name = fmt.Sprintf("component-%d", i+1)
+		}
+		if err := put(filepath.Base(name)+"."+definition.format(), content.Bytes()); err != nil {
+			return outputs, err
+		}
+	}
+	return outputs, nil
+}
+
+func (scheduler *Scheduler) export(ctx context.Context, definition Definition, version string) (*Output, error) {
+	job := reporting.NewExportJob(scheduler.client, reporting.ExportRequest{
+		ReportID: definition.ReportID,
+		Format:   definition.format(),
+		Layout:   definition.Layout,
+		Timezone: definition.Timezone,
+		Filters:  definition.Filters,
+	})
+	job.Timeout = scheduler.ExportTimeout
+	job.PollInterval = scheduler.ExportPollInterval
+	job.Now = scheduler.Now
+	if err := job.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := job.Stream(writer)
+		writer.CloseWithError(err)
+	}()
+	output, err := scheduler.sink.Put(ctx, Output{Definition: definition.Name, Version: version, FileName: job.FileName()}, reader)
+	reader.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return nil, fmt.Errorf("storing export of report '%s': %w", definition.ReportID, err)
+	}
+	return &output, nil
+}
+
+// discard deletes the outputs of a failed attempt.
+func (scheduler *Scheduler) discard(ctx context.Context, outputs []Output) {
+	for _, output := range outputs {
+		scheduler.sink.Delete(ctx, output)
+	}
+}
+
+func version(scheduledAt time.Time) string {
+	return scheduledAt.UTC().Format("20060102T150405Z") + "-" + strings.ToLower(rand.Text()[:8])
+}
+
+// applyRetention deletes the outputs of all but the last Retention versions of a definition.
+func (scheduler *Scheduler) applyRetention(ctx context.Context, definition Definition) error {
+	if definition.Retention <= 0 {
+		return nil
+	}
+	outputs, err := scheduler.sink.List(ctx, definition.Name)
+	if err != nil {
+		return fmt.Errorf("listing outputs of '%s': %w", definition.Name, err)
+	}
+	var versions []string
+	seen := map[string]bool{}
+	for _, output := range outputs {
+		if !seen[output.Version] {
+			seen[output.Version] = true
+			versions = append(versions, output.Version)
+		}
+	}
+	sort.Strings(versions)
+	if len(versions) <= definition.Retention {
+		return nil
+	}
+	expired := map[string]bool{}
+	for _, version := range versions[:len(versions)-definition.Retention] {
+		expired[version] = true
+	}
+	var failures []error
+	for _, output := range outputs {
+		if expired[output.Version] {
+			if err := scheduler.sink.Delete(ctx, output); err != nil {
+				failures = append(failures, err)
+			}
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// record appends a run to the history and drops the oldest runs of its definition beyond HistoryLimit.
+func (scheduler *Scheduler) record(run Run) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	history, err := scheduler.file.Load()
+	if err != nil {
+		return err
+	}
+	if history == nil {
+		history = &History{}
+	}
+	history.Runs = append(history.Runs, run)
+	count := 0
+	for _, recorded := range history.Runs {
+		if recorded.Definition == run.Definition {
+			count++
+		}
+	}
+	if excess := count - scheduler.HistoryLimit; scheduler.HistoryLimit > 0 && excess > 0 {
+		kept := history.Runs[:0]
+		for _, recorded := range history.Runs {
+			if recorded.Definition == run.Definition && excess > 0 {
+				excess--
+				continue
+			}
+			kept = append(kept, recorded)
+		}
+		history.Runs = kept
+	}
+	if err := scheduler.file.Save(history); err != nil {
+		return fmt.Errorf("saving history '%s': %w", scheduler.file.Path, err)
+	}
+	return nil
+}
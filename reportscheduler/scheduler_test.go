@@ -0,0 +1,225 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reportscheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	mutex sync.Mutex
+	// failures is the number of requests that fail before the report is returned.
+	failures int
+	requests int
+}
+
+func (client *fakeClient) fail() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.requests++
+	if client.requests <= client.failures {
+		return errors.New("service unavailable")
+	}
+	return nil
+}
+
+func (client *fakeClient) GetReportPreviewWithContext(ctx context.Context, options *backuprecoveryv1.GetReportPreviewOptions) (*backuprecoveryv1.ReportPreview, *core.DetailedResponse, error) {
+	if err := client.fail(); err != nil {
+		return nil, &core.DetailedResponse{StatusCode: 503}, err
+	}
+	return &backuprecoveryv1.ReportPreview{Components: []backuprecoveryv1.Component{{
+		ID:   core.StringPtr("c1"),
+		Data: []map[string]any{{"runs": 3.0, "system": "prod"}},
+	}}}, &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func (client *fakeClient) GetComponentPreviewWithContext(ctx context.Context, options *backuprecoveryv1.GetComponentPreviewOptions) (*backuprecoveryv1.ComponentPreview, *core.DetailedResponse, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (client *fakeClient) ExportReportWithContext(ctx context.Context, options *backuprecoveryv1.ExportReportOptions) (io.ReadCloser, *core.DetailedResponse, error) {
+	if err := client.fail(); err != nil {
+		return nil, &core.DetailedResponse{StatusCode: 400}, err
+	}
+	return io.NopCloser(strings.NewReader("system,runs\nprod,3\n")), &core.DetailedResponse{StatusCode: 200}, nil
+}
+
+func newScheduler(t *testing.T, client Client, definitions ...Definition) (*Scheduler, string) {
+	dir := t.TempDir()
+	scheduler, err := NewScheduler(client, NewDirSink(filepath.Join(dir, "out")), filepath.Join(dir, "history.json"), definitions)
+	require.Nil(t, err)
+	scheduler.RetryDelay = time.Millisecond
+	scheduler.ExportPollInterval = time.Millisecond
+	return scheduler, dir
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+definitions:
+- name: daily-runs
+  schedule: "0 6 * * *"
+  reportId: r1
+  timezone: Europe/Berlin
+  retention: 7
+- name: weekly-preview
+  schedule: "@weekly"
+  reportId: r2
+  mode: preview
+  format: csv
+  componentIds: [c1]
+`))
+	require.Nil(t, err)
+	require.Len(t, config.Definitions, 2)
+	assert.Equal(t, "Europe/Berlin", config.Definitions[0].Timezone)
+	assert.Equal(t, []string{"c1"}, config.Definitions[1].ComponentIDs)
+
+	for _, content := range []string{
+		"definitions: [{name: a, schedule: '@daily', reportId: r1, unknown: 1}]",
+		"definitions: [{name: a/b, schedule: '@daily', reportId: r1}]",
+		"definitions: [{name: a, schedule: 'daily', reportId: r1}]",
+		"definitions: [{name: a, schedule: '@daily', reportId: r1, timezone: Mars/Olympus}]",
+		"definitions: [{name: a, schedule: '@daily', reportId: r1, componentIds: [c1]}]",
+		"definitions: [{name: a, schedule: '@daily', reportId: r1}, {name: a, schedule: '@daily', reportId: r2}]",
+	} {
+		_, err := ParseConfig([]byte(content))
+		assert.NotNil(t, err, content)
+	}
+}
+
+func TestRunNowExport(t *testing.T) {
+	client := &fakeClient{failures: 1}
+	scheduler, dir := newScheduler(t, client, Definition{Name: "daily", Schedule: "@daily", ReportID: "r1"})
+	scheduler.Now = func() time.Time { return base }
+
+	run, err := scheduler.RunNow(context.Background(), "daily")
+	require.Nil(t, err)
+	assert.Equal(t, RunSucceeded, run.Status)
+	assert.Equal(t, 2, run.Attempts)
+	assert.Regexp(t, `^20260601T120000Z-[a-z2-7]{8}$`, run.Version)
+	require.Len(t, run.Outputs, 1)
+	content, err := os.ReadFile(run.Outputs[0].Location)
+	require.Nil(t, err)
+	assert.Equal(t, "system,runs\nprod,3\n", string(content))
+	assert.Equal(t, filepath.Join(dir, "out", "daily", run.Version), filepath.Dir(run.Outputs[0].Location))
+
+	runs, err := scheduler.History("daily")
+	require.Nil(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.Outputs, runs[0].Outputs)
+}
+
+func TestRunNowPreviewRetention(t *testing.T) {
+	client := &fakeClient{}
+	scheduler, _ := newScheduler(t, client, Definition{Name: "preview", Schedule: "@hourly", ReportID: "r1", Mode: ModePreview, Format: PreviewCSV, Retention: 2})
+	now := base
+	scheduler.Now = func() time.Time { return now }
+
+	for range 3 {
+		_, err := scheduler.RunNow(context.Background(), "preview")
+		require.Nil(t, err)
+		now = now.Add(time.Hour)
+	}
+	outputs, err := scheduler.sink.List(context.Background(), "preview")
+	require.Nil(t, err)
+	require.Len(t, outputs, 2)
+	assert.True(t, strings.HasPrefix(outputs[0].Version, "20260601T130000Z-"))
+	assert.Equal(t, "c1.csv", outputs[0].FileName)
+	content, err := os.ReadFile(outputs[1].Location)
+	require.Nil(t, err)
+	assert.Equal(t, "runs,system\n3,prod\n", string(content))
+
+	runs, err := scheduler.History("")
+	require.Nil(t, err)
+	assert.Len(t, runs, 3)
+}
+
+func TestRunNowFailure(t *testing.T) {
+	client := &fakeClient{failures: 10}
+	scheduler, _ := newScheduler(t, client, Definition{Name: "daily", Schedule: "@daily", ReportID: "r1"})
+	var recorded []Run
+	scheduler.OnRun = func(run Run) { recorded = append(recorded, run) }
+
+	run, err := scheduler.RunNow(context.Background(), "daily")
+	require.NotNil(t, err)
+	assert.Equal(t, RunFailed, run.Status)
+	assert.Equal(t, 3, run.Attempts)
+	assert.Contains(t, run.Error, "service unavailable")
+	require.Len(t, recorded, 1)
+
+	_, err = scheduler.RunNow(context.Background(), "unknown")
+	assert.NotNil(t, err)
+}
+
+func TestRunNowSameSecond(t *testing.T) {
+	client := &fakeClient{}
+	scheduler, _ := newScheduler(t, client, Definition{Name: "daily", Schedule: "@daily", ReportID: "r1"})
+	scheduler.Now = func() time.Time { return base }
+
+	first, err := scheduler.RunNow(context.Background(), "daily")
+	require.Nil(t, err)
+	second, err := scheduler.RunNow(context.Background(), "daily")
+	require.Nil(t, err)
+	assert.NotEqual(t, first.Version, second.Version)
+	outputs, err := scheduler.sink.List(context.Background(), "daily")
+	require.Nil(t, err)
+	assert.Len(t, outputs, 2)
+}
+
+func TestDirSinkRejectsUnsafeFileNames(t *testing.T) {
+	sink := NewDirSink(t.TempDir())
+	for _, name := range []string{"..", "", ".export-1"} {
+		_, err := sink.Put(context.Background(), Output{Definition: "daily", Version: "v1", FileName: name}, strings.NewReader("x"))
+		assert.ErrorContains(t, err, "invalid file name", name)
+	}
+	output, err := sink.Put(context.Background(), Output{Definition: "daily", Version: "v1", FileName: "../runs.csv"}, strings.NewReader("x"))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(sink.Dir, "daily", "v1", "runs.csv"), output.Location)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	client := &fakeClient{}
+	scheduler, _ := newScheduler(t, client, Definition{Name: "often", Schedule: "* * * * *", ReportID: "r1"})
+	// Start just before a minute boundary so the first run is due right away.
+	start := time.Now()
+	offset := start.Truncate(time.Minute).Add(time.Minute).Add(-20 * time.Millisecond).Sub(start)
+	scheduler.Now = func() time.Time { return time.Now().Add(offset) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan Run, 1)
+	scheduler.OnRun = func(run Run) {
+		done <- run
+		cancel()
+	}
+	err := scheduler.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	run := <-done
+	assert.Equal(t, RunSucceeded, run.Status)
+	assert.Equal(t, 0, run.ScheduledAt.Second())
+}
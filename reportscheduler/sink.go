@@ -0,0 +1,144 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reportscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Output is a file written by a run.
+type Output struct {
+	Definition string `json:"definition"`
+
+	// Version identifies the run that wrote the file. Versions sort in the order of the scheduled times of the runs.
+	Version  string `json:"version"`
+	FileName string `json:"fileName"`
+
+	// Location is where the sink stored the file, e.g. its path.
+	Location string `json:"location,omitempty"`
+	Size     int64  `json:"size"`
+}
+
+// Sink stores the outputs of runs.
+type Sink interface {
+	// Put stores content as output and returns the stored output.
+	Put(ctx context.Context, output Output, content io.Reader) (Output, error)
+
+	// List returns the stored outputs of a definition.
+	List(ctx context.Context, definition string) ([]Output, error)
+
+	// Delete removes a stored output.
+	Delete(ctx context.Context, output Output) error
+}
+
+// DirSink stores outputs as files in a directory, at definition/version/file name.
+type DirSink struct {
+	Dir string
+}
+
+// NewDirSink : Instantiate DirSink
+func NewDirSink(dir string) *DirSink {
+	return &DirSink{Dir: dir}
+}
+
+// Put writes the file. It only appears once complete.
+func (sink *DirSink) Put(ctx context.Context, output Output, content io.Reader) (Output, error) {
+	dir := filepath.Join(sink.Dir, output.Definition, output.Version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return output, err
+	}
+	name := filepath.Base(output.FileName)
+	if !filepath.IsLocal(name) || strings.HasPrefix(name, ".") {
+		return output, fmt.Errorf("invalid file name '%s'", output.FileName)
+	}
+	location := filepath.Join(dir, name)
+	var size int64
+	err := helpers.WriteFile(location, func(w io.Writer) error {
+		var err error
+		size, err = io.Copy(w, content)
+		return err
+	})
+	if err != nil {
+		return output, err
+	}
+	output.Location = location
+	output.Size = size
+	return output, nil
+}
+
+// List returns the files of a definition sorted by version and file name.
+func (sink *DirSink) List(ctx context.Context, definition string) ([]Output, error) {
+	versions, err := os.ReadDir(filepath.Join(sink.Dir, definition))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var outputs []Output
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		dir := filepath.Join(sink.Dir, definition, version.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			info, err := file.Info()
+			if err != nil || !info.Mode().IsRegular() || file.Name()[0] == '.' {
+				continue
+			}
+			outputs = append(outputs, Output{
+				Definition: definition,
+				Version:    version.Name(),
+				FileName:   file.Name(),
+				Location:   filepath.Join(dir, file.Name()),
+				Size:       info.Size(),
+			})
+		}
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		if outputs[i].Version != outputs[j].Version {
+			return outputs[i].Version < outputs[j].Version
+		}
+		return outputs[i].FileName < outputs[j].FileName
+	})
+	return outputs, nil
+}
+
+// Delete removes the file, and its version directory once empty.
+func (sink *DirSink) Delete(ctx context.Context, output Output) error {
+	dir := filepath.Join(sink.Dir, output.Definition, output.Version)
+	if err := os.Remove(filepath.Join(dir, filepath.Base(output.FileName))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("deleting output '%s': %w", output.FileName, err)
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+		os.Remove(dir)
+	}
+	return nil
+}
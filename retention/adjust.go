@@ -0,0 +1,391 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Adjustment requests a retention change on the copies of one run.
+type Adjustment struct {
+	GroupID string `json:"groupId"`
+	RunID   string `json:"runId"`
+
+	// Targets selects the copies to change. Every existing copy of the run is changed when empty.
+	Targets []Target `json:"targets,omitempty"`
+
+	// DaysToKeep is added to, or when negative subtracted from, the current expiry of each copy.
+	DaysToKeep int64 `json:"daysToKeep,omitempty"`
+
+	// Delete marks the copies for deletion. DaysToKeep is ignored.
+	Delete bool `json:"delete,omitempty"`
+}
+
+// PlannedChange is the previewed effect of an adjustment on one copy. Refused changes carry the reason and are not
+// applied.
+type PlannedChange struct {
+	GroupID       string    `json:"groupId"`
+	GroupName     string    `json:"groupName,omitempty"`
+	RunID         string    `json:"runId"`
+	Target        Target    `json:"target"`
+	CurrentExpiry time.Time `json:"currentExpiry,omitempty"`
+
+	// NewExpiry is zero for deletions and when the current expiry is unknown.
+	NewExpiry  time.Time `json:"newExpiry,omitempty"`
+	DaysToKeep int64     `json:"daysToKeep,omitempty"`
+	Delete     bool      `json:"delete,omitempty"`
+
+	// Destructive is set for deletions and for shortenings that expire the copy immediately.
+	Destructive bool   `json:"destructive,omitempty"`
+	Refused     string `json:"refused,omitempty"`
+
+	archivalTargetType string
+}
+
+// Plan is the preview of a set of adjustments. Apply refuses a plan whose fingerprint no longer matches the runs, and
+// a destructive plan without its confirmation token.
+type Plan struct {
+	Adjustments []Adjustment    `json:"adjustments"`
+	Changes     []PlannedChange `json:"changes"`
+	Fingerprint string          `json:"fingerprint"`
+
+	confirmation string
+}
+
+// Confirmation returns the token that confirms the deletions of a destructive plan, or an empty string when the plan
+// deletes nothing. The token is not part of the JSON form of the plan; it is meant to be shown to the person
+// approving the deletions, who passes it to Apply.
+func (plan *Plan) Confirmation() string {
+	if !plan.Destructive() {
+		return ""
+	}
+	return plan.confirmation
+}
+
+// Destructive reports whether applying the plan deletes data.
+func (plan *Plan) Destructive() bool {
+	for _, change := range plan.Changes {
+		if change.Destructive && change.Refused == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Refused returns the changes that will not be applied.
+func (plan *Plan) Refused() []PlannedChange {
+	var refused []PlannedChange
+	for _, change := range plan.Changes {
+		if change.Refused != "" {
+			refused = append(refused, change)
+		}
+	}
+	return refused
+}
+
+// RunFailure is a run the cluster did not update.
+type RunFailure struct {
+	GroupID string `json:"groupId"`
+	RunID   string `json:"runId"`
+	Error   string `json:"error"`
+}
+
+// AdjustResult is the outcome of applying a plan.
+type AdjustResult struct {
+	Updated []string     `json:"updated"`
+	Failed  []RunFailure `json:"failed,omitempty"`
+}
+
+// ErrConfirmation is returned by Apply when a destructive plan is applied without its confirmation token.
+var ErrConfirmation = errors.New("destructive plan requires its confirmation token")
+
+// ErrPlanChanged is returned by Apply when the runs changed since the plan was previewed.
+var ErrPlanChanged = errors.New("runs changed since the plan was previewed")
+
+// Adjuster extends, shortens and deletes snapshot copies of existing runs through UpdateProtectionGroupRun.
+// Copies on legal hold are never shortened or deleted, locked copies are never deleted or shortened into their
+// lock period, and every change is previewed before it is applied.
+//
+// Confirmation tokens guard against deletions nobody approved: a stored or forwarded plan, or a caller that only
+// replays plans, cannot apply a destructive plan, because the token is derived from the plan fingerprint with
+// Secret and is never serialized with the plan. They do not guard against callers that can use the Adjuster to
+// preview the same plan themselves.
+type Adjuster struct {
+	client   Client
+	tenantID string
+
+	// Secret keys the confirmation tokens. Adjusters that preview and apply the same plans, for example in separate
+	// processes, must share it. Defaults to a random secret.
+	Secret []byte
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewAdjuster : Instantiate Adjuster
+func NewAdjuster(client Client, tenantID string) *Adjuster {
+	return &Adjuster{client: client, tenantID: tenantID, Secret: []byte(rand.Text()), Now: time.Now}
+}
+
+// Preview computes the new expiry of every selected copy and applies the guardrails.
+func (adjuster *Adjuster) Preview(ctx context.Context, adjustments []Adjustment) (*Plan, error) {
+	now := adjuster.Now()
+	plan := &Plan{Adjustments: adjustments, Changes: []PlannedChange{}}
+	for _, adjustment := range adjustments {
+		result, _, err := adjuster.client.GetProtectionGroupRunsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupRunsOptions{
+			ID:                   core.StringPtr(adjustment.GroupID),
+			XIBMTenantID:         core.StringPtr(adjuster.tenantID),
+			RunID:                core.StringPtr(adjustment.RunID),
+			IncludeObjectDetails: core.BoolPtr(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("getting run '%s' of protection group '%s': %w", adjustment.RunID, adjustment.GroupID, err)
+		}
+		var run *backuprecoveryv1.ProtectionGroupRun
+		if result != nil {
+			for i := range result.Runs {
+				if helpers.Deref(result.Runs[i].ID) == adjustment.RunID {
+					run = &result.Runs[i]
+				}
+			}
+		}
+		if run == nil {
+			return nil, fmt.Errorf("run '%s' of protection group '%s' not found", adjustment.RunID, adjustment.GroupID)
+		}
+
+		copies := existingCopies(run)
+		for _, target := range adjustment.Targets {
+			found := false
+			for _, c := range copies {
+				found = found || c.Target.same(target)
+			}
+			if !found {
+				return nil, fmt.Errorf("run '%s' has no %s copy", adjustment.RunID, target)
+			}
+		}
+		for _, c := range copies {
+			if !selected(c.Target, adjustment.Targets) {
+				continue
+			}
+			change := planChange(adjustment, c, now)
+			change.GroupID = adjustment.GroupID
+			if c.Target.Kind == CopyArchival {
+				change.archivalTargetType = archivalTargetType(run, c.Target.ID)
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+	plan.Fingerprint = fingerprint(plan.Changes)
+	plan.confirmation = adjuster.confirmation(plan.Fingerprint)
+	return plan, nil
+}
+
+func selected(target Target, targets []Target) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	for _, candidate := range targets {
+		if candidate.same(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// planChange previews one copy and records why it is refused, if it is.
+func planChange(adjustment Adjustment, c Copy, now time.Time) PlannedChange {
+	change := PlannedChange{
+		GroupName:     c.ProtectionGroupName,
+		RunID:         c.RunID,
+		Target:        c.Target,
+		CurrentExpiry: c.ReportedExpiry,
+		Delete:        adjustment.Delete,
+	}
+	if adjustment.Delete {
+		change.Destructive = true
+		switch {
+		case c.OnLegalHold:
+			change.Refused = "copy is on legal hold"
+		case c.Locked(now):
+			change.Refused = fmt.Sprintf("copy is DataLocked until %s", c.LockedUntil.Format(time.RFC3339))
+		}
+		return change
+	}
+
+	change.DaysToKeep = adjustment.DaysToKeep
+	if !c.ReportedExpiry.IsZero() {
+		change.NewExpiry = c.ReportedExpiry.Add(time.Duration(adjustment.DaysToKeep) * day)
+	}
+	if adjustment.DaysToKeep >= 0 {
+		return change
+	}
+	change.Destructive = !change.NewExpiry.IsZero() && !change.NewExpiry.After(now)
+	switch {
+	case c.OnLegalHold:
+		change.Refused = "copy is on legal hold"
+	case change.NewExpiry.IsZero():
+		change.Refused = "current expiry is unknown"
+	case c.Locked(now) && change.NewExpiry.Before(c.LockedUntil):
+		change.Refused = fmt.Sprintf("new expiry falls inside the DataLock period ending %s", c.LockedUntil.Format(time.RFC3339))
+	}
+	return change
+}
+
+func archivalTargetType(run *backuprecoveryv1.ProtectionGroupRun, targetID int64) string {
+	if run.ArchivalInfo != nil {
+		for _, result := range run.ArchivalInfo.ArchivalTargetResults {
+			if helpers.Deref(result.TargetID) == targetID {
+				return helpers.Deref(result.TargetType)
+			}
+		}
+	}
+	return ""
+}
+
+// fingerprint hashes the changes, including the state they were computed from, so that a plan applies exactly as
+// previewed.
+func fingerprint(changes []PlannedChange) string {
+	hash := sha256.New()
+	for _, change := range changes {
+		fmt.Fprintf(hash, "%s|%s|%s|%d|%d|%d|%t|%s\n", change.GroupID, change.RunID, change.Target,
+			change.CurrentExpiry.UnixMicro(), change.NewExpiry.UnixMicro(), change.DaysToKeep, change.Delete, change.Refused)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// confirmation derives the confirmation token of a plan from its fingerprint.
+func (adjuster *Adjuster) confirmation(fingerprint string) string {
+	mac := hmac.New(sha256.New, adjuster.Secret)
+	mac.Write([]byte(fingerprint))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// Apply previews the plan's adjustments again and, when nothing changed in the meantime, sends the changes that
+// were not refused. Destructive plans require the token returned by Plan.Confirmation.
+func (adjuster *Adjuster) Apply(ctx context.Context, plan *Plan, confirmation string) (*AdjustResult, error) {
+	if plan.Destructive() && !hmac.Equal([]byte(confirmation), []byte(adjuster.confirmation(plan.Fingerprint))) {
+		return nil, ErrConfirmation
+	}
+	current, err := adjuster.Preview(ctx, plan.Adjustments)
+	if err != nil {
+		return nil, err
+	}
+	if current.Fingerprint != plan.Fingerprint {
+		return nil, ErrPlanChanged
+	}
+
+	type runKey struct{ groupID, runID string }
+	params := map[runKey]*backuprecoveryv1.UpdateProtectionGroupRunParams{}
+	var groups []string
+	var order []runKey
+	for _, change := range current.Changes {
+		if change.Refused != "" {
+			continue
+		}
+		key := runKey{change.GroupID, change.RunID}
+		if params[key] == nil {
+			params[key] = &backuprecoveryv1.UpdateProtectionGroupRunParams{RunID: core.StringPtr(change.RunID)}
+			order = append(order, key)
+			if !slices.Contains(groups, change.GroupID) {
+				groups = append(groups, change.GroupID)
+			}
+		}
+		addChange(params[key], change)
+	}
+
+	result := &AdjustResult{Updated: []string{}}
+	for _, groupID := range groups {
+		var batch []backuprecoveryv1.UpdateProtectionGroupRunParams
+		for _, key := range order {
+			if key.groupID == groupID {
+				batch = append(batch, *params[key])
+			}
+		}
+		response, _, err := adjuster.client.UpdateProtectionGroupRunWithContext(ctx, &backuprecoveryv1.UpdateProtectionGroupRunOptions{
+			ID:                             core.StringPtr(groupID),
+			XIBMTenantID:                   core.StringPtr(adjuster.tenantID),
+			UpdateProtectionGroupRunParams: batch,
+		})
+		if err != nil {
+			for _, run := range batch {
+				result.Failed = append(result.Failed, RunFailure{GroupID: groupID, RunID: *run.RunID, Error: err.Error()})
+			}
+			continue
+		}
+		failed := map[string]string{}
+		if response != nil {
+			for _, failure := range response.FailedRuns {
+				failed[helpers.Deref(failure.RunID)] = helpers.Deref(failure.ErrorMessage)
+			}
+		}
+		for _, run := range batch {
+			if message, ok := failed[*run.RunID]; ok {
+				result.Failed = append(result.Failed, RunFailure{GroupID: groupID, RunID: *run.RunID, Error: message})
+				continue
+			}
+			result.Updated = append(result.Updated, *run.RunID)
+		}
+	}
+	return result, nil
+}
+
+func addChange(params *backuprecoveryv1.UpdateProtectionGroupRunParams, change PlannedChange) {
+	var daysToKeep *int64
+	var deleteSnapshot *bool
+	if change.Delete {
+		deleteSnapshot = core.BoolPtr(true)
+	} else {
+		daysToKeep = core.Int64Ptr(change.DaysToKeep)
+	}
+	switch change.Target.Kind {
+	case CopyLocal:
+		params.LocalSnapshotConfig = &backuprecoveryv1.UpdateLocalSnapshotConfig{DaysToKeep: daysToKeep, DeleteSnapshot: deleteSnapshot}
+	case CopyReplication:
+		if params.ReplicationSnapshotConfig == nil {
+			params.ReplicationSnapshotConfig = &backuprecoveryv1.UpdateReplicationSnapshotConfig{}
+		}
+		params.ReplicationSnapshotConfig.UpdateExistingSnapshotConfig = append(params.ReplicationSnapshotConfig.UpdateExistingSnapshotConfig,
+			backuprecoveryv1.UpdateExistingReplicationSnapshotConfig{
+				ID:             core.Int64Ptr(change.Target.ID),
+				DaysToKeep:     daysToKeep,
+				DeleteSnapshot: deleteSnapshot,
+			})
+	case CopyArchival:
+		if params.ArchivalSnapshotConfig == nil {
+			params.ArchivalSnapshotConfig = &backuprecoveryv1.UpdateArchivalSnapshotConfig{}
+		}
+		params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig = append(params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig,
+			backuprecoveryv1.UpdateExistingArchivalSnapshotConfig{
+				ID:                 core.Int64Ptr(change.Target.ID),
+				ArchivalTargetType: core.StringPtr(change.archivalTargetType),
+				DaysToKeep:         daysToKeep,
+				DeleteSnapshot:     deleteSnapshot,
+			})
+	}
+}
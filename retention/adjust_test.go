@@ -0,0 +1,159 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adjustableRun returns a run whose local copy expires at base+7d and whose archive on target 100 expires at
+// base+60d.
+func adjustableRun(id string) backuprecoveryv1.ProtectionGroupRun {
+	run := testRun(id, base)
+	run.Objects = []backuprecoveryv1.ObjectRunResult{{
+		LocalSnapshotInfo: &backuprecoveryv1.BackupRun{SnapshotInfo: &backuprecoveryv1.SnapshotInfo{
+			ExpiryTimeUsecs: core.Int64Ptr(base.Add(7 * day).UnixMicro()),
+		}},
+	}}
+	run.ArchivalInfo = &backuprecoveryv1.ArchivalRunSummary{ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{{
+		TargetID:        core.Int64Ptr(100),
+		TargetName:      core.StringPtr("vault"),
+		TargetType:      core.StringPtr("Cloud"),
+		Status:          core.StringPtr("Succeeded"),
+		ExpiryTimeUsecs: core.Int64Ptr(base.Add(60 * day).UnixMicro()),
+	}}}
+	return run
+}
+
+func newAdjuster() (*Adjuster, *fakeClient) {
+	held := adjustableRun("r-hold")
+	held.OnLegalHold = core.BoolPtr(true)
+	locked := adjustableRun("r-lock")
+	locked.LocalBackupInfo.DataLockConstraints = &backuprecoveryv1.DataLockConstraints{
+		Mode:            core.StringPtr("Compliance"),
+		ExpiryTimeUsecs: core.Int64Ptr(base.Add(3 * day).UnixMicro()),
+	}
+	client := &fakeClient{runs: map[string][]backuprecoveryv1.ProtectionGroupRun{
+		"pg-1": {adjustableRun("r-ext"), held, locked},
+	}}
+	adjuster := NewAdjuster(client, "tenant")
+	adjuster.Now = func() time.Time { return base.Add(day) }
+	return adjuster, client
+}
+
+func TestAdjustExtend(t *testing.T) {
+	adjuster, client := newAdjuster()
+
+	plan, err := adjuster.Preview(context.Background(), []Adjustment{{GroupID: "pg-1", RunID: "r-ext", DaysToKeep: 10}})
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, base.Add(17*day), plan.Changes[0].NewExpiry)
+	assert.Equal(t, base.Add(70*day), plan.Changes[1].NewExpiry)
+	assert.False(t, plan.Destructive())
+	assert.Empty(t, plan.Refused())
+
+	result, err := adjuster.Apply(context.Background(), plan, "")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"r-ext"}, result.Updated)
+
+	params := client.runUpdates[0].UpdateProtectionGroupRunParams[0]
+	assert.Equal(t, int64(10), *params.LocalSnapshotConfig.DaysToKeep)
+	archival := params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig[0]
+	assert.Equal(t, int64(100), *archival.ID)
+	assert.Equal(t, "Cloud", *archival.ArchivalTargetType)
+	assert.Equal(t, int64(10), *archival.DaysToKeep)
+}
+
+func TestAdjustGuardrails(t *testing.T) {
+	adjuster, client := newAdjuster()
+	local := []Target{{Kind: CopyLocal}}
+
+	plan, err := adjuster.Preview(context.Background(), []Adjustment{
+		{GroupID: "pg-1", RunID: "r-hold", Targets: local, Delete: true},
+		{GroupID: "pg-1", RunID: "r-lock", Targets: local, Delete: true},
+		{GroupID: "pg-1", RunID: "r-lock", Targets: local, DaysToKeep: -5},
+		{GroupID: "pg-1", RunID: "r-hold", Targets: local, DaysToKeep: 5},
+	})
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 4)
+	assert.Equal(t, "copy is on legal hold", plan.Changes[0].Refused)
+	assert.Contains(t, plan.Changes[1].Refused, "DataLocked until")
+	assert.Contains(t, plan.Changes[2].Refused, "inside the DataLock period")
+	// Extending a copy on legal hold is harmless.
+	assert.Empty(t, plan.Changes[3].Refused)
+	assert.False(t, plan.Destructive())
+
+	result, err := adjuster.Apply(context.Background(), plan, "")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"r-hold"}, result.Updated)
+	require.Len(t, client.runUpdates, 1)
+	assert.Len(t, client.runUpdates[0].UpdateProtectionGroupRunParams, 1)
+
+	_, err = adjuster.Preview(context.Background(), []Adjustment{{GroupID: "pg-1", RunID: "r-ext", Targets: []Target{{Kind: CopyReplication, ID: 5}}}})
+	assert.NotNil(t, err)
+}
+
+func TestAdjustDeleteRequiresConfirmation(t *testing.T) {
+	adjuster, client := newAdjuster()
+
+	plan, err := adjuster.Preview(context.Background(), []Adjustment{{GroupID: "pg-1", RunID: "r-ext", Targets: []Target{{Kind: CopyArchival, ID: 100}}, Delete: true}})
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.True(t, plan.Destructive())
+	assert.Len(t, plan.Confirmation(), 12)
+
+	_, err = adjuster.Apply(context.Background(), plan, "")
+	assert.Equal(t, ErrConfirmation, err)
+	_, err = adjuster.Apply(context.Background(), plan, plan.Fingerprint)
+	assert.Equal(t, ErrConfirmation, err)
+
+	// A plan read back from its JSON form carries no confirmation token.
+	content, err := json.Marshal(plan)
+	require.Nil(t, err)
+	assert.NotContains(t, string(content), plan.Confirmation())
+	stored := &Plan{}
+	require.Nil(t, json.Unmarshal(content, stored))
+	assert.Empty(t, stored.Confirmation())
+	assert.Empty(t, client.runUpdates)
+
+	result, err := adjuster.Apply(context.Background(), stored, plan.Confirmation())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"r-ext"}, result.Updated)
+	params := client.runUpdates[0].UpdateProtectionGroupRunParams[0]
+	assert.Nil(t, params.LocalSnapshotConfig)
+	assert.True(t, *params.ArchivalSnapshotConfig.UpdateExistingSnapshotConfig[0].DeleteSnapshot)
+}
+
+func TestAdjustDetectsChangedRuns(t *testing.T) {
+	adjuster, client := newAdjuster()
+
+	plan, err := adjuster.Preview(context.Background(), []Adjustment{{GroupID: "pg-1", RunID: "r-ext", Delete: true}})
+	require.Nil(t, err)
+
+	client.runs["pg-1"][0].OnLegalHold = core.BoolPtr(true)
+	_, err = adjuster.Apply(context.Background(), plan, plan.Confirmation())
+	assert.Equal(t, ErrPlanChanged, err)
+	assert.Empty(t, client.runUpdates)
+}
@@ -0,0 +1,66 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"sort"
+	"time"
+)
+
+// Day groups the copies that expire on one calendar day.
+type Day struct {
+	Date   time.Time `json:"date"`
+	Copies []Copy    `json:"copies"`
+}
+
+// Expiring returns the copies that expire within the given number of days after from, ordered by expiry. Copies on
+// legal hold are left out because they do not expire while the hold is in place.
+func Expiring(copies []Copy, from time.Time, days int) []Copy {
+	until := from.Add(time.Duration(days) * day)
+	var expiring []Copy
+	for _, c := range copies {
+		if c.OnLegalHold || c.Expiry.IsZero() {
+			continue
+		}
+		if !c.Expiry.Before(from) && c.Expiry.Before(until) {
+			expiring = append(expiring, c)
+		}
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Expiry.Before(expiring[j].Expiry)
+	})
+	return expiring
+}
+
+// Calendar groups the copies expiring in the next days after from by calendar day in the given location. Days
+// without expiring copies are omitted. A nil location means UTC.
+func Calendar(copies []Copy, from time.Time, days int, loc *time.Location) []Day {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var calendar []Day
+	for _, c := range Expiring(copies, from, days) {
+		local := c.Expiry.In(loc)
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if n := len(calendar); n > 0 && calendar[n-1].Date.Equal(date) {
+			calendar[n-1].Copies = append(calendar[n-1].Copies, c)
+			continue
+		}
+		calendar = append(calendar, Day{Date: date, Copies: []Copy{c}})
+	}
+	return calendar
+}
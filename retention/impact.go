@@ -0,0 +1,303 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionPolicyByIDWithContext(ctx context.Context, getProtectionPolicyByIdOptions *backuprecoveryv1.GetProtectionPolicyByIdOptions) (result *backuprecoveryv1.ProtectionPolicyResponse, response *core.DetailedResponse, err error)
+	UpdateProtectionPolicyWithContext(ctx context.Context, updateProtectionPolicyOptions *backuprecoveryv1.UpdateProtectionPolicyOptions) (result *backuprecoveryv1.ProtectionPolicyResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+	UpdateProtectionGroupRunWithContext(ctx context.Context, updateProtectionGroupRunOptions *backuprecoveryv1.UpdateProtectionGroupRunOptions) (result *backuprecoveryv1.UpdateProtectionGroupRunResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// ChangeKind classifies the effect of a policy update on an existing copy.
+type ChangeKind string
+
+const (
+	// ChangeExpiresEarlier means the copy would expire before its current expiry.
+	ChangeExpiresEarlier ChangeKind = "expires_earlier"
+	// ChangeLosesCopy means the proposed policy no longer has a rule for the copy's target.
+	ChangeLosesCopy ChangeKind = "loses_copy"
+	// ChangeLockConflict means the proposed expiry falls inside the DataLock (WORM) period of the copy, so the
+	// cluster cannot honour the shorter retention.
+	ChangeLockConflict ChangeKind = "lock_conflict"
+)
+
+// Change is the effect of a policy update on one copy of one run.
+type Change struct {
+	Kind                ChangeKind `json:"kind"`
+	ProtectionGroupID   string     `json:"protectionGroupId"`
+	ProtectionGroupName string     `json:"protectionGroupName,omitempty"`
+	RunID               string     `json:"runId"`
+	Target              Target     `json:"target"`
+	CurrentExpiry       time.Time  `json:"currentExpiry"`
+	ProposedExpiry      time.Time  `json:"proposedExpiry,omitempty"`
+	LockedUntil         time.Time  `json:"lockedUntil,omitempty"`
+}
+
+// TargetDiff describes how the retention of one target differs between two policies. Days are zero for a target
+// that is missing from one side.
+type TargetDiff struct {
+	Target           Target `json:"target"`
+	CurrentDays      int64  `json:"currentDays"`
+	ProposedDays     int64  `json:"proposedDays"`
+	CurrentLockDays  int64  `json:"currentLockDays"`
+	ProposedLockDays int64  `json:"proposedLockDays"`
+	Removed          bool   `json:"removed,omitempty"`
+	Added            bool   `json:"added,omitempty"`
+}
+
+// Shortened reports whether the proposed policy keeps snapshots on the target for less time, or not at all.
+func (diff TargetDiff) Shortened() bool {
+	return diff.Removed || diff.ProposedDays < diff.CurrentDays
+}
+
+// GroupRef identifies a protection group using the analyzed policy.
+type GroupRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Impact is the result of analyzing a policy update.
+type Impact struct {
+	PolicyID string       `json:"policyId"`
+	Targets  []TargetDiff `json:"targets"`
+	Groups   []GroupRef   `json:"groups"`
+	Changes  []Change     `json:"changes"`
+
+	// Conflicts lists policy level problems, e.g. a shorter Compliance DataLock, which the cluster rejects.
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// Safe reports whether the update neither shortens the life of an existing copy nor conflicts with a lock.
+func (impact *Impact) Safe() bool {
+	return len(impact.Changes) == 0 && len(impact.Conflicts) == 0
+}
+
+// LockConflicts returns the changes that collide with a DataLock.
+func (impact *Impact) LockConflicts() []Change {
+	var conflicts []Change
+	for _, change := range impact.Changes {
+		if change.Kind == ChangeLockConflict {
+			conflicts = append(conflicts, change)
+		}
+	}
+	return conflicts
+}
+
+// ImpactError is returned by GuardedUpdate when the update is refused.
+type ImpactError struct {
+	Impact *Impact
+}
+
+func (err *ImpactError) Error() string {
+	return fmt.Sprintf("update of policy '%s' refused: %d existing copies affected, %d policy conflicts",
+		err.Impact.PolicyID, len(err.Impact.Changes), len(err.Impact.Conflicts))
+}
+
+// Analyzer evaluates the effect of a policy update on the runs of every protection group using the policy.
+type Analyzer struct {
+	client   Client
+	tenantID string
+
+	// NumRuns is the number of runs fetched per request. Longer histories are fetched in several requests. Defaults to
+	// 1000.
+	NumRuns int64
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewAnalyzer : Instantiate Analyzer
+func NewAnalyzer(client Client, tenantID string) *Analyzer {
+	return &Analyzer{client: client, tenantID: tenantID, NumRuns: 1000, Now: time.Now}
+}
+
+// Diff compares the retention configured for each target by two policies.
+func Diff(current *backuprecoveryv1.ProtectionPolicyResponse, proposed *backuprecoveryv1.ProtectionPolicyResponse) ([]TargetDiff, []string, error) {
+	currentCalc, err := NewCalculator(current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("current policy: %w", err)
+	}
+	proposedCalc, err := NewCalculator(proposed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("proposed policy: %w", err)
+	}
+	diffs, conflicts := diffCalculators(currentCalc, proposedCalc)
+	return diffs, conflicts, nil
+}
+
+func diffCalculators(current *Calculator, proposed *Calculator) ([]TargetDiff, []string) {
+	var diffs []TargetDiff
+	var conflicts []string
+	for _, r := range current.rules {
+		diff := TargetDiff{Target: r.target, CurrentDays: r.days, CurrentLockDays: r.lockDays}
+		if p := proposed.rule(r.target); p != nil {
+			diff.ProposedDays, diff.ProposedLockDays = p.days, p.lockDays
+		} else {
+			diff.Removed = true
+		}
+		diffs = append(diffs, diff)
+		if r.lockMode == backuprecoveryv1.DataLockConfig_Mode_Compliance && diff.ProposedLockDays < r.lockDays {
+			conflicts = append(conflicts, fmt.Sprintf("compliance datalock on %s cannot be reduced from %d to %d days",
+				r.target, r.lockDays, diff.ProposedLockDays))
+		}
+	}
+	for _, p := range proposed.rules {
+		if current.rule(p.target) == nil {
+			diffs = append(diffs, TargetDiff{Target: p.target, ProposedDays: p.days, ProposedLockDays: p.lockDays, Added: true})
+		}
+	}
+	return diffs, conflicts
+}
+
+// Analyze compares the current and proposed versions of a policy and lists the existing copies that would expire
+// earlier, lose their target, or collide with a DataLock. Only copies that have not yet expired are considered.
+func (analyzer *Analyzer) Analyze(ctx context.Context, current *backuprecoveryv1.ProtectionPolicyResponse, proposed *backuprecoveryv1.ProtectionPolicyResponse) (*Impact, error) {
+	currentCalc, err := NewCalculator(current)
+	if err != nil {
+		return nil, fmt.Errorf("current policy: %w", err)
+	}
+	proposedCalc, err := NewCalculator(proposed)
+	if err != nil {
+		return nil, fmt.Errorf("proposed policy: %w", err)
+	}
+	policyID := helpers.Deref(current.ID)
+	if policyID == "" {
+		return nil, fmt.Errorf("current policy has no id")
+	}
+
+	impact := &Impact{PolicyID: policyID}
+	impact.Targets, impact.Conflicts = diffCalculators(currentCalc, proposedCalc)
+
+	groups, _, err := analyzer.client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+		XIBMTenantID: core.StringPtr(analyzer.tenantID),
+		PolicyIds:    []string{policyID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing protection groups of policy '%s': %w", policyID, err)
+	}
+	if groups == nil {
+		return impact, nil
+	}
+
+	now := analyzer.Now()
+	for _, group := range groups.ProtectionGroups {
+		if helpers.Deref(group.IsDeleted) {
+			continue
+		}
+		ref := GroupRef{ID: helpers.Deref(group.ID), Name: helpers.Deref(group.Name)}
+		impact.Groups = append(impact.Groups, ref)
+
+		runs, err := ListRuns(ctx, analyzer.client, backuprecoveryv1.GetProtectionGroupRunsOptions{
+			ID:                       core.StringPtr(ref.ID),
+			XIBMTenantID:             core.StringPtr(analyzer.tenantID),
+			NumRuns:                  core.Int64Ptr(analyzer.NumRuns),
+			ExcludeNonRestorableRuns: core.BoolPtr(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+		impact.Changes = append(impact.Changes, compare(ref, currentCalc, proposedCalc, runs, now)...)
+	}
+	return impact, nil
+}
+
+func compare(group GroupRef, current *Calculator, proposed *Calculator, runs []backuprecoveryv1.ProtectionGroupRun, now time.Time) []Change {
+	// Both calculators enumerate the same copies in the same order; only the computed expiries differ.
+	proposedCopies := proposed.Expirations(runs, nil)
+	var changes []Change
+	for i, c := range current.Expirations(runs, nil) {
+		if c.OnLegalHold || !c.Expiry.After(now) {
+			continue
+		}
+		p := proposedCopies[i]
+		change := Change{
+			ProtectionGroupID:   group.ID,
+			ProtectionGroupName: group.Name,
+			RunID:               c.RunID,
+			Target:              c.Target,
+			CurrentExpiry:       c.Expiry,
+			LockedUntil:         c.LockedUntil,
+		}
+		switch {
+		case c.Managed && !p.Managed:
+			change.Kind = ChangeLosesCopy
+		case p.Expiry.Before(c.Expiry) && c.Locked(now) && (p.Source == SourceDataLock || p.Expiry.Before(c.LockedUntil)):
+			change.Kind = ChangeLockConflict
+			change.ProposedExpiry = p.Expiry
+		case p.Expiry.Before(c.Expiry):
+			change.Kind = ChangeExpiresEarlier
+			change.ProposedExpiry = p.Expiry
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// GuardedUpdate analyzes the update described by the options against the current version of the policy and only
+// calls UpdateProtectionPolicy when the update is safe or force is set. A refused update returns an *ImpactError;
+// the impact is returned in every case where the analysis succeeded.
+func (analyzer *Analyzer) GuardedUpdate(ctx context.Context, options *backuprecoveryv1.UpdateProtectionPolicyOptions, force bool) (*backuprecoveryv1.ProtectionPolicyResponse, *Impact, error) {
+	if options == nil || options.ID == nil {
+		return nil, nil, fmt.Errorf("update options must specify the policy id")
+	}
+	current, _, err := analyzer.client.GetProtectionPolicyByIDWithContext(ctx, &backuprecoveryv1.GetProtectionPolicyByIdOptions{
+		ID:           options.ID,
+		XIBMTenantID: core.StringPtr(analyzer.tenantID),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting policy '%s': %w", *options.ID, err)
+	}
+	proposed := &backuprecoveryv1.ProtectionPolicyResponse{
+		ID:                 options.ID,
+		Name:               options.Name,
+		BackupPolicy:       options.BackupPolicy,
+		ExtendedRetention:  options.ExtendedRetention,
+		RemoteTargetPolicy: options.RemoteTargetPolicy,
+	}
+	impact, err := analyzer.Analyze(ctx, current, proposed)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !impact.Safe() && !force {
+		return nil, impact, &ImpactError{Impact: impact}
+	}
+	if options.XIBMTenantID == nil {
+		options.XIBMTenantID = core.StringPtr(analyzer.tenantID)
+	}
+	updated, _, err := analyzer.client.UpdateProtectionPolicyWithContext(ctx, options)
+	if err != nil {
+		return nil, impact, fmt.Errorf("updating policy '%s': %w", *options.ID, err)
+	}
+	return updated, impact, nil
+}
@@ -0,0 +1,170 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	policy  *backuprecoveryv1.ProtectionPolicyResponse
+	groups  []backuprecoveryv1.ProtectionGroupResponse
+	runs    map[string][]backuprecoveryv1.ProtectionGroupRun
+	updated *backuprecoveryv1.UpdateProtectionPolicyOptions
+
+	runUpdates []*backuprecoveryv1.UpdateProtectionGroupRunOptions
+}
+
+func (fake *fakeClient) GetProtectionPolicyByIDWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionPolicyByIdOptions) (*backuprecoveryv1.ProtectionPolicyResponse, *core.DetailedResponse, error) {
+	return fake.policy, nil, nil
+}
+
+func (fake *fakeClient) UpdateProtectionPolicyWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionPolicyOptions) (*backuprecoveryv1.ProtectionPolicyResponse, *core.DetailedResponse, error) {
+	fake.updated = options
+	return &backuprecoveryv1.ProtectionPolicyResponse{ID: options.ID, Name: options.Name}, nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	if len(options.PolicyIds) != 1 || options.PolicyIds[0] != "policy-1" {
+		return nil, nil, errors.New("unexpected policy filter")
+	}
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: fake.groups}, nil, nil
+}
+
+func (fake *fakeClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	if options.RunID == nil {
+		return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: fake.runs[*options.ID]}, nil, nil
+	}
+	var runs []backuprecoveryv1.ProtectionGroupRun
+	for _, run := range fake.runs[*options.ID] {
+		if *run.ID == *options.RunID {
+			runs = append(runs, run)
+		}
+	}
+	return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: runs}, nil, nil
+}
+
+func (fake *fakeClient) UpdateProtectionGroupRunWithContext(ctx context.Context, options *backuprecoveryv1.UpdateProtectionGroupRunOptions) (*backuprecoveryv1.UpdateProtectionGroupRunResponse, *core.DetailedResponse, error) {
+	fake.runUpdates = append(fake.runUpdates, options)
+	response := &backuprecoveryv1.UpdateProtectionGroupRunResponse{}
+	for _, params := range options.UpdateProtectionGroupRunParams {
+		response.SuccessfulRunIds = append(response.SuccessfulRunIds, *params.RunID)
+	}
+	return response, nil, nil
+}
+
+func newFakeClient() *fakeClient {
+	run := testRun("r1", base.Add(day))
+	run.ArchivalInfo = &backuprecoveryv1.ArchivalRunSummary{
+		ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{
+			{TargetID: core.Int64Ptr(100), Status: core.StringPtr("Succeeded")},
+		},
+	}
+	return &fakeClient{
+		policy: testPolicy(),
+		groups: []backuprecoveryv1.ProtectionGroupResponse{
+			{ID: core.StringPtr("pg-1"), Name: core.StringPtr("db")},
+			{ID: core.StringPtr("pg-2"), IsDeleted: core.BoolPtr(true)},
+		},
+		runs: map[string][]backuprecoveryv1.ProtectionGroupRun{
+			"pg-1": {testRun("r0", base), run},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	proposed := testPolicy()
+	proposed.BackupPolicy.Regular.Retention.DataLockConfig.Duration = core.Int64Ptr(1)
+	proposed.RemoteTargetPolicy.ArchivalTargets = nil
+
+	diffs, conflicts, err := Diff(testPolicy(), proposed)
+	require.Nil(t, err)
+	require.Len(t, diffs, 3)
+	assert.False(t, diffs[0].Shortened())
+	assert.True(t, diffs[2].Removed)
+	assert.True(t, diffs[2].Shortened())
+	require.Len(t, conflicts, 1)
+	assert.Contains(t, conflicts[0], "cannot be reduced from 3 to 1 days")
+}
+
+func TestAnalyze(t *testing.T) {
+	client := newFakeClient()
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.Now = func() time.Time { return base.Add(2 * day) }
+
+	proposed := testPolicy()
+	proposed.BackupPolicy.Regular.Retention.Duration = core.Int64Ptr(2)
+	proposed.RemoteTargetPolicy.ArchivalTargets = nil
+
+	impact, err := analyzer.Analyze(context.Background(), client.policy, proposed)
+	require.Nil(t, err)
+	assert.False(t, impact.Safe())
+	assert.Equal(t, []GroupRef{{ID: "pg-1", Name: "db"}}, impact.Groups)
+	require.Len(t, impact.Changes, 2)
+
+	// The local copy of r1 is still locked until base+4d, past the proposed base+3d expiry.
+	assert.Equal(t, ChangeLockConflict, impact.Changes[0].Kind)
+	assert.Equal(t, "r1", impact.Changes[0].RunID)
+	assert.Len(t, impact.LockConflicts(), 1)
+	assert.Equal(t, ChangeLosesCopy, impact.Changes[1].Kind)
+	assert.Equal(t, CopyArchival, impact.Changes[1].Target.Kind)
+
+	analyzer.Now = func() time.Time { return base.Add(5 * day) }
+	impact, err = analyzer.Analyze(context.Background(), client.policy, proposed)
+	require.Nil(t, err)
+	assert.Equal(t, ChangeExpiresEarlier, impact.Changes[0].Kind)
+	assert.Equal(t, base.Add(4*day), impact.Changes[0].ProposedExpiry)
+}
+
+func TestGuardedUpdate(t *testing.T) {
+	client := newFakeClient()
+	analyzer := NewAnalyzer(client, "tenant")
+	analyzer.Now = func() time.Time { return base.Add(5 * day) }
+
+	options := &backuprecoveryv1.UpdateProtectionPolicyOptions{
+		ID:                 core.StringPtr("policy-1"),
+		Name:               core.StringPtr("gold"),
+		BackupPolicy:       testPolicy().BackupPolicy,
+		ExtendedRetention:  testPolicy().ExtendedRetention,
+		RemoteTargetPolicy: &backuprecoveryv1.TargetsConfiguration{},
+	}
+	_, impact, err := analyzer.GuardedUpdate(context.Background(), options, false)
+	var impactErr *ImpactError
+	require.True(t, errors.As(err, &impactErr))
+	assert.NotNil(t, impact)
+	assert.Nil(t, client.updated)
+
+	updated, _, err := analyzer.GuardedUpdate(context.Background(), options, true)
+	require.Nil(t, err)
+	assert.Equal(t, "policy-1", *updated.ID)
+	assert.Equal(t, "tenant", *client.updated.XIBMTenantID)
+
+	options.RemoteTargetPolicy = testPolicy().RemoteTargetPolicy
+	client.updated = nil
+	_, impact, err = analyzer.GuardedUpdate(context.Background(), options, false)
+	require.Nil(t, err)
+	assert.True(t, impact.Safe())
+	assert.NotNil(t, client.updated)
+}
@@ -0,0 +1,557 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package retention computes when snapshot copies expire under a protection policy.
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+const day = 24 * time.Hour
+
+// CopyKind identifies where a snapshot copy is stored.
+type CopyKind string
+
+const (
+	CopyLocal       CopyKind = "local"
+	CopyReplication CopyKind = "replication"
+	CopyArchival    CopyKind = "archival"
+)
+
+// Target identifies a single snapshot copy location. ID is the remote cluster id for replication copies and the
+// archival target id for archival copies; it is zero for local copies.
+type Target struct {
+	Kind CopyKind `json:"kind"`
+	ID   int64    `json:"id,omitempty"`
+	Name string   `json:"name,omitempty"`
+}
+
+// String returns a short human readable form of the target.
+func (target Target) String() string {
+	if target.Kind == CopyLocal {
+		return string(CopyLocal)
+	}
+	if target.Name != "" {
+		return fmt.Sprintf("%s:%s", target.Kind, target.Name)
+	}
+	return fmt.Sprintf("%s:%d", target.Kind, target.ID)
+}
+
+// same reports whether both values refer to the same copy location, ignoring the display name.
+func (target Target) same(other Target) bool {
+	return target.Kind == other.Kind && target.ID == other.ID
+}
+
+// Source explains which rule determined the expiry of a copy.
+type Source string
+
+const (
+	// SourcePolicy means the base retention of the policy (or policy target) applies.
+	SourcePolicy Source = "policy"
+	// SourceExtended means an extended retention tier kept the copy longer than the base retention.
+	SourceExtended Source = "extended"
+	// SourceDataLock means the DataLock (WORM) period outlasts every retention rule.
+	SourceDataLock Source = "datalock"
+	// SourceReported means no policy rule covers the copy and the expiry reported by the cluster is used.
+	SourceReported Source = "reported"
+)
+
+// Copy is the computed retention of one snapshot copy of one run.
+type Copy struct {
+	RunID               string    `json:"runId"`
+	ProtectionGroupID   string    `json:"protectionGroupId,omitempty"`
+	ProtectionGroupName string    `json:"protectionGroupName,omitempty"`
+	Target              Target    `json:"target"`
+	SnapshotTime        time.Time `json:"snapshotTime"`
+	Expiry              time.Time `json:"expiry"`
+	Source              Source    `json:"source"`
+
+	// LockedUntil is the end of the DataLock period of the copy. It is zero when the copy is not locked.
+	LockedUntil time.Time `json:"lockedUntil,omitempty"`
+	LockMode    string    `json:"lockMode,omitempty"`
+
+	// DaysToKeep is the sum of the overrides applied on top of the policy expiry.
+	DaysToKeep int64 `json:"daysToKeep,omitempty"`
+
+	// ReportedExpiry is the expiry returned by the cluster for the copy, if any.
+	ReportedExpiry time.Time `json:"reportedExpiry,omitempty"`
+
+	OnLegalHold bool `json:"onLegalHold,omitempty"`
+
+	// Managed is false when the policy has no rule for the copy's target, e.g. after an archival target was removed.
+	Managed bool `json:"managed"`
+}
+
+// Locked reports whether the copy is still protected by a DataLock at the given time.
+func (c Copy) Locked(now time.Time) bool {
+	return !c.LockedUntil.IsZero() && now.Before(c.LockedUntil)
+}
+
+// Override records a DaysToKeep adjustment applied to a copy with UpdateProtectionGroupRun. Like the API, the days
+// are added to (or, when negative, subtracted from) the expiry computed from the policy.
+type Override struct {
+	RunID      string `json:"runId"`
+	Target     Target `json:"target"`
+	DaysToKeep int64  `json:"daysToKeep"`
+}
+
+// Days converts a retention unit and duration to days. Months count as 30 days and years as 365 days, matching
+// the way the cluster interprets Retention and DataLockConfig.
+func Days(unit string, duration int64) (int64, error) {
+	switch unit {
+	case backuprecoveryv1.Retention_Unit_Days:
+		return duration, nil
+	case backuprecoveryv1.Retention_Unit_Weeks:
+		return duration * 7, nil
+	case backuprecoveryv1.Retention_Unit_Months:
+		return duration * 30, nil
+	case backuprecoveryv1.Retention_Unit_Years:
+		return duration * 365, nil
+	}
+	return 0, fmt.Errorf("unsupported retention unit '%s'", unit)
+}
+
+// rule is the retention of one copy location as configured by a policy.
+type rule struct {
+	target   Target
+	days     int64
+	lockDays int64
+	lockMode string
+	fullDays int64
+	extended []extendedRule
+}
+
+// extendedRule is a single extended retention tier.
+type extendedRule struct {
+	unit      string
+	frequency int64
+	runType   string
+	days      int64
+	lockDays  int64
+}
+
+// Calculator computes copy expiries for the runs of protection groups that use a policy.
+type Calculator struct {
+	policyID string
+	rules    []rule
+}
+
+// ErrNoRetention is returned by NewCalculator when a copy location of the policy has no retention. Reading it as zero
+// days would expire its copies when they are taken.
+var ErrNoRetention = errors.New("retention is not set")
+
+// NewCalculator : Instantiate Calculator for the given policy.
+func NewCalculator(policy *backuprecoveryv1.ProtectionPolicyResponse) (*Calculator, error) {
+	if policy == nil || policy.BackupPolicy == nil || policy.BackupPolicy.Regular == nil {
+		return nil, fmt.Errorf("policy has no regular backup policy")
+	}
+	calc := &Calculator{policyID: helpers.Deref(policy.ID)}
+
+	regular := policy.BackupPolicy.Regular
+	local, err := newRule(Target{Kind: CopyLocal}, regular.Retention, policy.ExtendedRetention)
+	if err != nil {
+		return nil, fmt.Errorf("local retention: %w", err)
+	}
+	for _, full := range regular.FullBackups {
+		days, _, _, err := retentionDays(full.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("full backup retention: %w", err)
+		}
+		local.fullDays = max(local.fullDays, days)
+	}
+	calc.rules = append(calc.rules, local)
+
+	if targets := policy.RemoteTargetPolicy; targets != nil {
+		for _, replication := range targets.ReplicationTargets {
+			target := Target{Kind: CopyReplication}
+			if replication.RemoteTargetConfig != nil {
+				target.ID = helpers.Deref(replication.RemoteTargetConfig.ClusterID)
+				target.Name = helpers.Deref(replication.RemoteTargetConfig.ClusterName)
+			}
+			r, err := newRule(target, replication.Retention, nil)
+			if err != nil {
+				return nil, fmt.Errorf("replication retention for %s: %w", target, err)
+			}
+			calc.rules = append(calc.rules, r)
+		}
+		for _, archival := range targets.ArchivalTargets {
+			target := Target{Kind: CopyArchival, ID: helpers.Deref(archival.TargetID), Name: helpers.Deref(archival.TargetName)}
+			r, err := newRule(target, archival.Retention, archival.ExtendedRetention)
+			if err != nil {
+				return nil, fmt.Errorf("archival retention for %s: %w", target, err)
+			}
+			calc.rules = append(calc.rules, r)
+		}
+	}
+	return calc, nil
+}
+
+// Targets returns the copy locations configured by the policy.
+func (calc *Calculator) Targets() []Target {
+	targets := make([]Target, 0, len(calc.rules))
+	for _, r := range calc.rules {
+		targets = append(targets, r.target)
+	}
+	return targets
+}
+
+// RetentionDays returns the base retention and the DataLock period in days for a configured target. The boolean is
+// false when the policy has no rule for the target.
+func (calc *Calculator) RetentionDays(target Target) (days int64, lockDays int64, ok bool) {
+	if r := calc.rule(target); r != nil {
+		return r.days, r.lockDays, true
+	}
+	return 0, 0, false
+}
+
+func (calc *Calculator) rule(target Target) *rule {
+	for i := range calc.rules {
+		if calc.rules[i].target.same(target) {
+			return &calc.rules[i]
+		}
+	}
+	return nil
+}
+
+func newRule(target Target, retention *backuprecoveryv1.Retention, extended []backuprecoveryv1.ExtendedRetentionPolicy) (rule, error) {
+	if retention == nil {
+		return rule{}, ErrNoRetention
+	}
+	days, lockDays, lockMode, err := retentionDays(retention)
+	if err != nil {
+		return rule{}, err
+	}
+	r := rule{target: target, days: days, lockDays: lockDays, lockMode: lockMode}
+	for _, ext := range extended {
+		if ext.Schedule == nil {
+			continue
+		}
+		extDays, extLockDays, _, err := retentionDays(ext.Retention)
+		if err != nil {
+			return rule{}, fmt.Errorf("extended retention: %w", err)
+		}
+		frequency := helpers.Deref(ext.Schedule.Frequency)
+		if frequency <= 0 {
+			frequency = 1
+		}
+		r.extended = append(r.extended, extendedRule{
+			unit:      helpers.Deref(ext.Schedule.Unit),
+			frequency: frequency,
+			runType:   helpers.Deref(ext.RunType),
+			days:      extDays,
+			lockDays:  extLockDays,
+		})
+	}
+	return r, nil
+}
+
+func retentionDays(retention *backuprecoveryv1.Retention) (days int64, lockDays int64, lockMode string, err error) {
+	if retention == nil {
+		return 0, 0, "", nil
+	}
+	days, err = Days(helpers.Deref(retention.Unit), helpers.Deref(retention.Duration))
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if lock := retention.DataLockConfig; lock != nil {
+		lockDays, err = Days(helpers.Deref(lock.Unit), helpers.Deref(lock.Duration))
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("datalock: %w", err)
+		}
+		lockMode = helpers.Deref(lock.Mode)
+	}
+	return days, lockDays, lockMode, nil
+}
+
+// Expirations computes the expiry of every existing copy of the given runs. Runs may belong to several protection
+// groups using the policy. Extended retention tiers are evaluated over the supplied history, so the history should
+// start at least one tier period before the oldest run of interest. Overrides are applied on top of the policy
+// expiry and the result never ends before the DataLock period of the copy.
+func (calc *Calculator) Expirations(runs []backuprecoveryv1.ProtectionGroupRun, overrides []Override) []Copy {
+	ordered := make([]*backuprecoveryv1.ProtectionGroupRun, 0, len(runs))
+	for i := range runs {
+		if _, ok := SnapshotTime(&runs[i]); ok {
+			ordered = append(ordered, &runs[i])
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, _ := SnapshotTime(ordered[i])
+		b, _ := SnapshotTime(ordered[j])
+		return a.Before(b)
+	})
+
+	tiers := calc.extendedDays(ordered)
+	var copies []Copy
+	for _, run := range ordered {
+		for _, c := range existingCopies(run) {
+			calc.apply(&c, run, tiers[extKey{run: helpers.Deref(run.ID), target: c.Target.Kind, id: c.Target.ID}], overrides)
+			copies = append(copies, c)
+		}
+	}
+	return copies
+}
+
+type extKey struct {
+	run    string
+	target CopyKind
+	id     int64
+}
+
+type tierResult struct {
+	days     int64
+	lockDays int64
+}
+
+// extendedDays evaluates the extended retention tiers of every rule over the ordered history and returns, per run
+// and target, the longest tier retention that applies.
+func (calc *Calculator) extendedDays(ordered []*backuprecoveryv1.ProtectionGroupRun) map[extKey]tierResult {
+	result := make(map[extKey]tierResult)
+	for _, r := range calc.rules {
+		for _, ext := range r.extended {
+			lastBucket := make(map[string]int64)
+			counts := make(map[string]int64)
+			for _, run := range ordered {
+				if !succeeded(run.LocalBackupInfo) && !succeeded(run.OriginalBackupInfo) {
+					continue
+				}
+				if ext.runType != "" && !runTypeMatches(ext.runType, runType(run)) {
+					continue
+				}
+				group := helpers.Deref(run.ProtectionGroupID)
+				t, _ := SnapshotTime(run)
+				var keep bool
+				if ext.unit == backuprecoveryv1.ExtendedRetentionSchedule_Unit_Runs {
+					keep = counts[group]%ext.frequency == 0
+					counts[group]++
+				} else {
+					bucket, ok := periodIndex(ext.unit, t)
+					if !ok {
+						continue
+					}
+					bucket /= ext.frequency
+					last, seen := lastBucket[group]
+					keep = !seen || bucket != last
+					lastBucket[group] = bucket
+				}
+				if !keep {
+					continue
+				}
+				key := extKey{run: helpers.Deref(run.ID), target: r.target.Kind, id: r.target.ID}
+				current := result[key]
+				result[key] = tierResult{days: max(current.days, ext.days), lockDays: max(current.lockDays, ext.lockDays)}
+			}
+		}
+	}
+	return result
+}
+
+func (calc *Calculator) apply(c *Copy, run *backuprecoveryv1.ProtectionGroupRun, tier tierResult, overrides []Override) {
+	r := calc.rule(c.Target)
+	if r == nil {
+		if !c.ReportedExpiry.IsZero() {
+			c.Expiry = c.ReportedExpiry
+		}
+		c.Source = SourceReported
+		applyOverrides(c, overrides)
+		return
+	}
+	c.Managed = true
+	if c.Target.Name == "" {
+		c.Target.Name = r.target.Name
+	}
+
+	days := r.days
+	if r.fullDays > days && runTypeMatches(backuprecoveryv1.ExtendedRetentionPolicy_RunType_Full, runType(run)) {
+		days = r.fullDays
+	}
+	c.Source = SourcePolicy
+	if tier.days > days {
+		days = tier.days
+		c.Source = SourceExtended
+	}
+	c.Expiry = c.SnapshotTime.Add(time.Duration(days) * day)
+
+	if lockDays := max(r.lockDays, tier.lockDays); lockDays > 0 {
+		c.LockedUntil = maxTime(c.LockedUntil, c.SnapshotTime.Add(time.Duration(lockDays)*day))
+		if c.LockMode == "" {
+			c.LockMode = r.lockMode
+		}
+	}
+	applyOverrides(c, overrides)
+}
+
+func applyOverrides(c *Copy, overrides []Override) {
+	for _, o := range overrides {
+		if o.RunID == c.RunID && o.Target.same(c.Target) {
+			c.DaysToKeep += o.DaysToKeep
+		}
+	}
+	if c.DaysToKeep != 0 && !c.Expiry.IsZero() {
+		c.Expiry = c.Expiry.Add(time.Duration(c.DaysToKeep) * day)
+	}
+	if c.LockedUntil.After(c.Expiry) {
+		c.Expiry = c.LockedUntil
+		c.Source = SourceDataLock
+	}
+}
+
+// existingCopies lists the successful, not deleted copies of a run with their reported expiry and lock.
+func existingCopies(run *backuprecoveryv1.ProtectionGroupRun) []Copy {
+	snapshot, _ := SnapshotTime(run)
+	base := Copy{
+		RunID:               helpers.Deref(run.ID),
+		ProtectionGroupID:   helpers.Deref(run.ProtectionGroupID),
+		ProtectionGroupName: helpers.Deref(run.ProtectionGroupName),
+		SnapshotTime:        snapshot,
+		OnLegalHold:         helpers.Deref(run.OnLegalHold),
+	}
+
+	var copies []Copy
+	info := run.LocalBackupInfo
+	if info == nil {
+		info = run.OriginalBackupInfo
+	}
+	if succeeded(info) && !helpers.Deref(run.IsLocalSnapshotsDeleted) {
+		c := base
+		c.Target = Target{Kind: CopyLocal}
+		setLock(&c, info.DataLockConstraints)
+		c.ReportedExpiry = localReportedExpiry(run)
+		copies = append(copies, c)
+	}
+	if run.ReplicationInfo != nil {
+		for _, result := range run.ReplicationInfo.ReplicationTargetResults {
+			if !copySucceeded(result.Status) || helpers.Deref(result.IsManuallyDeleted) || helpers.Deref(result.IsInBound) {
+				continue
+			}
+			c := base
+			c.Target = Target{Kind: CopyReplication, ID: helpers.Deref(result.ClusterID), Name: helpers.Deref(result.ClusterName)}
+			c.ReportedExpiry = usecs(result.ExpiryTimeUsecs)
+			c.OnLegalHold = c.OnLegalHold || helpers.Deref(result.OnLegalHold)
+			setLock(&c, result.DataLockConstraints)
+			copies = append(copies, c)
+		}
+	}
+	if run.ArchivalInfo != nil {
+		for _, result := range run.ArchivalInfo.ArchivalTargetResults {
+			if !copySucceeded(result.Status) || helpers.Deref(result.IsManuallyDeleted) {
+				continue
+			}
+			c := base
+			c.Target = Target{Kind: CopyArchival, ID: helpers.Deref(result.TargetID), Name: helpers.Deref(result.TargetName)}
+			c.ReportedExpiry = usecs(result.ExpiryTimeUsecs)
+			c.OnLegalHold = c.OnLegalHold || helpers.Deref(result.OnLegalHold)
+			setLock(&c, result.DataLockConstraints)
+			copies = append(copies, c)
+		}
+	}
+	return copies
+}
+
+// localReportedExpiry returns the latest expiry reported by the objects of a run for the local snapshot.
+func localReportedExpiry(run *backuprecoveryv1.ProtectionGroupRun) time.Time {
+	var expiry time.Time
+	for _, object := range run.Objects {
+		if object.LocalSnapshotInfo != nil && object.LocalSnapshotInfo.SnapshotInfo != nil {
+			expiry = maxTime(expiry, usecs(object.LocalSnapshotInfo.SnapshotInfo.ExpiryTimeUsecs))
+		}
+	}
+	return expiry
+}
+
+func setLock(c *Copy, constraints *backuprecoveryv1.DataLockConstraints) {
+	if constraints == nil {
+		return
+	}
+	c.LockedUntil = maxTime(c.LockedUntil, usecs(constraints.ExpiryTimeUsecs))
+	c.LockMode = helpers.Deref(constraints.Mode)
+}
+
+// SnapshotTime returns the time the snapshot of a run was taken, which is the start of its backup.
+func SnapshotTime(run *backuprecoveryv1.ProtectionGroupRun) (time.Time, bool) {
+	for _, info := range []*backuprecoveryv1.BackupRunSummary{run.LocalBackupInfo, run.OriginalBackupInfo} {
+		if info != nil && info.StartTimeUsecs != nil {
+			return usecs(info.StartTimeUsecs), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func runType(run *backuprecoveryv1.ProtectionGroupRun) string {
+	if run.LocalBackupInfo != nil && run.LocalBackupInfo.RunType != nil {
+		return *run.LocalBackupInfo.RunType
+	}
+	if run.OriginalBackupInfo != nil {
+		return helpers.Deref(run.OriginalBackupInfo.RunType)
+	}
+	return ""
+}
+
+// runTypeMatches compares a policy run type ('Full') with a run summary run type ('kFull').
+func runTypeMatches(policyRunType string, summaryRunType string) bool {
+	return "k"+policyRunType == summaryRunType || policyRunType == summaryRunType
+}
+
+func succeeded(info *backuprecoveryv1.BackupRunSummary) bool {
+	return info != nil && copySucceeded(info.Status)
+}
+
+func copySucceeded(status *string) bool {
+	switch helpers.Deref(status) {
+	case backuprecoveryv1.BackupRunSummary_Status_Succeeded, backuprecoveryv1.BackupRunSummary_Status_Succeededwithwarning:
+		return true
+	}
+	return false
+}
+
+// periodIndex numbers calendar periods of the given unit in UTC. Weeks start on Monday.
+func periodIndex(unit string, t time.Time) (int64, bool) {
+	t = t.UTC()
+	switch unit {
+	case backuprecoveryv1.ExtendedRetentionSchedule_Unit_Hours:
+		return t.Unix() / 3600, true
+	case backuprecoveryv1.ExtendedRetentionSchedule_Unit_Days:
+		return t.Unix() / 86400, true
+	case backuprecoveryv1.ExtendedRetentionSchedule_Unit_Weeks:
+		// 1970-01-01 was a Thursday; shift so that buckets start on Monday.
+		return (t.Unix()/86400 + 3) / 7, true
+	case backuprecoveryv1.ExtendedRetentionSchedule_Unit_Months:
+		return int64(t.Year())*12 + int64(t.Month()) - 1, true
+	case backuprecoveryv1.ExtendedRetentionSchedule_Unit_Years:
+		return int64(t.Year()), true
+	}
+	return 0, false
+}
+
+func usecs(value *int64) time.Time {
+	if value == nil || *value == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(*value).UTC()
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,215 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.March, 1, 2, 0, 0, 0, time.UTC)
+
+func testPolicy() *backuprecoveryv1.ProtectionPolicyResponse {
+	return &backuprecoveryv1.ProtectionPolicyResponse{
+		ID:   core.StringPtr("policy-1"),
+		Name: core.StringPtr("gold"),
+		BackupPolicy: &backuprecoveryv1.BackupPolicy{
+			Regular: &backuprecoveryv1.RegularBackupPolicy{
+				Retention: &backuprecoveryv1.Retention{
+					Unit:     core.StringPtr("Days"),
+					Duration: core.Int64Ptr(7),
+					DataLockConfig: &backuprecoveryv1.DataLockConfig{
+						Mode:     core.StringPtr("Compliance"),
+						Unit:     core.StringPtr("Days"),
+						Duration: core.Int64Ptr(3),
+					},
+				},
+			},
+		},
+		ExtendedRetention: []backuprecoveryv1.ExtendedRetentionPolicy{{
+			Schedule:  &backuprecoveryv1.ExtendedRetentionSchedule{Unit: core.StringPtr("Months"), Frequency: core.Int64Ptr(1)},
+			Retention: &backuprecoveryv1.Retention{Unit: core.StringPtr("Years"), Duration: core.Int64Ptr(1)},
+		}},
+		RemoteTargetPolicy: &backuprecoveryv1.TargetsConfiguration{
+			ReplicationTargets: []backuprecoveryv1.ReplicationTargetConfiguration{{
+				Retention:          &backuprecoveryv1.Retention{Unit: core.StringPtr("Weeks"), Duration: core.Int64Ptr(2)},
+				TargetType:         core.StringPtr("RemoteCluster"),
+				RemoteTargetConfig: &backuprecoveryv1.RemoteTargetConfig{ClusterID: core.Int64Ptr(5), ClusterName: core.StringPtr("dr")},
+			}},
+			ArchivalTargets: []backuprecoveryv1.ArchivalTargetConfiguration{{
+				Retention:  &backuprecoveryv1.Retention{Unit: core.StringPtr("Months"), Duration: core.Int64Ptr(2)},
+				TargetID:   core.Int64Ptr(100),
+				TargetName: core.StringPtr("vault"),
+			}},
+		},
+	}
+}
+
+func testRun(id string, start time.Time) backuprecoveryv1.ProtectionGroupRun {
+	return backuprecoveryv1.ProtectionGroupRun{
+		ID:                core.StringPtr(id),
+		ProtectionGroupID: core.StringPtr("pg-1"),
+		LocalBackupInfo: &backuprecoveryv1.BackupRunSummary{
+			RunType:        core.StringPtr("kRegular"),
+			StartTimeUsecs: core.Int64Ptr(start.UnixMicro()),
+			Status:         core.StringPtr("Succeeded"),
+		},
+	}
+}
+
+func find(t *testing.T, copies []Copy, runID string, kind CopyKind) Copy {
+	for _, c := range copies {
+		if c.RunID == runID && c.Target.Kind == kind {
+			return c
+		}
+	}
+	t.Fatalf("copy %s/%s not found", runID, kind)
+	return Copy{}
+}
+
+func TestDays(t *testing.T) {
+	for unit, want := range map[string]int64{"Days": 4, "Weeks": 28, "Months": 120, "Years": 1460} {
+		days, err := Days(unit, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, want, days, unit)
+	}
+	_, err := Days("Fortnights", 1)
+	assert.NotNil(t, err)
+}
+
+func TestExpirationsLocalAndExtended(t *testing.T) {
+	calc, err := NewCalculator(testPolicy())
+	require.Nil(t, err)
+
+	runs := []backuprecoveryv1.ProtectionGroupRun{
+		testRun("r2", base.Add(day)),
+		testRun("r1", base),
+		testRun("r3", base.Add(31*day)),
+	}
+	copies := calc.Expirations(runs, nil)
+	require.Len(t, copies, 3)
+
+	first := find(t, copies, "r1", CopyLocal)
+	assert.Equal(t, SourceExtended, first.Source)
+	assert.Equal(t, base.Add(365*day), first.Expiry)
+	assert.Equal(t, base.Add(3*day), first.LockedUntil)
+
+	second := find(t, copies, "r2", CopyLocal)
+	assert.Equal(t, SourcePolicy, second.Source)
+	assert.Equal(t, base.Add(8*day), second.Expiry)
+
+	// r3 is the first run of April and is kept by the monthly tier.
+	assert.Equal(t, SourceExtended, find(t, copies, "r3", CopyLocal).Source)
+}
+
+func TestExpirationsRemoteTargets(t *testing.T) {
+	calc, err := NewCalculator(testPolicy())
+	require.Nil(t, err)
+
+	run := testRun("r1", base.Add(day))
+	run.ReplicationInfo = &backuprecoveryv1.ReplicationRunSummary{
+		ReplicationTargetResults: []backuprecoveryv1.ReplicationTargetResult{
+			{ClusterID: core.Int64Ptr(5), Status: core.StringPtr("Succeeded")},
+			{ClusterID: core.Int64Ptr(9), Status: core.StringPtr("Failed")},
+		},
+	}
+	run.ArchivalInfo = &backuprecoveryv1.ArchivalRunSummary{
+		ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{
+			{TargetID: core.Int64Ptr(100), Status: core.StringPtr("Succeeded")},
+			{TargetID: core.Int64Ptr(200), Status: core.StringPtr("Succeeded"), ExpiryTimeUsecs: core.Int64Ptr(base.Add(50 * day).UnixMicro())},
+		},
+	}
+	// Skip the first-of-month run so that r1 is governed by base retention only.
+	copies := calc.Expirations([]backuprecoveryv1.ProtectionGroupRun{testRun("r0", base), run}, nil)
+
+	var remote []Copy
+	for _, c := range copies {
+		if c.RunID == "r1" && c.Target.Kind != CopyLocal {
+			remote = append(remote, c)
+		}
+	}
+	require.Len(t, remote, 3)
+	assert.Equal(t, Target{Kind: CopyReplication, ID: 5, Name: "dr"}, remote[0].Target)
+	assert.Equal(t, base.Add(15*day), remote[0].Expiry)
+	assert.Equal(t, base.Add(61*day), remote[1].Expiry)
+	assert.True(t, remote[1].Managed)
+	assert.False(t, remote[2].Managed)
+	assert.Equal(t, SourceReported, remote[2].Source)
+	assert.Equal(t, base.Add(50*day), remote[2].Expiry)
+}
+
+func TestExpirationsOverridesAndDataLock(t *testing.T) {
+	calc, err := NewCalculator(testPolicy())
+	require.Nil(t, err)
+
+	runs := []backuprecoveryv1.ProtectionGroupRun{testRun("r0", base), testRun("r1", base.Add(day))}
+	overrides := []Override{
+		{RunID: "r1", Target: Target{Kind: CopyLocal}, DaysToKeep: 10},
+	}
+	extended := find(t, calc.Expirations(runs, overrides), "r1", CopyLocal)
+	assert.Equal(t, base.Add(18*day), extended.Expiry)
+	assert.Equal(t, int64(10), extended.DaysToKeep)
+
+	overrides[0].DaysToKeep = -6
+	shortened := find(t, calc.Expirations(runs, overrides), "r1", CopyLocal)
+	assert.Equal(t, SourceDataLock, shortened.Source)
+	assert.Equal(t, base.Add(4*day), shortened.Expiry)
+	assert.True(t, shortened.Locked(base.Add(2*day)))
+	assert.False(t, shortened.Locked(base.Add(5*day)))
+}
+
+func TestCalendar(t *testing.T) {
+	calc, err := NewCalculator(testPolicy())
+	require.Nil(t, err)
+
+	runs := []backuprecoveryv1.ProtectionGroupRun{testRun("r0", base)}
+	for i := 1; i < 5; i++ {
+		runs = append(runs, testRun("r"+string(rune('0'+i)), base.Add(time.Duration(i)*day)))
+	}
+	runs[2].OnLegalHold = core.BoolPtr(true)
+	copies := calc.Expirations(runs, nil)
+
+	expiring := Expiring(copies, base.Add(7*day), 4)
+	require.Len(t, expiring, 2)
+	assert.Equal(t, "r1", expiring[0].RunID)
+	assert.Equal(t, "r3", expiring[1].RunID)
+
+	calendar := Calendar(copies, base.Add(7*day), 30, nil)
+	require.Len(t, calendar, 3)
+	assert.Equal(t, time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC), calendar[0].Date)
+}
+
+func TestNewCalculatorErrors(t *testing.T) {
+	_, err := NewCalculator(&backuprecoveryv1.ProtectionPolicyResponse{})
+	assert.NotNil(t, err)
+
+	policy := testPolicy()
+	policy.BackupPolicy.Regular.Retention.Unit = core.StringPtr("Eons")
+	_, err = NewCalculator(policy)
+	assert.NotNil(t, err)
+
+	policy = testPolicy()
+	policy.RemoteTargetPolicy.ReplicationTargets[0].Retention = nil
+	_, err = NewCalculator(policy)
+	assert.ErrorIs(t, err, ErrNoRetention)
+	assert.ErrorContains(t, err, "replication retention for")
+}
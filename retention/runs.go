@@ -0,0 +1,77 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// RunLister lists the runs of a protection group.
+type RunLister interface {
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+}
+
+var _ RunLister = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// ListRuns returns every run of a protection group selected by options, in pages of options.NumRuns runs. The
+// service returns the newest runs first, so every further page ends before the oldest run of the previous one.
+func ListRuns(ctx context.Context, client RunLister, options backuprecoveryv1.GetProtectionGroupRunsOptions) ([]backuprecoveryv1.ProtectionGroupRun, error) {
+	groupID := helpers.Deref(options.ID)
+	pageSize := helpers.Deref(options.NumRuns)
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("listing runs of protection group '%s': NumRuns must be positive", groupID)
+	}
+	var runs []backuprecoveryv1.ProtectionGroupRun
+	seen := make(map[string]bool)
+	for {
+		request := options
+		page, _, err := client.GetProtectionGroupRunsWithContext(ctx, &request)
+		if err != nil {
+			return nil, fmt.Errorf("listing runs of protection group '%s': %w", groupID, err)
+		}
+		if page == nil {
+			return runs, nil
+		}
+		end := int64(math.MaxInt64)
+		if options.EndTimeUsecs != nil {
+			end = *options.EndTimeUsecs
+		}
+		oldest := end
+		for _, run := range page.Runs {
+			if id := helpers.Deref(run.ID); id != "" {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+			}
+			runs = append(runs, run)
+			if start, ok := SnapshotTime(&run); ok && start.UnixMicro() < oldest {
+				oldest = start.UnixMicro()
+			}
+		}
+		if int64(len(page.Runs)) < pageSize || oldest >= end {
+			return runs, nil
+		}
+		options.EndTimeUsecs = core.Int64Ptr(oldest - 1)
+	}
+}
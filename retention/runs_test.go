@@ -0,0 +1,69 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagingClient returns the runs started before EndTimeUsecs, newest first, at most NumRuns at a time.
+type pagingClient struct {
+	runs     []backuprecoveryv1.ProtectionGroupRun
+	requests []backuprecoveryv1.GetProtectionGroupRunsOptions
+}
+
+func (fake *pagingClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	fake.requests = append(fake.requests, *options)
+	page := &backuprecoveryv1.ProtectionGroupRunsResponse{}
+	for i := len(fake.runs) - 1; i >= 0 && int64(len(page.Runs)) < *options.NumRuns; i-- {
+		if options.EndTimeUsecs == nil || *fake.runs[i].LocalBackupInfo.StartTimeUsecs <= *options.EndTimeUsecs {
+			page.Runs = append(page.Runs, fake.runs[i])
+		}
+	}
+	return page, nil, nil
+}
+
+func TestListRuns(t *testing.T) {
+	client := &pagingClient{}
+	for i := range 5 {
+		client.runs = append(client.runs, testRun(fmt.Sprintf("r%d", i), base.Add(day*time.Duration(i))))
+	}
+	runs, err := ListRuns(context.Background(), client, backuprecoveryv1.GetProtectionGroupRunsOptions{
+		ID:      core.StringPtr("pg-1"),
+		NumRuns: core.Int64Ptr(2),
+	})
+	require.Nil(t, err)
+	var ids []string
+	for _, run := range runs {
+		ids = append(ids, *run.ID)
+	}
+	assert.Equal(t, []string{"r4", "r3", "r2", "r1", "r0"}, ids)
+	require.Len(t, client.requests, 3)
+	assert.Nil(t, client.requests[0].EndTimeUsecs)
+	assert.Equal(t, base.Add(3*day).UnixMicro()-1, *client.requests[1].EndTimeUsecs)
+
+	_, err = ListRuns(context.Background(), client, backuprecoveryv1.GetProtectionGroupRunsOptions{ID: core.StringPtr("pg-1")})
+	assert.ErrorContains(t, err, "NumRuns must be positive")
+}
@@ -0,0 +1,367 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rpo measures recovery point objective attainment of protected objects against their policy schedules.
+package rpo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/retention"
+)
+
+// Client is the subset of the BackupRecoveryV1 operations used by this package.
+type Client interface {
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetProtectionPolicyByIDWithContext(ctx context.Context, getProtectionPolicyByIdOptions *backuprecoveryv1.GetProtectionPolicyByIdOptions) (result *backuprecoveryv1.ProtectionPolicyResponse, response *core.DetailedResponse, err error)
+	GetProtectionGroupRunsWithContext(ctx context.Context, getProtectionGroupRunsOptions *backuprecoveryv1.GetProtectionGroupRunsOptions) (result *backuprecoveryv1.ProtectionGroupRunsResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// ObjectStatus is the RPO attainment of one copy location of one protected object.
+type ObjectStatus struct {
+	ProtectionGroupID   string           `json:"protectionGroupId"`
+	ProtectionGroupName string           `json:"protectionGroupName,omitempty"`
+	ObjectID            int64            `json:"objectId"`
+	ObjectName          string           `json:"objectName"`
+	Environment         string           `json:"environment,omitempty"`
+	Target              retention.Target `json:"target"`
+
+	// Objective is the allowed age of the newest recovery point, including the evaluator's grace period.
+	Objective time.Duration `json:"objective"`
+
+	// LastRecoveryPoint is the snapshot time of the newest successful copy. It is zero if there is none.
+	LastRecoveryPoint time.Time `json:"lastRecoveryPoint,omitempty"`
+
+	// Age is the time elapsed since LastRecoveryPoint at the end of the evaluated period.
+	Age time.Duration `json:"age"`
+
+	// Attainment is the percentage of the evaluated period during which the objective was met.
+	Attainment float64 `json:"attainment"`
+
+	// Breaching is true when the objective is not met at the end of the period or the cluster reports an SLA
+	// violation for the last run of the group.
+	Breaching bool `json:"breaching"`
+
+	// SlaViolated reflects IsLastRunSlaViolated of the protection group.
+	SlaViolated bool `json:"slaViolated,omitempty"`
+}
+
+// Report lists the RPO attainment of every protected object over a period.
+type Report struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Objects []ObjectStatus `json:"objects"`
+}
+
+// Breaching returns the statuses that currently breach their objective.
+func (report *Report) Breaching() []ObjectStatus {
+	var breaching []ObjectStatus
+	for _, status := range report.Objects {
+		if status.Breaching {
+			breaching = append(breaching, status)
+		}
+	}
+	return breaching
+}
+
+// Evaluator computes RPO attainment for the protection groups of a tenant.
+type Evaluator struct {
+	client   Client
+	tenantID string
+
+	// Grace is added to every schedule interval before an object is considered in breach.
+	Grace time.Duration
+
+	// Lookback is how far before the period runs are fetched to find the recovery point in effect when the period
+	// starts. Defaults to 7 days.
+	Lookback time.Duration
+
+	// Environments restricts the evaluated protection groups. All environments are evaluated when empty.
+	Environments []string
+
+	// NumRuns is the number of runs fetched per request. Longer periods are fetched in several requests. Defaults to
+	// 1000.
+	NumRuns int64
+}
+
+// NewEvaluator : Instantiate Evaluator
+func NewEvaluator(client Client, tenantID string) *Evaluator {
+	return &Evaluator{client: client, tenantID: tenantID, Lookback: 7 * day, NumRuns: 1000}
+}
+
+// objective is the RPO of one copy location of a protection group.
+type objective struct {
+	target   retention.Target
+	interval time.Duration
+}
+
+type objectKey struct {
+	id     int64
+	target retention.Target
+}
+
+// Evaluate computes the attainment of every object of every active protection group over [from, to).
+func (evaluator *Evaluator) Evaluate(ctx context.Context, from time.Time, to time.Time) (*Report, error) {
+	groups, _, err := evaluator.client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+		XIBMTenantID:       core.StringPtr(evaluator.tenantID),
+		Environments:       evaluator.Environments,
+		IsDeleted:          core.BoolPtr(false),
+		IncludeLastRunInfo: core.BoolPtr(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing protection groups: %w", err)
+	}
+	report := &Report{From: from, To: to}
+	if groups == nil {
+		return report, nil
+	}
+
+	policies := make(map[string][]objective)
+	for _, group := range groups.ProtectionGroups {
+		if helpers.Deref(group.IsPaused) || (group.IsActive != nil && !*group.IsActive) {
+			continue
+		}
+		policyID := helpers.Deref(group.PolicyID)
+		objectives, ok := policies[policyID]
+		if !ok {
+			objectives, err = evaluator.objectives(ctx, policyID)
+			if err != nil {
+				return nil, err
+			}
+			policies[policyID] = objectives
+		}
+		statuses, err := evaluator.evaluateGroup(ctx, &group, objectives, from, to)
+		if err != nil {
+			return nil, err
+		}
+		report.Objects = append(report.Objects, statuses...)
+	}
+	return report, nil
+}
+
+// objectives derives the RPO of the local copy and every remote target from a policy.
+func (evaluator *Evaluator) objectives(ctx context.Context, policyID string) ([]objective, error) {
+	policy, _, err := evaluator.client.GetProtectionPolicyByIDWithContext(ctx, &backuprecoveryv1.GetProtectionPolicyByIdOptions{
+		ID:           core.StringPtr(policyID),
+		XIBMTenantID: core.StringPtr(evaluator.tenantID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting policy '%s': %w", policyID, err)
+	}
+	if policy == nil || policy.BackupPolicy == nil || policy.BackupPolicy.Regular == nil || policy.BackupPolicy.Regular.Incremental == nil {
+		return nil, fmt.Errorf("policy '%s' has no incremental schedule", policyID)
+	}
+	local, err := Interval(policy.BackupPolicy.Regular.Incremental.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("policy '%s': %w", policyID, err)
+	}
+	objectives := []objective{{target: retention.Target{Kind: retention.CopyLocal}, interval: local}}
+	if targets := policy.RemoteTargetPolicy; targets != nil {
+		for _, replication := range targets.ReplicationTargets {
+			interval, err := TargetInterval(replication.Schedule, local)
+			if err != nil {
+				return nil, fmt.Errorf("policy '%s': %w", policyID, err)
+			}
+			target := retention.Target{Kind: retention.CopyReplication}
+			if replication.RemoteTargetConfig != nil {
+				target.ID = helpers.Deref(replication.RemoteTargetConfig.ClusterID)
+				target.Name = helpers.Deref(replication.RemoteTargetConfig.ClusterName)
+			}
+			objectives = append(objectives, objective{target: target, interval: interval})
+		}
+		for _, archival := range targets.ArchivalTargets {
+			interval, err := TargetInterval(archival.Schedule, local)
+			if err != nil {
+				return nil, fmt.Errorf("policy '%s': %w", policyID, err)
+			}
+			target := retention.Target{Kind: retention.CopyArchival, ID: helpers.Deref(archival.TargetID), Name: helpers.Deref(archival.TargetName)}
+			objectives = append(objectives, objective{target: target, interval: interval})
+		}
+	}
+	return objectives, nil
+}
+
+func (evaluator *Evaluator) evaluateGroup(ctx context.Context, group *backuprecoveryv1.ProtectionGroupResponse, objectives []objective, from time.Time, to time.Time) ([]ObjectStatus, error) {
+	runs, err := retention.ListRuns(ctx, evaluator.client, backuprecoveryv1.GetProtectionGroupRunsOptions{
+		ID:                   group.ID,
+		XIBMTenantID:         core.StringPtr(evaluator.tenantID),
+		StartTimeUsecs:       core.Int64Ptr(from.Add(-evaluator.Lookback).UnixMicro()),
+		EndTimeUsecs:         core.Int64Ptr(to.UnixMicro()),
+		NumRuns:              core.Int64Ptr(evaluator.NumRuns),
+		IncludeObjectDetails: core.BoolPtr(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[objectKey]backuprecoveryv1.ObjectSummary)
+	recovery := make(map[objectKey][]RecoveryPoint)
+	var order []objectKey
+	track := func(key objectKey, object *backuprecoveryv1.ObjectSummary, point RecoveryPoint, ok bool) {
+		if _, seen := objects[key]; !seen {
+			order = append(order, key)
+			objects[key] = *object
+		}
+		if ok && !point.Available.After(to) {
+			recovery[key] = append(recovery[key], point)
+		}
+	}
+	// Every object of the last run and of the fetched runs is evaluated against every objective of the policy, so
+	// that objects whose copies all failed or never ran are reported with no recovery point.
+	seed := func(results []backuprecoveryv1.ObjectRunResult) {
+		for _, result := range results {
+			if result.Object == nil {
+				continue
+			}
+			for _, goal := range objectives {
+				target := retention.Target{Kind: goal.target.Kind, ID: goal.target.ID}
+				track(objectKey{id: helpers.Deref(result.Object.ID), target: target}, result.Object, RecoveryPoint{}, false)
+			}
+		}
+	}
+	if group.LastRun != nil {
+		seed(group.LastRun.Objects)
+	}
+	for _, run := range runs {
+		seed(run.Objects)
+		for _, result := range run.Objects {
+			if result.Object == nil {
+				continue
+			}
+			id := helpers.Deref(result.Object.ID)
+			if local := result.LocalSnapshotInfo; local != nil && local.SnapshotInfo != nil {
+				point, ok := snapshotPoint(local.SnapshotInfo)
+				track(objectKey{id: id, target: retention.Target{Kind: retention.CopyLocal}}, result.Object, point, ok)
+			}
+			if result.ReplicationInfo != nil {
+				for _, target := range result.ReplicationInfo.ReplicationTargetResults {
+					point, ok := copyPoint(&run, target.Status, target.EndTimeUsecs)
+					key := objectKey{id: id, target: retention.Target{Kind: retention.CopyReplication, ID: helpers.Deref(target.ClusterID)}}
+					track(key, result.Object, point, ok)
+				}
+			}
+			if result.ArchivalInfo != nil {
+				for _, target := range result.ArchivalInfo.ArchivalTargetResults {
+					point, ok := copyPoint(&run, target.Status, target.EndTimeUsecs)
+					key := objectKey{id: id, target: retention.Target{Kind: retention.CopyArchival, ID: helpers.Deref(target.TargetID)}}
+					track(key, result.Object, point, ok)
+				}
+			}
+		}
+	}
+	// A group none of whose objects is known is reported as a whole, with object ID zero.
+	if len(order) == 0 {
+		for _, goal := range objectives {
+			target := retention.Target{Kind: goal.target.Kind, ID: goal.target.ID}
+			track(objectKey{target: target}, &backuprecoveryv1.ObjectSummary{Name: group.Name, Environment: group.Environment}, RecoveryPoint{}, false)
+		}
+	}
+
+	slaViolated := lastRunSlaViolated(group.LastRun)
+	var statuses []ObjectStatus
+	for _, key := range order {
+		var goal *objective
+		for i := range objectives {
+			if objectives[i].target.Kind == key.target.Kind && objectives[i].target.ID == key.target.ID {
+				goal = &objectives[i]
+			}
+		}
+		if goal == nil {
+			continue
+		}
+		object := objects[key]
+		status := ObjectStatus{
+			ProtectionGroupID:   helpers.Deref(group.ID),
+			ProtectionGroupName: helpers.Deref(group.Name),
+			ObjectID:            key.id,
+			ObjectName:          helpers.Deref(object.Name),
+			Environment:         helpers.Deref(object.Environment),
+			Target:              goal.target,
+			Objective:           goal.interval + evaluator.Grace,
+			SlaViolated:         slaViolated,
+		}
+		for _, point := range recovery[key] {
+			if point.Point.After(status.LastRecoveryPoint) {
+				status.LastRecoveryPoint = point.Point
+			}
+		}
+		if status.LastRecoveryPoint.IsZero() {
+			status.Age = to.Sub(from.Add(-evaluator.Lookback))
+		} else {
+			status.Age = to.Sub(status.LastRecoveryPoint)
+		}
+		status.Attainment = Attainment(recovery[key], status.Objective, from, to)
+		status.Breaching = status.LastRecoveryPoint.IsZero() || status.Age > status.Objective || slaViolated
+		statuses = append(statuses, status)
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].ObjectID < statuses[j].ObjectID })
+	return statuses, nil
+}
+
+func snapshotPoint(info *backuprecoveryv1.SnapshotInfo) (RecoveryPoint, bool) {
+	if !succeeded(info.Status) || info.StartTimeUsecs == nil || info.EndTimeUsecs == nil {
+		return RecoveryPoint{}, false
+	}
+	return RecoveryPoint{Point: usecs(*info.StartTimeUsecs), Available: usecs(*info.EndTimeUsecs)}, true
+}
+
+// copyPoint returns the recovery point a remote copy provides: the snapshot time of the run, available once the
+// copy has completed.
+func copyPoint(run *backuprecoveryv1.ProtectionGroupRun, status *string, endTimeUsecs *int64) (RecoveryPoint, bool) {
+	snapshot, ok := retention.SnapshotTime(run)
+	if !ok || !succeeded(status) || endTimeUsecs == nil {
+		return RecoveryPoint{}, false
+	}
+	return RecoveryPoint{Point: snapshot, Available: usecs(*endTimeUsecs)}, true
+}
+
+func lastRunSlaViolated(run *backuprecoveryv1.ProtectionGroupRun) bool {
+	if run == nil {
+		return false
+	}
+	if run.LocalBackupInfo != nil && helpers.Deref(run.LocalBackupInfo.IsSlaViolated) {
+		return true
+	}
+	if run.ArchivalInfo != nil {
+		for _, target := range run.ArchivalInfo.ArchivalTargetResults {
+			if helpers.Deref(target.IsSlaViolated) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func succeeded(status *string) bool {
+	switch helpers.Deref(status) {
+	case backuprecoveryv1.SnapshotInfo_Status_Ksuccessful, backuprecoveryv1.SnapshotInfo_Status_Kwarning,
+		backuprecoveryv1.BackupRunSummary_Status_Succeeded, backuprecoveryv1.BackupRunSummary_Status_Succeededwithwarning:
+		return true
+	}
+	return false
+}
+
+func usecs(value int64) time.Time {
+	return time.UnixMicro(value).UTC()
+}
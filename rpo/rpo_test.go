@@ -0,0 +1,226 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC)
+
+func TestInterval(t *testing.T) {
+	hourly := &backuprecoveryv1.IncrementalSchedule{Unit: core.StringPtr("Hours"), HourSchedule: &backuprecoveryv1.HourSchedule{Frequency: core.Int64Ptr(4)}}
+	interval, err := Interval(hourly)
+	require.Nil(t, err)
+	assert.Equal(t, 4*time.Hour, interval)
+
+	weekly := &backuprecoveryv1.IncrementalSchedule{Unit: core.StringPtr("Weeks"), WeekSchedule: &backuprecoveryv1.WeekSchedule{DayOfWeek: []string{"Monday", "Friday"}}}
+	interval, err = Interval(weekly)
+	require.Nil(t, err)
+	assert.Equal(t, 4*day, interval)
+
+	_, err = Interval(&backuprecoveryv1.IncrementalSchedule{Unit: core.StringPtr("Days")})
+	assert.NotNil(t, err)
+
+	interval, err = TargetInterval(&backuprecoveryv1.TargetSchedule{Unit: core.StringPtr("Runs"), Frequency: core.Int64Ptr(3)}, time.Hour)
+	require.Nil(t, err)
+	assert.Equal(t, 3*time.Hour, interval)
+}
+
+func TestAttainment(t *testing.T) {
+	points := []RecoveryPoint{
+		{Point: base.Add(-2 * time.Hour), Available: base.Add(-time.Hour)},
+		{Point: base.Add(6 * time.Hour), Available: base.Add(7 * time.Hour)},
+	}
+	// The objective of 4h is met until base+2h and again from base+7h: 5 of 10 hours are in breach.
+	assert.InDelta(t, 50.0, Attainment(points, 4*time.Hour, base, base.Add(10*time.Hour)), 0.001)
+	assert.InDelta(t, 100.0, Attainment(points, 9*time.Hour, base, base.Add(10*time.Hour)), 0.001)
+	assert.InDelta(t, 0.0, Attainment(nil, time.Hour, base, base.Add(10*time.Hour)), 0.001)
+}
+
+type fakeClient struct{}
+
+func (fakeClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: []backuprecoveryv1.ProtectionGroupResponse{
+		{ID: core.StringPtr("pg-1"), Name: core.StringPtr("hosts"), PolicyID: core.StringPtr("p-1")},
+		{ID: core.StringPtr("pg-2"), PolicyID: core.StringPtr("p-1"), IsPaused: core.BoolPtr(true)},
+	}}, nil, nil
+}
+
+func (fakeClient) GetProtectionPolicyByIDWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionPolicyByIdOptions) (*backuprecoveryv1.ProtectionPolicyResponse, *core.DetailedResponse, error) {
+	return &backuprecoveryv1.ProtectionPolicyResponse{
+		ID: options.ID,
+		BackupPolicy: &backuprecoveryv1.BackupPolicy{Regular: &backuprecoveryv1.RegularBackupPolicy{
+			Incremental: &backuprecoveryv1.IncrementalBackupPolicy{Schedule: &backuprecoveryv1.IncrementalSchedule{
+				Unit: core.StringPtr("Hours"), HourSchedule: &backuprecoveryv1.HourSchedule{Frequency: core.Int64Ptr(6)},
+			}},
+		}},
+		RemoteTargetPolicy: &backuprecoveryv1.TargetsConfiguration{ArchivalTargets: []backuprecoveryv1.ArchivalTargetConfiguration{{
+			TargetID: core.Int64Ptr(9), TargetName: core.StringPtr("vault"),
+			Schedule: &backuprecoveryv1.TargetSchedule{Unit: core.StringPtr("Days"), Frequency: core.Int64Ptr(1)},
+		}}},
+	}, nil, nil
+}
+
+func run(start time.Time, objects ...backuprecoveryv1.ObjectRunResult) backuprecoveryv1.ProtectionGroupRun {
+	return backuprecoveryv1.ProtectionGroupRun{
+		ID:              core.StringPtr(start.Format(time.RFC3339)),
+		LocalBackupInfo: &backuprecoveryv1.BackupRunSummary{StartTimeUsecs: core.Int64Ptr(start.UnixMicro()), Status: core.StringPtr("Succeeded")},
+		Objects:         objects,
+	}
+}
+
+func local(id int64, start time.Time, status string) backuprecoveryv1.ObjectRunResult {
+	return backuprecoveryv1.ObjectRunResult{
+		Object: &backuprecoveryv1.ObjectSummary{ID: core.Int64Ptr(id), Name: core.StringPtr("host"), Environment: core.StringPtr("kPhysical")},
+		LocalSnapshotInfo: &backuprecoveryv1.BackupRun{SnapshotInfo: &backuprecoveryv1.SnapshotInfo{
+			Status:         core.StringPtr(status),
+			StartTimeUsecs: core.Int64Ptr(start.UnixMicro()),
+			EndTimeUsecs:   core.Int64Ptr(start.Add(30 * time.Minute).UnixMicro()),
+		}},
+	}
+}
+
+func (fakeClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	archived := local(1, base, "kSuccessful")
+	archived.ArchivalInfo = &backuprecoveryv1.ArchivalRun{ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{{
+		TargetID: core.Int64Ptr(9), Status: core.StringPtr("Succeeded"), EndTimeUsecs: core.Int64Ptr(base.Add(2 * time.Hour).UnixMicro()),
+	}}}
+	return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: []backuprecoveryv1.ProtectionGroupRun{
+		run(base, archived, local(2, base, "kSuccessful")),
+		run(base.Add(6*time.Hour), local(1, base.Add(6*time.Hour), "kSuccessful"), local(2, base.Add(6*time.Hour), "kFailed")),
+	}}, nil, nil
+}
+
+func TestEvaluate(t *testing.T) {
+	evaluator := NewEvaluator(fakeClient{}, "tenant")
+	evaluator.Grace = time.Hour
+
+	report, err := evaluator.Evaluate(context.Background(), base.Add(time.Hour), base.Add(13*time.Hour))
+	require.Nil(t, err)
+	require.Len(t, report.Objects, 4)
+
+	host1 := report.Objects[0]
+	assert.Equal(t, int64(1), host1.ObjectID)
+	assert.Equal(t, retention.CopyLocal, host1.Target.Kind)
+	assert.Equal(t, 7*time.Hour, host1.Objective)
+	assert.InDelta(t, 100.0, host1.Attainment, 0.001)
+	assert.False(t, host1.Breaching)
+
+	vault := report.Objects[1]
+	assert.Equal(t, retention.Target{Kind: retention.CopyArchival, ID: 9, Name: "vault"}, vault.Target)
+	assert.Equal(t, 25*time.Hour, vault.Objective)
+	// No archive is available before base+2h, so the first hour of the period counts as a breach.
+	assert.InDelta(t, 100.0*11/12, vault.Attainment, 0.001)
+	assert.False(t, vault.Breaching)
+
+	// Host 2 last succeeded at base, so its 7h objective lapsed at base+7h: 6 of 12 hours are in breach.
+	host2 := report.Objects[2]
+	assert.Equal(t, int64(2), host2.ObjectID)
+	assert.InDelta(t, 50.0, host2.Attainment, 0.001)
+	assert.True(t, host2.Breaching)
+	assert.Equal(t, 13*time.Hour, host2.Age)
+
+	// Host 2 was never archived, so its archival copy has no recovery point at all.
+	host2Vault := report.Objects[3]
+	assert.Equal(t, int64(2), host2Vault.ObjectID)
+	assert.Equal(t, retention.CopyArchival, host2Vault.Target.Kind)
+	assert.True(t, host2Vault.LastRecoveryPoint.IsZero())
+	assert.InDelta(t, 0.0, host2Vault.Attainment, 0.001)
+	assert.True(t, host2Vault.Breaching)
+	assert.Len(t, report.Breaching(), 2)
+}
+
+// monthClient serves hourly runs of pg-1 over May 2026 in pages, newest first, a group pg-2 whose runs all
+// failed before the period and a group pg-3 without any run.
+type monthClient struct {
+	fakeClient
+	requests int
+}
+
+func (client *monthClient) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	lastRun := run(base.Add(-10*day), local(7, base.Add(-10*day), "kFailed"))
+	return &backuprecoveryv1.ProtectionGroupsResponse{ProtectionGroups: []backuprecoveryv1.ProtectionGroupResponse{
+		{ID: core.StringPtr("pg-1"), Name: core.StringPtr("hosts"), PolicyID: core.StringPtr("p-1")},
+		{ID: core.StringPtr("pg-2"), Name: core.StringPtr("failing"), PolicyID: core.StringPtr("p-1"), LastRun: &lastRun},
+		{ID: core.StringPtr("pg-3"), Name: core.StringPtr("idle"), PolicyID: core.StringPtr("p-1")},
+	}}, nil, nil
+}
+
+func (client *monthClient) GetProtectionGroupRunsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupRunsOptions) (*backuprecoveryv1.ProtectionGroupRunsResponse, *core.DetailedResponse, error) {
+	if *options.ID != "pg-1" {
+		return &backuprecoveryv1.ProtectionGroupRunsResponse{}, nil, nil
+	}
+	client.requests++
+	var runs []backuprecoveryv1.ProtectionGroupRun
+	for start := usecs(*options.EndTimeUsecs).Truncate(time.Hour); !start.Before(usecs(*options.StartTimeUsecs)); start = start.Add(-time.Hour) {
+		if int64(len(runs)) == *options.NumRuns {
+			break
+		}
+		if start.UnixMicro() > *options.EndTimeUsecs {
+			continue
+		}
+		result := local(1, start, "kSuccessful")
+		result.ArchivalInfo = &backuprecoveryv1.ArchivalRun{ArchivalTargetResults: []backuprecoveryv1.ArchivalTargetResult{{
+			TargetID: core.Int64Ptr(9), Status: core.StringPtr("Succeeded"), EndTimeUsecs: core.Int64Ptr(start.Add(time.Hour).UnixMicro()),
+		}}}
+		runs = append(runs, run(start, result))
+	}
+	return &backuprecoveryv1.ProtectionGroupRunsResponse{Runs: runs}, nil, nil
+}
+
+func TestEvaluateMonth(t *testing.T) {
+	client := &monthClient{}
+	evaluator := NewEvaluator(client, "tenant")
+	evaluator.NumRuns = 100
+	from, to := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := evaluator.Evaluate(context.Background(), from, to)
+	require.Nil(t, err)
+	// 38 days of hourly runs including the lookback, 100 per request.
+	assert.Equal(t, 10, client.requests)
+
+	require.Len(t, report.Objects, 6)
+	hosts := report.Objects[:2]
+	for _, status := range hosts {
+		assert.Equal(t, "pg-1", status.ProtectionGroupID)
+		assert.InDelta(t, 100.0, status.Attainment, 0.001)
+		assert.False(t, status.Breaching)
+	}
+	// The run at the end of the period only completes after it.
+	assert.Equal(t, to.Add(-time.Hour), hosts[0].LastRecoveryPoint)
+
+	// The objects of pg-2 are known from its last run and those of pg-3 not at all.
+	for _, status := range report.Objects[2:] {
+		assert.True(t, status.LastRecoveryPoint.IsZero())
+		assert.InDelta(t, 0.0, status.Attainment, 0.001)
+		assert.True(t, status.Breaching)
+	}
+	assert.Equal(t, int64(7), report.Objects[2].ObjectID)
+	assert.Equal(t, "pg-3", report.Objects[4].ProtectionGroupID)
+	assert.Equal(t, int64(0), report.Objects[4].ObjectID)
+	assert.Equal(t, "idle", report.Objects[4].ObjectName)
+	assert.Len(t, report.Breaching(), 4)
+}
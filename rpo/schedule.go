@@ -0,0 +1,177 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+const day = 24 * time.Hour
+
+var weekdays = map[string]int{
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Sunday:    0,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Monday:    1,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Tuesday:   2,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Wednesday: 3,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Thursday:  4,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Friday:    5,
+	backuprecoveryv1.WeekSchedule_DayOfWeek_Saturday:  6,
+}
+
+// Interval returns the longest gap between two consecutive runs of an incremental schedule, which is the recovery
+// point objective the schedule implies. Weekly schedules use the widest gap between the selected days; monthly and
+// yearly schedules assume the longest month and year.
+func Interval(schedule *backuprecoveryv1.IncrementalSchedule) (time.Duration, error) {
+	if schedule == nil {
+		return 0, fmt.Errorf("no incremental schedule")
+	}
+	switch unit := helpers.Deref(schedule.Unit); unit {
+	case backuprecoveryv1.IncrementalSchedule_Unit_Minutes:
+		if schedule.MinuteSchedule != nil {
+			return frequency(schedule.MinuteSchedule.Frequency) * time.Minute, nil
+		}
+	case backuprecoveryv1.IncrementalSchedule_Unit_Hours:
+		if schedule.HourSchedule != nil {
+			return frequency(schedule.HourSchedule.Frequency) * time.Hour, nil
+		}
+	case backuprecoveryv1.IncrementalSchedule_Unit_Days:
+		if schedule.DaySchedule != nil {
+			return frequency(schedule.DaySchedule.Frequency) * day, nil
+		}
+	case backuprecoveryv1.IncrementalSchedule_Unit_Weeks:
+		if schedule.WeekSchedule != nil {
+			return weeklyGap(schedule.WeekSchedule.DayOfWeek), nil
+		}
+	case backuprecoveryv1.IncrementalSchedule_Unit_Months:
+		return 31 * day, nil
+	case backuprecoveryv1.IncrementalSchedule_Unit_Years:
+		return 366 * day, nil
+	default:
+		return 0, fmt.Errorf("unsupported schedule unit '%s'", unit)
+	}
+	return 0, fmt.Errorf("schedule unit '%s' has no matching schedule", helpers.Deref(schedule.Unit))
+}
+
+// TargetInterval returns the recovery point objective of a replication or archival target schedule. A schedule in
+// runs is expressed as a multiple of the local interval.
+func TargetInterval(schedule *backuprecoveryv1.TargetSchedule, local time.Duration) (time.Duration, error) {
+	if schedule == nil {
+		return local, nil
+	}
+	n := frequency(schedule.Frequency)
+	switch unit := helpers.Deref(schedule.Unit); unit {
+	case backuprecoveryv1.TargetSchedule_Unit_Runs:
+		return n * local, nil
+	case backuprecoveryv1.TargetSchedule_Unit_Hours:
+		return n * time.Hour, nil
+	case backuprecoveryv1.TargetSchedule_Unit_Days:
+		return n * day, nil
+	case backuprecoveryv1.TargetSchedule_Unit_Weeks:
+		return n * 7 * day, nil
+	case backuprecoveryv1.TargetSchedule_Unit_Months:
+		return n * 31 * day, nil
+	case backuprecoveryv1.TargetSchedule_Unit_Years:
+		return n * 366 * day, nil
+	default:
+		return 0, fmt.Errorf("unsupported target schedule unit '%s'", unit)
+	}
+}
+
+func frequency(value *int64) time.Duration {
+	if value == nil || *value < 1 {
+		return 1
+	}
+	return time.Duration(*value)
+}
+
+func weeklyGap(days []string) time.Duration {
+	var selected []int
+	for _, name := range days {
+		if index, ok := weekdays[name]; ok {
+			selected = append(selected, index)
+		}
+	}
+	if len(selected) == 0 {
+		return 7 * day
+	}
+	sort.Ints(selected)
+	widest := selected[0] + 7 - selected[len(selected)-1]
+	for i := 1; i < len(selected); i++ {
+		widest = max(widest, selected[i]-selected[i-1])
+	}
+	return time.Duration(widest) * day
+}
+
+// RecoveryPoint is a copy that became usable at Available and lets the object be restored to Point.
+type RecoveryPoint struct {
+	Point     time.Time `json:"point"`
+	Available time.Time `json:"available"`
+}
+
+// Attainment returns the percentage of the window [from, to) during which the age of the newest available recovery
+// point was within the objective. Points may be given in any order and may start before the window.
+func Attainment(points []RecoveryPoint, objective time.Duration, from time.Time, to time.Time) float64 {
+	if !to.After(from) {
+		return 100
+	}
+	ordered := append([]RecoveryPoint(nil), points...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Available.Before(ordered[j].Available) })
+
+	var breached time.Duration
+	var newest time.Time
+	cursor := from
+	for _, p := range ordered {
+		if p.Available.After(cursor) {
+			breached += breach(newest, objective, cursor, minTime(p.Available, to))
+			cursor = minTime(p.Available, to)
+		}
+		if p.Point.After(newest) {
+			newest = p.Point
+		}
+		if !cursor.Before(to) {
+			break
+		}
+	}
+	if cursor.Before(to) {
+		breached += breach(newest, objective, cursor, to)
+	}
+	return 100 * (1 - float64(breached)/float64(to.Sub(from)))
+}
+
+// breach returns how much of [start, end) lies past the objective of the given recovery point.
+func breach(point time.Time, objective time.Duration, start time.Time, end time.Time) time.Duration {
+	limit := start
+	if !point.IsZero() && point.Add(objective).After(start) {
+		limit = point.Add(objective)
+	}
+	if !end.After(limit) {
+		return 0
+	}
+	return end.Sub(limit)
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,409 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// PreflightClient is the subset of the management SRE API used by Checker.
+type PreflightClient interface {
+	CompatibleClustersForReleaseWithContext(ctx context.Context, compatibleClustersForReleaseOptions *backuprecoveryv1.CompatibleClustersForReleaseOptions) (result []backuprecoveryv1.CompatibleCluster, response *core.DetailedResponse, err error)
+	ClustersUpgradesInfoWithContext(ctx context.Context, clustersUpgradesInfoOptions *backuprecoveryv1.ClustersUpgradesInfoOptions) (result []backuprecoveryv1.UpgradeInfo, response *core.DetailedResponse, err error)
+	GetManagementAlertsWithContext(ctx context.Context, getManagementAlertsOptions *backuprecoveryv1.GetManagementAlertsOptions) (result *backuprecoveryv1.AlertsList, response *core.DetailedResponse, err error)
+}
+
+var _ PreflightClient = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// WorkloadClient is the subset of the BackupRecoveryV1 operations used to find running work on a cluster.
+type WorkloadClient interface {
+	GetProtectionGroupsWithContext(ctx context.Context, getProtectionGroupsOptions *backuprecoveryv1.GetProtectionGroupsOptions) (result *backuprecoveryv1.ProtectionGroupsResponse, response *core.DetailedResponse, err error)
+	GetRecoveriesWithContext(ctx context.Context, getRecoveriesOptions *backuprecoveryv1.GetRecoveriesOptions) (result *backuprecoveryv1.RecoveriesResponse, response *core.DetailedResponse, err error)
+}
+
+var _ WorkloadClient = (*backuprecoveryv1.BackupRecoveryV1)(nil)
+
+// Workload is the data plane client of one cluster.
+type Workload struct {
+	Client   WorkloadClient
+	TenantID string
+}
+
+// Result is the outcome of a pre-flight check. Results are ordered: pass < warn < fail.
+type Result string
+
+const (
+	ResultPass Result = "pass"
+	ResultWarn Result = "warn"
+	ResultFail Result = "fail"
+)
+
+func (result Result) rank() int {
+	switch result {
+	case ResultFail:
+		return 2
+	case ResultWarn:
+		return 1
+	}
+	return 0
+}
+
+func worse(a Result, b Result) Result {
+	if b.rank() > a.rank() {
+		return b
+	}
+	return a
+}
+
+// Names of the pre-flight checks, in the order they appear in the matrix.
+const (
+	CheckCompatible     = "compatible"
+	CheckCriticalAlerts = "critical_alerts"
+	CheckProtectionRuns = "protection_runs"
+	CheckRecoveries     = "recoveries"
+	CheckPendingUpgrade = "pending_upgrade"
+)
+
+var checkNames = []string{CheckCompatible, CheckCriticalAlerts, CheckProtectionRuns, CheckRecoveries, CheckPendingUpgrade}
+
+// CheckResult is the outcome of one check of one cluster.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Result Result `json:"result"`
+	Reason string `json:"reason,omitempty"`
+
+	// Items lists what caused a warning or failure, e.g. alert names or running groups.
+	Items []string `json:"items,omitempty"`
+}
+
+// ClusterPreflight is the pre-flight outcome of one cluster.
+type ClusterPreflight struct {
+	Cluster Cluster       `json:"cluster"`
+	Result  Result        `json:"result"`
+	Checks  []CheckResult `json:"checks"`
+}
+
+func (cluster *ClusterPreflight) add(check CheckResult) {
+	cluster.Checks = append(cluster.Checks, check)
+	cluster.Result = worse(cluster.Result, check.Result)
+}
+
+// PreflightReport is the pass/warn/fail matrix of a set of clusters for a target version.
+type PreflightReport struct {
+	TargetVersion string             `json:"targetVersion"`
+	GeneratedAt   time.Time          `json:"generatedAt"`
+	Clusters      []ClusterPreflight `json:"clusters"`
+}
+
+// Result returns the worst result of all clusters.
+func (report *PreflightReport) Result() Result {
+	result := ResultPass
+	for _, cluster := range report.Clusters {
+		result = worse(result, cluster.Result)
+	}
+	return result
+}
+
+// Blocked returns the clusters that fail, or also those that warn when strict is set.
+func (report *PreflightReport) Blocked(strict bool) []ClusterPreflight {
+	var blocked []ClusterPreflight
+	for _, cluster := range report.Clusters {
+		if cluster.Result == ResultFail || (strict && cluster.Result == ResultWarn) {
+			blocked = append(blocked, cluster)
+		}
+	}
+	return blocked
+}
+
+// WriteMatrix writes the report as a table with one row per cluster and one column per check, followed by the
+// reasons of every warning and failure.
+func (report *PreflightReport) WriteMatrix(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "CLUSTER\tRESULT\t%s\n", strings.ToUpper(strings.Join(checkNames, "\t")))
+	for _, cluster := range report.Clusters {
+		cells := make([]string, len(checkNames))
+		for i, name := range checkNames {
+			cells[i] = "-"
+			for _, check := range cluster.Checks {
+				if check.Name == name {
+					cells[i] = string(check.Result)
+				}
+			}
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", cluster.Cluster, cluster.Result, strings.Join(cells, "\t"))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	for _, cluster := range report.Clusters {
+		for _, check := range cluster.Checks {
+			if check.Result == ResultPass {
+				continue
+			}
+			line := fmt.Sprintf("%s %s %s: %s", cluster.Cluster, check.Result, check.Name, check.Reason)
+			if len(check.Items) > 0 {
+				line += " (" + strings.Join(check.Items, ", ") + ")"
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PreflightError is returned by the Gate of a Checker when clusters are blocked.
+type PreflightError struct {
+	Report  *PreflightReport
+	Blocked []ClusterPreflight
+}
+
+func (err *PreflightError) Error() string {
+	var clusters []string
+	for _, cluster := range err.Blocked {
+		var reasons []string
+		for _, check := range cluster.Checks {
+			if check.Result != ResultPass {
+				reasons = append(reasons, check.Name+": "+check.Reason)
+			}
+		}
+		clusters = append(clusters, fmt.Sprintf("%s (%s)", cluster.Cluster, strings.Join(reasons, "; ")))
+	}
+	return "pre-flight checks failed for " + strings.Join(clusters, ", ")
+}
+
+// Checker runs the pre-flight checks of clusters before an upgrade.
+type Checker struct {
+	client PreflightClient
+
+	// Workloads are the data plane clients by cluster id, used to find running protection runs and recoveries.
+	// Clusters without one get a warning for these checks.
+	Workloads map[int64]Workload
+
+	// MaxItems is the maximum number of alerts, runs and recoveries listed per check. Defaults to 20.
+	MaxItems int
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewChecker : Instantiate Checker
+func NewChecker(client PreflightClient) *Checker {
+	return &Checker{
+		client:    client,
+		Workloads: map[int64]Workload{},
+		MaxItems:  20,
+		Now:       time.Now,
+	}
+}
+
+// Check runs every check against every cluster. Checks that cannot be evaluated because an API call failed are
+// reported as failures, so an outage never lets an upgrade through.
+func (checker *Checker) Check(ctx context.Context, targetVersion string, clusters []Cluster) (*PreflightReport, error) {
+	report := &PreflightReport{TargetVersion: targetVersion, GeneratedAt: checker.Now().UTC()}
+
+	compatible, compatibleErr := checker.compatible(ctx, targetVersion)
+	identifiers := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		identifiers = append(identifiers, cluster.Identifier())
+	}
+	upgrades, _, upgradesErr := checker.client.ClustersUpgradesInfoWithContext(ctx, &backuprecoveryv1.ClustersUpgradesInfoOptions{
+		ClusterIdentifiers: identifiers,
+	})
+
+	for _, cluster := range clusters {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		preflight := ClusterPreflight{Cluster: cluster, Result: ResultPass}
+		preflight.add(checkCompatible(compatible, compatibleErr, cluster, targetVersion))
+		preflight.add(checker.checkAlerts(ctx, cluster))
+		preflight.add(checker.checkRuns(ctx, cluster))
+		preflight.add(checker.checkRecoveries(ctx, cluster))
+		preflight.add(checkPendingUpgrade(upgrades, upgradesErr, cluster))
+		report.Clusters = append(report.Clusters, preflight)
+	}
+	return report, nil
+}
+
+// Gate returns a function for Orchestrator.BeforeWave that checks the clusters of the wave and blocks it with a
+// *PreflightError when a cluster fails, or also warns when strict is set. The report is passed to onReport, which
+// may be nil.
+func (checker *Checker) Gate(strict bool, onReport func(*PreflightReport)) func(ctx context.Context, state *State, wave *Wave) error {
+	return func(ctx context.Context, state *State, wave *Wave) error {
+		var clusters []Cluster
+		for _, cluster := range wave.Clusters {
+			if cluster.Status == StatusPending {
+				clusters = append(clusters, cluster.Cluster)
+			}
+		}
+		report, err := checker.Check(ctx, state.Request.TargetVersion, clusters)
+		if err != nil {
+			return err
+		}
+		if onReport != nil {
+			onReport(report)
+		}
+		if blocked := report.Blocked(strict); len(blocked) > 0 {
+			return &PreflightError{Report: report, Blocked: blocked}
+		}
+		return nil
+	}
+}
+
+func (checker *Checker) compatible(ctx context.Context, targetVersion string) (map[int64]bool, error) {
+	result, _, err := checker.client.CompatibleClustersForReleaseWithContext(ctx, &backuprecoveryv1.CompatibleClustersForReleaseOptions{
+		ReleaseVersion: core.StringPtr(targetVersion),
+	})
+	if err != nil {
+		return nil, err
+	}
+	compatible := map[int64]bool{}
+	for _, cluster := range result {
+		compatible[helpers.Deref(cluster.ClusterID)] = true
+	}
+	return compatible, nil
+}
+
+func checkCompatible(compatible map[int64]bool, err error, cluster Cluster, targetVersion string) CheckResult {
+	check := CheckResult{Name: CheckCompatible, Result: ResultPass}
+	switch {
+	case err != nil:
+		check.Result, check.Reason = ResultFail, "listing compatible clusters failed: "+err.Error()
+	case !compatible[cluster.ID]:
+		check.Result, check.Reason = ResultFail, "not compatible with "+targetVersion
+	}
+	return check
+}
+
+func (checker *Checker) checkAlerts(ctx context.Context, cluster Cluster) CheckResult {
+	check := CheckResult{Name: CheckCriticalAlerts, Result: ResultPass}
+	result, _, err := checker.client.GetManagementAlertsWithContext(ctx, &backuprecoveryv1.GetManagementAlertsOptions{
+		ClusterIdentifiers: []string{cluster.Identifier()},
+		AlertSeverityList:  []string{backuprecoveryv1.Alert_Severity_Kcritical},
+		AlertStateList:     []string{backuprecoveryv1.Alert_AlertState_Kopen},
+		MaxAlerts:          core.Int64Ptr(int64(checker.MaxItems)),
+	})
+	if err != nil {
+		check.Result, check.Reason = ResultFail, "listing alerts failed: "+err.Error()
+		return check
+	}
+	if result == nil || len(result.AlertsList) == 0 {
+		return check
+	}
+	check.Result = ResultFail
+	check.Reason = fmt.Sprintf("%d critical open alerts", len(result.AlertsList))
+	if len(result.AlertsList) >= checker.MaxItems {
+		check.Reason = fmt.Sprintf("at least %d critical open alerts", len(result.AlertsList))
+	}
+	for _, alert := range result.AlertsList {
+		name := helpers.Deref(alert.ID)
+		if alert.AlertDocument != nil && alert.AlertDocument.AlertName != nil {
+			name = *alert.AlertDocument.AlertName
+		}
+		check.Items = append(check.Items, name)
+	}
+	return check
+}
+
+func (checker *Checker) checkRuns(ctx context.Context, cluster Cluster) CheckResult {
+	check := CheckResult{Name: CheckProtectionRuns, Result: ResultPass}
+	workload, ok := checker.Workloads[cluster.ID]
+	if !ok {
+		check.Result, check.Reason = ResultWarn, "no data plane client to check"
+		return check
+	}
+	result, _, err := workload.Client.GetProtectionGroupsWithContext(ctx, &backuprecoveryv1.GetProtectionGroupsOptions{
+		XIBMTenantID:       core.StringPtr(workload.TenantID),
+		IsDeleted:          core.BoolPtr(false),
+		LastRunAnyStatus:   []string{backuprecoveryv1.GetProtectionGroupsOptions_LastRunAnyStatus_Running},
+		IncludeLastRunInfo: core.BoolPtr(true),
+	})
+	if err != nil {
+		check.Result, check.Reason = ResultFail, "listing protection groups failed: "+err.Error()
+		return check
+	}
+	if result == nil || len(result.ProtectionGroups) == 0 {
+		return check
+	}
+	check.Result = ResultWarn
+	check.Reason = fmt.Sprintf("%d protection groups running", len(result.ProtectionGroups))
+	for _, group := range result.ProtectionGroups[:min(len(result.ProtectionGroups), checker.MaxItems)] {
+		check.Items = append(check.Items, helpers.Deref(group.Name))
+	}
+	return check
+}
+
+func (checker *Checker) checkRecoveries(ctx context.Context, cluster Cluster) CheckResult {
+	check := CheckResult{Name: CheckRecoveries, Result: ResultPass}
+	workload, ok := checker.Workloads[cluster.ID]
+	if !ok {
+		check.Result, check.Reason = ResultWarn, "no data plane client to check"
+		return check
+	}
+	result, _, err := workload.Client.GetRecoveriesWithContext(ctx, &backuprecoveryv1.GetRecoveriesOptions{
+		XIBMTenantID: core.StringPtr(workload.TenantID),
+		Status:       []string{backuprecoveryv1.GetRecoveriesOptions_Status_Running, backuprecoveryv1.GetRecoveriesOptions_Status_Accepted},
+	})
+	if err != nil {
+		check.Result, check.Reason = ResultFail, "listing recoveries failed: "+err.Error()
+		return check
+	}
+	if result == nil || len(result.Recoveries) == 0 {
+		return check
+	}
+	check.Result = ResultWarn
+	check.Reason = fmt.Sprintf("%d recoveries in progress", len(result.Recoveries))
+	for _, recovery := range result.Recoveries[:min(len(result.Recoveries), checker.MaxItems)] {
+		check.Items = append(check.Items, helpers.Deref(recovery.Name))
+	}
+	return check
+}
+
+func checkPendingUpgrade(upgrades []backuprecoveryv1.UpgradeInfo, err error, cluster Cluster) CheckResult {
+	check := CheckResult{Name: CheckPendingUpgrade, Result: ResultPass}
+	if err != nil {
+		check.Result, check.Reason = ResultFail, "listing upgrades failed: "+err.Error()
+		return check
+	}
+	for _, info := range upgrades {
+		if helpers.Deref(info.ClusterID) != cluster.ID || helpers.Deref(info.ClusterIncarnationID) != cluster.IncarnationID {
+			continue
+		}
+		version := helpers.Deref(info.SoftwareVersion)
+		switch helpers.Deref(info.UpgradeStatus) {
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Inprogress:
+			check.Result, check.Reason = ResultFail, "upgrade to "+version+" in progress"
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Scheduled:
+			check.Result, check.Reason = ResultWarn, "upgrade to "+version+" already scheduled"
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Failed:
+			check.Result, check.Reason = ResultWarn, "previous upgrade to "+version+" failed"
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Clusterunreachable:
+			check.Result, check.Reason = ResultFail, "cluster unreachable"
+		}
+	}
+	return check
+}
@@ -0,0 +1,146 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePreflightClient struct {
+	fakeClient
+	criticalAlerts map[string][]string
+	alertsErr      error
+}
+
+func (fake *fakePreflightClient) GetManagementAlertsWithContext(ctx context.Context, options *backuprecoveryv1.GetManagementAlertsOptions) (*backuprecoveryv1.AlertsList, *core.DetailedResponse, error) {
+	if fake.alertsErr != nil {
+		return nil, nil, fake.alertsErr
+	}
+	result := &backuprecoveryv1.AlertsList{}
+	for _, name := range fake.criticalAlerts[options.ClusterIdentifiers[0]] {
+		result.AlertsList = append(result.AlertsList, backuprecoveryv1.Alert{
+			ID:            core.StringPtr(name),
+			AlertDocument: &backuprecoveryv1.AlertDocument{AlertName: core.StringPtr(name)},
+		})
+	}
+	return result, nil, nil
+}
+
+type fakeWorkload struct {
+	running    []string
+	recovering []string
+}
+
+func (fake *fakeWorkload) GetProtectionGroupsWithContext(ctx context.Context, options *backuprecoveryv1.GetProtectionGroupsOptions) (*backuprecoveryv1.ProtectionGroupsResponse, *core.DetailedResponse, error) {
+	result := &backuprecoveryv1.ProtectionGroupsResponse{}
+	for _, name := range fake.running {
+		result.ProtectionGroups = append(result.ProtectionGroups, backuprecoveryv1.ProtectionGroupResponse{Name: core.StringPtr(name)})
+	}
+	return result, nil, nil
+}
+
+func (fake *fakeWorkload) GetRecoveriesWithContext(ctx context.Context, options *backuprecoveryv1.GetRecoveriesOptions) (*backuprecoveryv1.RecoveriesResponse, *core.DetailedResponse, error) {
+	result := &backuprecoveryv1.RecoveriesResponse{}
+	for _, name := range fake.recovering {
+		result.Recoveries = append(result.Recoveries, backuprecoveryv1.Recovery{Name: core.StringPtr(name)})
+	}
+	return result, nil, nil
+}
+
+func check(t *testing.T, preflight ClusterPreflight, name string) CheckResult {
+	for _, result := range preflight.Checks {
+		if result.Name == name {
+			return result
+		}
+	}
+	t.Fatalf("no %s check for %s", name, preflight.Cluster)
+	return CheckResult{}
+}
+
+func TestPreflightMatrix(t *testing.T) {
+	client := &fakePreflightClient{
+		fakeClient:     fakeClient{compatible: []int64{1, 2}, status: map[int64]string{2: backuprecoveryv1.UpgradeInfo_UpgradeStatus_Scheduled}},
+		criticalAlerts: map[string][]string{"1:101": {"DiskFailure"}},
+	}
+	checker := NewChecker(client)
+	checker.Workloads[1] = Workload{Client: &fakeWorkload{}, TenantID: "tenant"}
+	checker.Workloads[2] = Workload{Client: &fakeWorkload{running: []string{"files"}, recovering: []string{"restore-db"}}, TenantID: "tenant"}
+
+	clusters := []Cluster{{ID: 1, IncarnationID: 101, Name: "prod"}, {ID: 2, IncarnationID: 102, Name: "dr"}, {ID: 3, IncarnationID: 103, Name: "lab"}}
+	report, err := checker.Check(context.Background(), "7.2", clusters)
+	require.Nil(t, err)
+	require.Len(t, report.Clusters, 3)
+
+	prod, dr, lab := report.Clusters[0], report.Clusters[1], report.Clusters[2]
+	assert.Equal(t, ResultFail, prod.Result)
+	assert.Equal(t, []string{"DiskFailure"}, check(t, prod, CheckCriticalAlerts).Items)
+	assert.Equal(t, ResultPass, check(t, prod, CheckProtectionRuns).Result)
+
+	assert.Equal(t, ResultWarn, dr.Result)
+	assert.Equal(t, []string{"files"}, check(t, dr, CheckProtectionRuns).Items)
+	assert.Equal(t, []string{"restore-db"}, check(t, dr, CheckRecoveries).Items)
+	assert.Equal(t, "upgrade to 7.2_release-20260601 already scheduled", check(t, dr, CheckPendingUpgrade).Reason)
+
+	assert.Equal(t, ResultFail, check(t, lab, CheckCompatible).Result)
+	assert.Equal(t, ResultWarn, check(t, lab, CheckRecoveries).Result)
+
+	assert.Equal(t, ResultFail, report.Result())
+	assert.Len(t, report.Blocked(false), 2)
+	assert.Len(t, report.Blocked(true), 3)
+
+	var matrix strings.Builder
+	require.Nil(t, report.WriteMatrix(&matrix))
+	assert.Contains(t, matrix.String(), "CLUSTER  RESULT  COMPATIBLE")
+	assert.Contains(t, matrix.String(), "prod fail critical_alerts: 1 critical open alerts (DiskFailure)")
+}
+
+func TestPreflightFailsWhenChecksCannotRun(t *testing.T) {
+	client := &fakePreflightClient{fakeClient: fakeClient{compatible: []int64{1}}, alertsErr: errors.New("unavailable")}
+	report, err := NewChecker(client).Check(context.Background(), "7.2", []Cluster{{ID: 1, IncarnationID: 101}})
+	require.Nil(t, err)
+	assert.Equal(t, ResultFail, check(t, report.Clusters[0], CheckCriticalAlerts).Result)
+}
+
+func TestGateBlocksWave(t *testing.T) {
+	client := &fakePreflightClient{
+		fakeClient:     fakeClient{compatible: []int64{1, 2}},
+		criticalAlerts: map[string][]string{"2:102": {"NodeDown"}},
+	}
+	checker := NewChecker(client)
+	for _, id := range []int64{1, 2} {
+		checker.Workloads[id] = Workload{Client: &fakeWorkload{}, TenantID: "tenant"}
+	}
+	orchestrator := newOrchestrator(t, &client.fakeClient)
+	var reports []*PreflightReport
+	orchestrator.BeforeWave = checker.Gate(false, func(report *PreflightReport) { reports = append(reports, report) })
+	_, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2"})
+	require.Nil(t, err)
+
+	state, err := orchestrator.Run(context.Background())
+	require.True(t, errors.Is(err, ErrHalted))
+	assert.Contains(t, state.HaltReason, "wave 1 blocked: pre-flight checks failed for 2:102 (critical_alerts: 1 critical open alerts)")
+	assert.Equal(t, [][]int64{{1}}, client.created)
+	assert.Len(t, reports, 2)
+}
@@ -0,0 +1,754 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package upgrade rolls a software release out to a fleet of clusters in waves through the management SRE API.
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+)
+
+// Client is the subset of the management SRE API used by this package.
+type Client interface {
+	CompatibleClustersForReleaseWithContext(ctx context.Context, compatibleClustersForReleaseOptions *backuprecoveryv1.CompatibleClustersForReleaseOptions) (result []backuprecoveryv1.CompatibleCluster, response *core.DetailedResponse, err error)
+	CreateClustersUpgradesWithContext(ctx context.Context, createClustersUpgradesOptions *backuprecoveryv1.CreateClustersUpgradesOptions) (result []backuprecoveryv1.UpgradeResponse, response *core.DetailedResponse, err error)
+	ClustersUpgradesInfoWithContext(ctx context.Context, clustersUpgradesInfoOptions *backuprecoveryv1.ClustersUpgradesInfoOptions) (result []backuprecoveryv1.UpgradeInfo, response *core.DetailedResponse, err error)
+	UpdateClustersUpgradesWithContext(ctx context.Context, updateClustersUpgradesOptions *backuprecoveryv1.UpdateClustersUpgradesOptions) (result []backuprecoveryv1.UpgradeResponse, response *core.DetailedResponse, err error)
+	DeleteClustersUpgradesWithContext(ctx context.Context, deleteClustersUpgradesOptions *backuprecoveryv1.DeleteClustersUpgradesOptions) (result []backuprecoveryv1.UpgradeCancelResponse, response *core.DetailedResponse, err error)
+}
+
+var _ Client = (*backuprecoveryv1.BackupRecoveryManagementSreApiV1)(nil)
+
+// Cluster identifies a cluster by id and incarnation id.
+type Cluster struct {
+	ID             int64  `json:"id"`
+	IncarnationID  int64  `json:"incarnationId"`
+	Name           string `json:"name,omitempty"`
+	CurrentVersion string `json:"currentVersion,omitempty"`
+}
+
+// Identifier returns the clusterId:clusterIncarnationId form used by the upgrade APIs.
+func (cluster Cluster) Identifier() string {
+	return fmt.Sprintf("%d:%d", cluster.ID, cluster.IncarnationID)
+}
+
+func (cluster Cluster) String() string {
+	if cluster.Name != "" {
+		return cluster.Name
+	}
+	return cluster.Identifier()
+}
+
+// Status is the progress of the upgrade of a cluster or a wave.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	// StatusScheduling marks the clusters whose upgrades are being created. It is saved before the upgrades are
+	// requested, so a rollout interrupted in between checks which upgrades exist instead of creating them again.
+	StatusScheduling Status = "scheduling"
+	StatusScheduled  Status = "scheduled"
+	StatusRunning    Status = "running"
+	StatusSucceeded  Status = "succeeded"
+	StatusFailed     Status = "failed"
+	StatusCancelled  Status = "cancelled"
+)
+
+// Finished reports whether the status is final.
+func (status Status) Finished() bool {
+	return status == StatusSucceeded || status == StatusFailed || status == StatusCancelled
+}
+
+// ClusterState is the upgrade progress of one cluster.
+type ClusterState struct {
+	Cluster         Cluster   `json:"cluster"`
+	Status          Status    `json:"status"`
+	PercentComplete float64   `json:"percentComplete,omitempty"`
+	Message         string    `json:"message,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt,omitempty"`
+
+	// UpgradeAt is the time the upgrade was moved to by Reschedule. It is zero when the upgrade starts as soon as it
+	// is scheduled.
+	UpgradeAt time.Time `json:"upgradeAt,omitempty"`
+}
+
+// Wave is a set of clusters upgraded together.
+type Wave struct {
+	Index      int            `json:"index"`
+	Canary     bool           `json:"canary,omitempty"`
+	Status     Status         `json:"status"`
+	Clusters   []ClusterState `json:"clusters"`
+	StartedAt  time.Time      `json:"startedAt,omitempty"`
+	FinishedAt time.Time      `json:"finishedAt,omitempty"`
+}
+
+func (wave *Wave) identifiers(statuses ...Status) []string {
+	var identifiers []string
+	for _, cluster := range wave.Clusters {
+		for _, status := range statuses {
+			if cluster.Status == status {
+				identifiers = append(identifiers, cluster.Cluster.Identifier())
+				break
+			}
+		}
+	}
+	return identifiers
+}
+
+// SkippedCluster is a requested cluster that is not part of the rollout.
+type SkippedCluster struct {
+	Cluster Cluster `json:"cluster"`
+	Reason  string  `json:"reason"`
+}
+
+// Request describes a rollout.
+type Request struct {
+	TargetVersion string `json:"targetVersion"`
+
+	// Type is one of the CreateClustersUpgradesOptions_Type_* constants. Defaults to Upgrade.
+	Type string `json:"type,omitempty"`
+
+	// PackageURL is the location of the release package, if the service cannot resolve it from the version.
+	PackageURL string `json:"packageUrl,omitempty"`
+
+	// Clusters restricts the rollout to these clusters, matched by id. All compatible clusters are upgraded when
+	// empty.
+	Clusters []Cluster `json:"clusters,omitempty"`
+
+	// CanarySize is the number of clusters of the first wave. Defaults to 1.
+	CanarySize int `json:"canarySize,omitempty"`
+
+	// BatchSize is the number of clusters of every later wave. Defaults to 5.
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// Interval spaces the upgrades of the clusters within a wave. It is sent as IntervalForRollingUpgradeInHours
+	// and rounded up to whole hours; zero upgrades the clusters of a wave at the same time.
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Soak is the time to wait after a wave finished before the next one starts.
+	Soak time.Duration `json:"soak,omitempty"`
+}
+
+// State is the persisted progress of a rollout. It is saved after every change so that an interrupted rollout can
+// be resumed with Run.
+type State struct {
+	Request    Request          `json:"request"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	Waves      []*Wave          `json:"waves"`
+	Skipped    []SkippedCluster `json:"skipped,omitempty"`
+	Halted     bool             `json:"halted,omitempty"`
+	HaltReason string           `json:"haltReason,omitempty"`
+}
+
+// Finished reports whether every wave is finished.
+func (state *State) Finished() bool {
+	for _, wave := range state.Waves {
+		if !wave.Status.Finished() {
+			return false
+		}
+	}
+	return true
+}
+
+// ClearHalt allows a halted rollout to be resumed. The failed and cancelled clusters of the halted wave are
+// upgraded again.
+func (state *State) ClearHalt() {
+	state.Halted, state.HaltReason = false, ""
+	for _, wave := range state.Waves {
+		if wave.Status != StatusFailed && wave.Status != StatusCancelled {
+			continue
+		}
+		wave.Status, wave.FinishedAt = StatusPending, time.Time{}
+		for i := range wave.Clusters {
+			if cluster := &wave.Clusters[i]; cluster.Status == StatusFailed || cluster.Status == StatusCancelled {
+				cluster.Status, cluster.Message = StatusPending, ""
+			}
+		}
+		return
+	}
+}
+
+// ErrHalted is returned by Run when a wave failed, or when the state was already halted.
+var ErrHalted = errors.New("rollout halted")
+
+// Orchestrator plans and runs rollouts.
+type Orchestrator struct {
+	client Client
+	file   helpers.JSONFile[State]
+
+	// PollInterval is the delay between progress checks. Defaults to five minutes.
+	PollInterval time.Duration
+
+	// WaveTimeout fails a wave that is not finished this long after it started. Defaults to 24 hours.
+	WaveTimeout time.Duration
+
+	// CancelOnFailure cancels the upgrades of a failed wave that have not started yet. Defaults to true.
+	CancelOnFailure bool
+
+	// BeforeWave, when set, is called before a wave is scheduled, e.g. the Gate of a pre-flight Checker. An error
+	// halts the rollout without scheduling the wave.
+	BeforeWave func(ctx context.Context, state *State, wave *Wave) error
+
+	// OnChange, when set, is called after every saved change of the state.
+	OnChange func(state *State)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewOrchestrator : Instantiate Orchestrator. The state of the rollout is kept in the file at statePath.
+func NewOrchestrator(client Client, statePath string) *Orchestrator {
+	return &Orchestrator{
+		client:          client,
+		file:            helpers.JSONFile[State]{Path: statePath},
+		PollInterval:    5 * time.Minute,
+		WaveTimeout:     24 * time.Hour,
+		CancelOnFailure: true,
+		Now:             time.Now,
+	}
+}
+
+// Compatible returns the clusters that can be upgraded to the version.
+func (orchestrator *Orchestrator) Compatible(ctx context.Context, version string) ([]Cluster, error) {
+	result, _, err := orchestrator.client.CompatibleClustersForReleaseWithContext(ctx, &backuprecoveryv1.CompatibleClustersForReleaseOptions{
+		ReleaseVersion: core.StringPtr(version),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing clusters compatible with '%s': %w", version, err)
+	}
+	clusters := make([]Cluster, 0, len(result))
+	for _, compatible := range result {
+		clusters = append(clusters, Cluster{
+			ID:             helpers.Deref(compatible.ClusterID),
+			IncarnationID:  helpers.Deref(compatible.ClusterIncarnationID),
+			Name:           helpers.Deref(compatible.ClusterName),
+			CurrentVersion: helpers.Deref(compatible.CurrentVersion),
+		})
+	}
+	return clusters, nil
+}
+
+// Plan splits the compatible clusters of the request into waves and saves the new state. Requested clusters that
+// are not compatible with the target version are listed as skipped. Plan refuses to replace the state of an
+// unfinished rollout.
+func (orchestrator *Orchestrator) Plan(ctx context.Context, request Request) (*State, error) {
+	if request.TargetVersion == "" {
+		return nil, errors.New("target version is required")
+	}
+	existing, err := orchestrator.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && !existing.Finished() {
+		return nil, fmt.Errorf("state '%s' holds an unfinished rollout to '%s'", orchestrator.file.Path, existing.Request.TargetVersion)
+	}
+	if request.Type == "" {
+		request.Type = backuprecoveryv1.CreateClustersUpgradesOptions_Type_Upgrade
+	}
+	if request.CanarySize <= 0 {
+		request.CanarySize = 1
+	}
+	if request.BatchSize <= 0 {
+		request.BatchSize = 5
+	}
+
+	compatible, err := orchestrator.Compatible(ctx, request.TargetVersion)
+	if err != nil {
+		return nil, err
+	}
+	state := &State{Request: request, CreatedAt: orchestrator.Now().UTC()}
+	clusters := compatible
+	if len(request.Clusters) > 0 {
+		byID := map[int64]Cluster{}
+		for _, cluster := range compatible {
+			byID[cluster.ID] = cluster
+		}
+		clusters = nil
+		for _, requested := range request.Clusters {
+			if cluster, ok := byID[requested.ID]; ok {
+				clusters = append(clusters, cluster)
+			} else {
+				state.Skipped = append(state.Skipped, SkippedCluster{Cluster: requested, Reason: "not compatible with " + request.TargetVersion})
+			}
+		}
+	}
+
+	size := request.CanarySize
+	for len(clusters) > 0 {
+		members := clusters[:min(size, len(clusters))]
+		clusters = clusters[len(members):]
+		wave := &Wave{Index: len(state.Waves), Canary: len(state.Waves) == 0, Status: StatusPending}
+		for _, cluster := range members {
+			wave.Clusters = append(wave.Clusters, ClusterState{Cluster: cluster, Status: StatusPending})
+		}
+		state.Waves = append(state.Waves, wave)
+		size = request.BatchSize
+	}
+	if err := orchestrator.save(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Load returns the saved state, or nil when there is none.
+func (orchestrator *Orchestrator) Load() (*State, error) {
+	return orchestrator.file.Load()
+}
+
+// Run upgrades the waves of the saved state one after the other and returns when every wave succeeded, a wave
+// failed or the context is cancelled. Waves that are already scheduled are monitored, not scheduled again, so Run
+// resumes an interrupted rollout.
+func (orchestrator *Orchestrator) Run(ctx context.Context) (*State, error) {
+	state, err := orchestrator.file.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no rollout planned in '%s'", orchestrator.file.Path)
+	}
+	if state.Halted {
+		return state, fmt.Errorf("%w: %s", ErrHalted, state.HaltReason)
+	}
+	for i, wave := range state.Waves {
+		if wave.Status.Finished() {
+			continue
+		}
+		if wave.Status == StatusPending {
+			if i > 0 {
+				if err := orchestrator.sleep(ctx, state.Waves[i-1].FinishedAt.Add(state.Request.Soak).Sub(orchestrator.Now())); err != nil {
+					return state, err
+				}
+			}
+			if orchestrator.BeforeWave != nil {
+				if err := orchestrator.BeforeWave(ctx, state, wave); err != nil {
+					return state, orchestrator.halt(state, fmt.Sprintf("wave %d blocked: %s", wave.Index, err))
+				}
+			}
+		}
+		if wave.Status == StatusPending || wave.Status == StatusScheduling {
+			if err := orchestrator.schedule(ctx, state, wave); err != nil {
+				return state, err
+			}
+		}
+		if err := orchestrator.monitor(ctx, state, wave); err != nil {
+			return state, err
+		}
+		if wave.Status != StatusSucceeded {
+			return state, orchestrator.failWave(ctx, state, wave)
+		}
+	}
+	return state, nil
+}
+
+// schedule creates the upgrades of the pending clusters of the wave. The clusters are saved as scheduling first; when
+// the wave is already scheduling, the upgrades created before the interruption are adopted and only the others are
+// created.
+func (orchestrator *Orchestrator) schedule(ctx context.Context, state *State, wave *Wave) error {
+	if wave.Status == StatusScheduling {
+		if err := orchestrator.adopt(ctx, state, wave); err != nil {
+			return err
+		}
+	} else {
+		for i := range wave.Clusters {
+			if cluster := &wave.Clusters[i]; cluster.Status == StatusPending {
+				cluster.Status = StatusScheduling
+			}
+		}
+		wave.Status = StatusScheduling
+		if err := orchestrator.save(state); err != nil {
+			return err
+		}
+	}
+
+	request := state.Request
+	options := &backuprecoveryv1.CreateClustersUpgradesOptions{
+		TargetVersion: core.StringPtr(request.TargetVersion),
+		Type:          core.StringPtr(request.Type),
+	}
+	if request.PackageURL != "" {
+		options.PackageURL = core.StringPtr(request.PackageURL)
+	}
+	if request.Interval > 0 {
+		options.IntervalForRollingUpgradeInHours = core.Int64Ptr(int64((request.Interval + time.Hour - 1) / time.Hour))
+	}
+	for _, cluster := range wave.Clusters {
+		if cluster.Status == StatusScheduling {
+			options.Clusters = append(options.Clusters, backuprecoveryv1.Upgrade{
+				ClusterID:            core.Int64Ptr(cluster.Cluster.ID),
+				ClusterIncarnationID: core.Int64Ptr(cluster.Cluster.IncarnationID),
+				CurrentVersion:       core.StringPtr(cluster.Cluster.CurrentVersion),
+			})
+		}
+	}
+	now := orchestrator.Now().UTC()
+	responses := map[string]backuprecoveryv1.UpgradeResponse{}
+	if len(options.Clusters) > 0 {
+		result, _, err := orchestrator.client.CreateClustersUpgradesWithContext(ctx, options)
+		if err != nil {
+			return fmt.Errorf("scheduling wave %d: %w", wave.Index, err)
+		}
+		for _, response := range result {
+			responses[fmt.Sprintf("%d:%d", helpers.Deref(response.ClusterID), helpers.Deref(response.ClusterIncarnationID))] = response
+		}
+	}
+	for i := range wave.Clusters {
+		cluster := &wave.Clusters[i]
+		if cluster.Status != StatusScheduling {
+			continue
+		}
+		cluster.Status, cluster.UpdatedAt = StatusScheduled, now
+		if response, ok := responses[cluster.Cluster.Identifier()]; ok && response.IsUpgradeSchedulingSuccessful != nil && !*response.IsUpgradeSchedulingSuccessful {
+			cluster.Status, cluster.Message = StatusFailed, helpers.Deref(response.ErrorMessage)
+		}
+	}
+	wave.Status, wave.StartedAt = StatusScheduled, now
+	return orchestrator.save(state)
+}
+
+// adopt marks the scheduling clusters of the wave that already report an upgrade to the target version as scheduled.
+func (orchestrator *Orchestrator) adopt(ctx context.Context, state *State, wave *Wave) error {
+	identifiers := wave.identifiers(StatusScheduling)
+	if len(identifiers) == 0 {
+		return nil
+	}
+	result, _, err := orchestrator.client.ClustersUpgradesInfoWithContext(ctx, &backuprecoveryv1.ClustersUpgradesInfoOptions{
+		ClusterIdentifiers: identifiers,
+	})
+	if err != nil {
+		return fmt.Errorf("checking upgrades of wave %d: %w", wave.Index, err)
+	}
+	now := orchestrator.Now().UTC()
+	for _, info := range result {
+		if helpers.Deref(info.UpgradeStatus) == "" || (!sameVersion(helpers.Deref(info.SoftwareVersion), state.Request.TargetVersion) && !sameVersion(helpers.Deref(info.PatchSoftwareVersion), state.Request.TargetVersion)) {
+			continue
+		}
+		identifier := fmt.Sprintf("%d:%d", helpers.Deref(info.ClusterID), helpers.Deref(info.ClusterIncarnationID))
+		for i := range wave.Clusters {
+			if cluster := &wave.Clusters[i]; cluster.Status == StatusScheduling && cluster.Cluster.Identifier() == identifier {
+				cluster.Status, cluster.UpdatedAt = StatusScheduled, now
+			}
+		}
+	}
+	return nil
+}
+
+// monitor polls the progress of the wave until every cluster finished, a cluster failed or the wave timed out.
+func (orchestrator *Orchestrator) monitor(ctx context.Context, state *State, wave *Wave) error {
+	for {
+		identifiers := wave.identifiers(StatusScheduled, StatusRunning)
+		if len(identifiers) > 0 {
+			result, _, err := orchestrator.client.ClustersUpgradesInfoWithContext(ctx, &backuprecoveryv1.ClustersUpgradesInfoOptions{
+				ClusterIdentifiers: identifiers,
+			})
+			if err != nil {
+				return fmt.Errorf("checking progress of wave %d: %w", wave.Index, err)
+			}
+			orchestrator.update(state, wave, result)
+		}
+
+		now := orchestrator.Now().UTC()
+		failed, finished := false, true
+		for i := range wave.Clusters {
+			cluster := &wave.Clusters[i]
+			started := wave.StartedAt
+			if cluster.UpgradeAt.After(started) {
+				started = cluster.UpgradeAt
+			}
+			if !cluster.Status.Finished() && orchestrator.WaveTimeout > 0 && now.Sub(started) > orchestrator.WaveTimeout {
+				cluster.Status, cluster.Message, cluster.UpdatedAt = StatusFailed, "timed out", now
+			}
+			failed = failed || cluster.Status == StatusFailed
+			finished = finished && cluster.Status.Finished()
+		}
+		switch {
+		case failed:
+			wave.Status, wave.FinishedAt = StatusFailed, now
+		case finished:
+			wave.Status, wave.FinishedAt = StatusSucceeded, now
+		}
+		if err := orchestrator.save(state); err != nil {
+			return err
+		}
+		if wave.Status.Finished() {
+			return nil
+		}
+		if err := orchestrator.sleep(ctx, orchestrator.PollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// update applies the reported progress to the clusters of the wave.
+func (orchestrator *Orchestrator) update(state *State, wave *Wave, infos []backuprecoveryv1.UpgradeInfo) {
+	now := orchestrator.Now().UTC()
+	byIdentifier := map[string]backuprecoveryv1.UpgradeInfo{}
+	for _, info := range infos {
+		byIdentifier[fmt.Sprintf("%d:%d", helpers.Deref(info.ClusterID), helpers.Deref(info.ClusterIncarnationID))] = info
+	}
+	for i := range wave.Clusters {
+		cluster := &wave.Clusters[i]
+		info, ok := byIdentifier[cluster.Cluster.Identifier()]
+		if !ok || cluster.Status.Finished() {
+			continue
+		}
+		// The progress of an earlier upgrade is reported until this one starts, so only a report for the target
+		// version counts.
+		if !sameVersion(helpers.Deref(info.SoftwareVersion), state.Request.TargetVersion) && !sameVersion(helpers.Deref(info.PatchSoftwareVersion), state.Request.TargetVersion) {
+			continue
+		}
+		status := cluster.Status
+		switch helpers.Deref(info.UpgradeStatus) {
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Complete:
+			status = StatusSucceeded
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Failed:
+			status = StatusFailed
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Inprogress:
+			status = StatusRunning
+		case backuprecoveryv1.UpgradeInfo_UpgradeStatus_Clusterunreachable:
+			cluster.Message = "cluster unreachable"
+		}
+		if info.UpgradePercentComplete != nil {
+			cluster.PercentComplete = *info.UpgradePercentComplete
+		}
+		if status != cluster.Status {
+			cluster.Status, cluster.UpdatedAt = status, now
+			if status == StatusFailed && cluster.Message == "" {
+				cluster.Message = "upgrade failed"
+			}
+		}
+	}
+}
+
+// failWave cancels the upgrades of the failed wave that have not started, if configured, and halts the rollout.
+func (orchestrator *Orchestrator) failWave(ctx context.Context, state *State, wave *Wave) error {
+	var failed []string
+	for _, cluster := range wave.Clusters {
+		if cluster.Status == StatusFailed {
+			failed = append(failed, cluster.Cluster.String())
+		}
+	}
+	reason := fmt.Sprintf("wave %d failed on %s", wave.Index, strings.Join(failed, ", "))
+	var cancelErr error
+	if orchestrator.CancelOnFailure {
+		cancelErr = orchestrator.cancel(ctx, state, wave)
+	}
+	return errors.Join(orchestrator.halt(state, reason), cancelErr)
+}
+
+// Cancel cancels the scheduled upgrades of the saved rollout and halts it. A wave is only marked cancelled once
+// none of its clusters is scheduled or running any more; the clusters whose upgrade could not be cancelled are
+// reported in the error.
+func (orchestrator *Orchestrator) Cancel(ctx context.Context) (*State, error) {
+	state, err := orchestrator.file.Load()
+	if err != nil || state == nil {
+		return state, err
+	}
+	var errs []error
+	for _, wave := range state.Waves {
+		if wave.Status == StatusScheduling {
+			// The clusters without an upgrade were never scheduled, so there is nothing to cancel for them.
+			if err := orchestrator.adopt(ctx, state, wave); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			now := orchestrator.Now().UTC()
+			for i := range wave.Clusters {
+				if cluster := &wave.Clusters[i]; cluster.Status == StatusScheduling {
+					cluster.Status, cluster.UpdatedAt = StatusCancelled, now
+				}
+			}
+			wave.Status, wave.StartedAt = StatusScheduled, now
+		}
+		if wave.Status != StatusScheduled {
+			continue
+		}
+		if err := orchestrator.cancel(ctx, state, wave); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var remaining []string
+		for _, cluster := range wave.Clusters {
+			if !cluster.Status.Finished() {
+				remaining = append(remaining, cluster.Cluster.String())
+			}
+		}
+		if len(remaining) > 0 {
+			errs = append(errs, fmt.Errorf("wave %d: upgrades of %s were not cancelled", wave.Index, strings.Join(remaining, ", ")))
+			continue
+		}
+		wave.Status, wave.FinishedAt = StatusCancelled, orchestrator.Now().UTC()
+	}
+	if err := orchestrator.halt(state, "cancelled"); !errors.Is(err, ErrHalted) {
+		errs = append(errs, err)
+	}
+	return state, errors.Join(errs...)
+}
+
+// cancel deletes the upgrades of the wave that are scheduled but not running yet.
+func (orchestrator *Orchestrator) cancel(ctx context.Context, state *State, wave *Wave) error {
+	identifiers := wave.identifiers(StatusScheduled)
+	if len(identifiers) == 0 {
+		return nil
+	}
+	result, _, err := orchestrator.client.DeleteClustersUpgradesWithContext(ctx, &backuprecoveryv1.DeleteClustersUpgradesOptions{
+		ClusterIdentifiers: identifiers,
+	})
+	if err != nil {
+		return fmt.Errorf("cancelling upgrades of wave %d: %w", wave.Index, err)
+	}
+	refused := map[string]string{}
+	for _, response := range result {
+		if response.IsUpgradeCancelSuccessful != nil && !*response.IsUpgradeCancelSuccessful {
+			refused[fmt.Sprintf("%d:%d", helpers.Deref(response.ClusterID), helpers.Deref(response.ClusterIncarnationID))] = helpers.Deref(response.ErrorMessage)
+		}
+	}
+	now := orchestrator.Now().UTC()
+	for i := range wave.Clusters {
+		cluster := &wave.Clusters[i]
+		if cluster.Status != StatusScheduled {
+			continue
+		}
+		if message, ok := refused[cluster.Cluster.Identifier()]; ok {
+			cluster.Message = "cancel refused: " + message
+			continue
+		}
+		cluster.Status, cluster.UpdatedAt = StatusCancelled, now
+	}
+	return orchestrator.save(state)
+}
+
+// Reschedule moves the not yet started upgrades of the running wave to a new time, e.g. to avoid a maintenance
+// freeze. The new time is saved with every cluster the service rescheduled; the clusters it refused keep their time
+// and are reported in the error.
+func (orchestrator *Orchestrator) Reschedule(ctx context.Context, at time.Time) (*State, error) {
+	state, err := orchestrator.file.Load()
+	if err != nil || state == nil {
+		return state, err
+	}
+	var errs []error
+	for _, wave := range state.Waves {
+		if wave.Status != StatusScheduled {
+			continue
+		}
+		options := &backuprecoveryv1.UpdateClustersUpgradesOptions{
+			TargetVersion:             core.StringPtr(state.Request.TargetVersion),
+			Type:                      core.StringPtr(state.Request.Type),
+			TimeStampToUpgradeAtMsecs: core.Int64Ptr(at.UnixMilli()),
+		}
+		for _, cluster := range wave.Clusters {
+			if cluster.Status == StatusScheduled {
+				options.Clusters = append(options.Clusters, backuprecoveryv1.Upgrade{
+					ClusterID:            core.Int64Ptr(cluster.Cluster.ID),
+					ClusterIncarnationID: core.Int64Ptr(cluster.Cluster.IncarnationID),
+					CurrentVersion:       core.StringPtr(cluster.Cluster.CurrentVersion),
+				})
+			}
+		}
+		if len(options.Clusters) == 0 {
+			continue
+		}
+		result, _, err := orchestrator.client.UpdateClustersUpgradesWithContext(ctx, options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rescheduling wave %d: %w", wave.Index, err))
+			break
+		}
+		refused := map[string]string{}
+		for _, response := range result {
+			if response.IsUpgradeSchedulingSuccessful != nil && !*response.IsUpgradeSchedulingSuccessful {
+				refused[fmt.Sprintf("%d:%d", helpers.Deref(response.ClusterID), helpers.Deref(response.ClusterIncarnationID))] = helpers.Deref(response.ErrorMessage)
+			}
+		}
+		now := orchestrator.Now().UTC()
+		for i := range wave.Clusters {
+			cluster := &wave.Clusters[i]
+			if cluster.Status != StatusScheduled {
+				continue
+			}
+			if message, ok := refused[cluster.Cluster.Identifier()]; ok {
+				cluster.Message = "reschedule refused: " + message
+				errs = append(errs, fmt.Errorf("rescheduling %s: %s", cluster.Cluster, message))
+				continue
+			}
+			cluster.UpgradeAt, cluster.UpdatedAt = at.UTC(), now
+		}
+	}
+	errs = append(errs, orchestrator.save(state))
+	return state, errors.Join(errs...)
+}
+
+func (orchestrator *Orchestrator) halt(state *State, reason string) error {
+	state.Halted, state.HaltReason = true, reason
+	if err := orchestrator.save(state); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrHalted, reason)
+}
+
+func (orchestrator *Orchestrator) save(state *State) error {
+	if err := orchestrator.file.Save(state); err != nil {
+		return fmt.Errorf("saving state '%s': %w", orchestrator.file.Path, err)
+	}
+	if orchestrator.OnChange != nil {
+		orchestrator.OnChange(state)
+	}
+	return nil
+}
+
+func (orchestrator *Orchestrator) sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sameVersion reports whether a reported software version is the target version. Reported versions may carry a
+// build suffix, e.g. 7.2.1_release-20260101.
+func sameVersion(reported string, target string) bool {
+	return reported != "" && (reported == target || strings.HasPrefix(reported, target+"_") || strings.HasPrefix(reported, target+"-"))
+}
+
+// ParseIdentifier parses the clusterId:clusterIncarnationId form.
+func ParseIdentifier(identifier string) (Cluster, error) {
+	id, incarnation, ok := strings.Cut(identifier, ":")
+	if !ok {
+		return Cluster{}, fmt.Errorf("invalid cluster identifier '%s'", identifier)
+	}
+	clusterID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Cluster{}, fmt.Errorf("invalid cluster identifier '%s': %w", identifier, err)
+	}
+	incarnationID, err := strconv.ParseInt(incarnation, 10, 64)
+	if err != nil {
+		return Cluster{}, fmt.Errorf("invalid cluster identifier '%s': %w", identifier, err)
+	}
+	return Cluster{ID: clusterID, IncarnationID: incarnationID}, nil
+}
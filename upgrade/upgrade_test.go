@@ -0,0 +1,349 @@
+/**
+ * (C) Copyright IBM Corp. 2026.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/backuprecoveryv1"
+	"github.com/IBM/ibm-backup-recovery-sdk-go/internal/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeClient struct {
+	compatible []int64
+	// status is the upgrade status reported for a cluster id once its upgrade was created. Complete by default.
+	status    map[int64]string
+	created   [][]int64
+	intervals []int64
+	cancelled []string
+	// refused are the cluster ids whose upgrades cannot be cancelled or rescheduled.
+	refused     map[int64]bool
+	rescheduled []int64
+	// missing are the cluster ids that have no upgrade until one is created. The others report one from the start.
+	missing map[int64]bool
+}
+
+func (fake *fakeClient) CompatibleClustersForReleaseWithContext(ctx context.Context, options *backuprecoveryv1.CompatibleClustersForReleaseOptions) ([]backuprecoveryv1.CompatibleCluster, *core.DetailedResponse, error) {
+	var clusters []backuprecoveryv1.CompatibleCluster
+	for _, id := range fake.compatible {
+		clusters = append(clusters, backuprecoveryv1.CompatibleCluster{
+			ClusterID:            core.Int64Ptr(id),
+			ClusterIncarnationID: core.Int64Ptr(100 + id),
+			CurrentVersion:       core.StringPtr("7.1"),
+		})
+	}
+	return clusters, nil, nil
+}
+
+func (fake *fakeClient) CreateClustersUpgradesWithContext(ctx context.Context, options *backuprecoveryv1.CreateClustersUpgradesOptions) ([]backuprecoveryv1.UpgradeResponse, *core.DetailedResponse, error) {
+	var ids []int64
+	var responses []backuprecoveryv1.UpgradeResponse
+	for _, cluster := range options.Clusters {
+		ids = append(ids, *cluster.ClusterID)
+		delete(fake.missing, *cluster.ClusterID)
+		responses = append(responses, backuprecoveryv1.UpgradeResponse{
+			ClusterID:                     cluster.ClusterID,
+			ClusterIncarnationID:          cluster.ClusterIncarnationID,
+			IsUpgradeSchedulingSuccessful: core.BoolPtr(true),
+		})
+	}
+	fake.created = append(fake.created, ids)
+	fake.intervals = append(fake.intervals, helpers.Deref(options.IntervalForRollingUpgradeInHours))
+	return responses, nil, nil
+}
+
+func (fake *fakeClient) ClustersUpgradesInfoWithContext(ctx context.Context, options *backuprecoveryv1.ClustersUpgradesInfoOptions) ([]backuprecoveryv1.UpgradeInfo, *core.DetailedResponse, error) {
+	var infos []backuprecoveryv1.UpgradeInfo
+	for _, identifier := range options.ClusterIdentifiers {
+		cluster, err := ParseIdentifier(identifier)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fake.missing[cluster.ID] {
+			infos = append(infos, backuprecoveryv1.UpgradeInfo{
+				ClusterID:            core.Int64Ptr(cluster.ID),
+				ClusterIncarnationID: core.Int64Ptr(cluster.IncarnationID),
+				SoftwareVersion:      core.StringPtr("7.1_release-20260101"),
+			})
+			continue
+		}
+		status := backuprecoveryv1.UpgradeInfo_UpgradeStatus_Complete
+		if reported, ok := fake.status[cluster.ID]; ok {
+			status = reported
+		}
+		infos = append(infos, backuprecoveryv1.UpgradeInfo{
+			ClusterID:            core.Int64Ptr(cluster.ID),
+			ClusterIncarnationID: core.Int64Ptr(cluster.IncarnationID),
+			SoftwareVersion:      core.StringPtr("7.2_release-20260601"),
+			UpgradeStatus:        core.StringPtr(status),
+		})
+	}
+	return infos, nil, nil
+}
+
+func (fake *fakeClient) UpdateClustersUpgradesWithContext(ctx context.Context, options *backuprecoveryv1.UpdateClustersUpgradesOptions) ([]backuprecoveryv1.UpgradeResponse, *core.DetailedResponse, error) {
+	var responses []backuprecoveryv1.UpgradeResponse
+	for _, cluster := range options.Clusters {
+		response := backuprecoveryv1.UpgradeResponse{
+			ClusterID:                     cluster.ClusterID,
+			ClusterIncarnationID:          cluster.ClusterIncarnationID,
+			IsUpgradeSchedulingSuccessful: core.BoolPtr(!fake.refused[*cluster.ClusterID]),
+		}
+		if fake.refused[*cluster.ClusterID] {
+			response.ErrorMessage = core.StringPtr("upgrade in progress")
+		} else {
+			fake.rescheduled = append(fake.rescheduled, *cluster.ClusterID)
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil, nil
+}
+
+func (fake *fakeClient) DeleteClustersUpgradesWithContext(ctx context.Context, options *backuprecoveryv1.DeleteClustersUpgradesOptions) ([]backuprecoveryv1.UpgradeCancelResponse, *core.DetailedResponse, error) {
+	fake.cancelled = append(fake.cancelled, options.ClusterIdentifiers...)
+	var responses []backuprecoveryv1.UpgradeCancelResponse
+	for _, identifier := range options.ClusterIdentifiers {
+		cluster, err := ParseIdentifier(identifier)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fake.refused[cluster.ID] {
+			responses = append(responses, backuprecoveryv1.UpgradeCancelResponse{
+				ClusterID:                 core.Int64Ptr(cluster.ID),
+				ClusterIncarnationID:      core.Int64Ptr(cluster.IncarnationID),
+				IsUpgradeCancelSuccessful: core.BoolPtr(false),
+				ErrorMessage:              core.StringPtr("upgrade in progress"),
+			})
+		}
+	}
+	return responses, nil, nil
+}
+
+func newOrchestrator(t *testing.T, client *fakeClient) *Orchestrator {
+	orchestrator := NewOrchestrator(client, filepath.Join(t.TempDir(), "rollout.json"))
+	orchestrator.PollInterval = time.Millisecond
+	orchestrator.Now = func() time.Time { return base }
+	return orchestrator
+}
+
+func TestPlanFiltersCompatibleClusters(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2, 3, 4}}
+	orchestrator := newOrchestrator(t, client)
+
+	state, err := orchestrator.Plan(context.Background(), Request{
+		TargetVersion: "7.2",
+		Clusters:      []Cluster{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 9}},
+		BatchSize:     2,
+	})
+	require.Nil(t, err)
+	require.Len(t, state.Waves, 2)
+	assert.True(t, state.Waves[0].Canary)
+	assert.Equal(t, "1:101", state.Waves[0].Clusters[0].Cluster.Identifier())
+	assert.Len(t, state.Waves[1].Clusters, 2)
+	require.Len(t, state.Skipped, 1)
+	assert.Equal(t, int64(9), state.Skipped[0].Cluster.ID)
+
+	_, err = orchestrator.Plan(context.Background(), Request{TargetVersion: "7.3"})
+	assert.NotNil(t, err)
+}
+
+func TestRunUpgradesInWaves(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2, 3}}
+	orchestrator := newOrchestrator(t, client)
+	_, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2", BatchSize: 5, Interval: 90 * time.Minute})
+	require.Nil(t, err)
+
+	var blocked []int
+	orchestrator.BeforeWave = func(ctx context.Context, state *State, wave *Wave) error {
+		blocked = append(blocked, wave.Index)
+		return nil
+	}
+	state, err := orchestrator.Run(context.Background())
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, [][]int64{{1}, {2, 3}}, client.created)
+	assert.Equal(t, []int64{2, 2}, client.intervals)
+	assert.Equal(t, []int{0, 1}, blocked)
+}
+
+func TestRunHaltsOnFailureAndResumes(t *testing.T) {
+	client := &fakeClient{
+		compatible: []int64{1, 2, 3, 4},
+		status:     map[int64]string{2: backuprecoveryv1.UpgradeInfo_UpgradeStatus_Failed, 3: backuprecoveryv1.UpgradeInfo_UpgradeStatus_Scheduled},
+	}
+	orchestrator := newOrchestrator(t, client)
+	_, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2", BatchSize: 2})
+	require.Nil(t, err)
+
+	state, err := orchestrator.Run(context.Background())
+	require.True(t, errors.Is(err, ErrHalted))
+	assert.True(t, state.Halted)
+	assert.Equal(t, StatusFailed, state.Waves[1].Status)
+	assert.Equal(t, StatusPending, state.Waves[2].Status)
+	assert.Equal(t, []string{"3:103"}, client.cancelled)
+	assert.Equal(t, StatusCancelled, state.Waves[1].Clusters[1].Status)
+
+	// A restarted orchestrator sees the halt in the state file.
+	restarted := newOrchestrator(t, client)
+	restarted.file = orchestrator.file
+	_, err = restarted.Run(context.Background())
+	require.True(t, errors.Is(err, ErrHalted))
+	assert.Len(t, client.created, 2)
+
+	// Once cleared, only the failed wave and the remaining ones are upgraded.
+	state.ClearHalt()
+	require.Nil(t, restarted.file.Save(state))
+	client.status = nil
+	state, err = restarted.Run(context.Background())
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, [][]int64{{1}, {2, 3}, {2, 3}, {4}}, client.created)
+}
+
+func TestRunResumesScheduledWave(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2}}
+	orchestrator := newOrchestrator(t, client)
+	state, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2"})
+	require.Nil(t, err)
+
+	// The canary was scheduled before the orchestrator was interrupted.
+	state.Waves[0].Status = StatusScheduled
+	state.Waves[0].Clusters[0].Status = StatusScheduled
+	require.Nil(t, orchestrator.file.Save(state))
+
+	state, err = orchestrator.Run(context.Background())
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, [][]int64{{2}}, client.created)
+}
+
+func TestRunAdoptsUpgradesOfSchedulingWave(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2, 3}, missing: map[int64]bool{3: true}}
+	orchestrator := newOrchestrator(t, client)
+	state, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2", CanarySize: 2})
+	require.Nil(t, err)
+
+	// The orchestrator was interrupted while creating the upgrades of the canary: only the one of cluster 1 exists.
+	state.Waves[0].Status = StatusScheduling
+	for i := range state.Waves[0].Clusters {
+		state.Waves[0].Clusters[i].Status = StatusScheduling
+	}
+	client.missing[2] = true
+	require.Nil(t, orchestrator.file.Save(state))
+
+	state, err = orchestrator.Run(context.Background())
+	require.Nil(t, err)
+	assert.True(t, state.Finished())
+	assert.Equal(t, [][]int64{{2}, {3}}, client.created)
+}
+
+func TestRunTimesOutWave(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1}, status: map[int64]string{1: backuprecoveryv1.UpgradeInfo_UpgradeStatus_Inprogress}}
+	orchestrator := newOrchestrator(t, client)
+	now := base
+	orchestrator.Now = func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}
+	orchestrator.WaveTimeout = 3 * time.Hour
+	_, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2"})
+	require.Nil(t, err)
+
+	state, err := orchestrator.Run(context.Background())
+	require.True(t, errors.Is(err, ErrHalted))
+	assert.Equal(t, "timed out", state.Waves[0].Clusters[0].Message)
+}
+
+// scheduledRollout plans a rollout of clusters 1 and 2 in one wave and marks the wave scheduled.
+func scheduledRollout(t *testing.T, orchestrator *Orchestrator) {
+	state, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2", CanarySize: 2})
+	require.Nil(t, err)
+	require.Len(t, state.Waves, 1)
+	state.Waves[0].Status = StatusScheduled
+	for i := range state.Waves[0].Clusters {
+		state.Waves[0].Clusters[i].Status = StatusScheduled
+	}
+	require.Nil(t, orchestrator.file.Save(state))
+}
+
+func TestCancelKeepsWaveWithUncancelledUpgrades(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2}, refused: map[int64]bool{2: true}}
+	orchestrator := newOrchestrator(t, client)
+	scheduledRollout(t, orchestrator)
+
+	state, err := orchestrator.Cancel(context.Background())
+	assert.ErrorContains(t, err, "wave 0: upgrades of 2:102 were not cancelled")
+	assert.True(t, state.Halted)
+	wave := state.Waves[0]
+	assert.Equal(t, StatusScheduled, wave.Status)
+	assert.Equal(t, StatusCancelled, wave.Clusters[0].Status)
+	assert.Equal(t, "cancel refused: upgrade in progress", wave.Clusters[1].Message)
+
+	// Once the remaining upgrade is cancelled, the wave is too.
+	client.refused = nil
+	state, err = orchestrator.Cancel(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, StatusCancelled, state.Waves[0].Status)
+
+	saved, err := orchestrator.file.Load()
+	require.Nil(t, err)
+	assert.Equal(t, StatusCancelled, saved.Waves[0].Status)
+}
+
+func TestRescheduleSavesTime(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2}, refused: map[int64]bool{2: true}}
+	orchestrator := newOrchestrator(t, client)
+	scheduledRollout(t, orchestrator)
+	at := base.Add(48 * time.Hour)
+
+	_, err := orchestrator.Reschedule(context.Background(), at)
+	assert.ErrorContains(t, err, "rescheduling 2:102: upgrade in progress")
+	assert.Equal(t, []int64{1}, client.rescheduled)
+
+	saved, err := orchestrator.file.Load()
+	require.Nil(t, err)
+	assert.Equal(t, at, saved.Waves[0].Clusters[0].UpgradeAt)
+	assert.True(t, saved.Waves[0].Clusters[1].UpgradeAt.IsZero())
+	assert.Equal(t, "reschedule refused: upgrade in progress", saved.Waves[0].Clusters[1].Message)
+}
+
+func TestCancelSchedulingWave(t *testing.T) {
+	client := &fakeClient{compatible: []int64{1, 2}, missing: map[int64]bool{2: true}}
+	orchestrator := newOrchestrator(t, client)
+	state, err := orchestrator.Plan(context.Background(), Request{TargetVersion: "7.2", CanarySize: 2})
+	require.Nil(t, err)
+	state.Waves[0].Status = StatusScheduling
+	for i := range state.Waves[0].Clusters {
+		state.Waves[0].Clusters[i].Status = StatusScheduling
+	}
+	require.Nil(t, orchestrator.file.Save(state))
+
+	state, err = orchestrator.Cancel(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1:101"}, client.cancelled)
+	assert.Equal(t, StatusCancelled, state.Waves[0].Status)
+	assert.Equal(t, StatusCancelled, state.Waves[0].Clusters[1].Status)
+}